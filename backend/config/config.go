@@ -3,16 +3,76 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"health-tracker/ratelimit"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Port           string
-	GinMode        string
-	JWTSecret      string
-	JWTExpiryHours int
-	DatabasePath   string
+	Port                           string
+	GinMode                        string
+	LogLevel                       string // debug, info, warn, error
+	LogFormat                      string // json, text
+	TracingExporter                string // none, otlp, stdout
+	OTLPEndpoint                   string // OTLP/HTTP collector, e.g. http://localhost:4318
+	TracingServiceName             string
+	TracingSampleRatio             float64 // fraction of new traces recorded, 0-1
+	RateLimitStore                 string  // memory, redis
+	RedisURL                       string
+	RateLimitGlobal                ratelimit.Limit // per client IP, across all routes
+	RateLimitUser                  ratelimit.Limit // per authenticated user
+	RateLimitAuth                  ratelimit.Limit // per client IP on login, register and password reset
+	TrustedProxies                 []string        // proxies allowed to report the client IP; empty trusts none
+	TrustedPlatform                string          // client IP header set by the hosting platform, e.g. CF-Connecting-IP
+	MetricsEnabled                 bool            // serve /metrics; off unless explicitly enabled
+	MetricsToken                   string          // bearer token required on /metrics; empty leaves an enabled endpoint open
+	CORSAllowedOrigins             []string        // exact origins, https://*.example.com wildcards, or *
+	CORSAllowedMethods             []string
+	CORSAllowCredentials           bool
+	CORSMaxAgeSeconds              int // how long browsers may cache preflight responses
+	HSTSMaxAgeSeconds              int // 0 disables Strict-Transport-Security
+	HSTSIncludeSubdomains          bool
+	ContentSecurityPolicy          string // the default lets JSON responses load nothing and never be framed
+	ShutdownTimeoutSeconds         int    // how long in-flight requests may drain after SIGTERM
+	BodyLimitDefaultKB             int    // request body cap for API routes without a specific limit
+	BodyLimitAuthKB                int
+	BodyLimitForumKB               int
+	BodyLimitImportKB              int  // multipart export archives on /account/import
+	StrictJSON                     bool // reject JSON bodies with fields the endpoint doesn't know
+	SymptomBatchMax                int  // most symptoms accepted by one /symptoms/batch request
+	LegacyRoutesEnabled            bool // serve the unversioned root paths as deprecated aliases of /v1
+	LegacyRoutesDeprecatedAt       time.Time
+	LegacyRoutesSunset             time.Time // zero omits the Sunset header
+	JWTSecret                      string
+	AccessTokenExpiryMinutes       int
+	RefreshTokenExpiryDays         int
+	DatabaseDriver                 string // sqlite, postgres
+	DatabaseURL                    string // sqlite file path or ":memory:", postgres DSN
+	DatabaseMaxOpenConns           int
+	DatabaseMaxIdleConns           int
+	DatabaseConnMaxLifetimeMinutes int
+	DatabaseLogLevel               string   // silent, error, warn, info
+	DatabaseAutoMigrate            bool     // apply pending migrations on startup
+	AdminEmails                    []string // promoted to admin on startup while no admin exists
+	FrontendURL                    string
+	PublicURL                      string // external base URL of the API for absolute links; empty gives relative links
+	PasswordResetExpiryMinutes     int
+	LoginMaxAttempts               int // failed logins before the account is locked
+	LoginLockoutBaseMinutes        int // first lockout; doubles with every further failure
+	LoginLockoutMaxMinutes         int
+	ExportDir                      string
+	ExportLinkExpiryHours          int
+	ExportAsyncThreshold           int    // exports with more rows than this run in the background
+	MailDriver                     string // log, file, smtp
+	MailFrom                       string
+	MailFileDir                    string
+	SMTPHost                       string
+	SMTPPort                       string
+	SMTPUsername                   string
+	SMTPPassword                   string
 }
 
 var AppConfig *Config
@@ -20,15 +80,111 @@ var AppConfig *Config
 func LoadConfig() {
 	godotenv.Load()
 
-	expiryHours, _ := strconv.Atoi(getEnv("JWT_EXPIRY_HOURS", "24"))
+	// DATABASE_URL falls back to the legacy DATABASE_PATH for sqlite
+	databaseURL := getEnv("DATABASE_URL", getEnv("DATABASE_PATH", "./health_tracker.db"))
+	databaseDriver := getEnv("DATABASE_DRIVER", defaultDatabaseDriver(databaseURL))
+	otlpEndpoint := getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	frontendURL := getEnv("FRONTEND_URL", "http://localhost:5173")
 
 	AppConfig = &Config{
-		Port:           getEnv("PORT", "8080"),
-		GinMode:        getEnv("GIN_MODE", "debug"),
-		JWTSecret:      getEnv("JWT_SECRET", "default-secret-key"),
-		JWTExpiryHours: expiryHours,
-		DatabasePath:   getEnv("DATABASE_PATH", "./health_tracker.db"),
+		Port:                           getEnv("PORT", "8080"),
+		GinMode:                        getEnv("GIN_MODE", "debug"),
+		LogLevel:                       getEnv("LOG_LEVEL", "info"),
+		LogFormat:                      getEnv("LOG_FORMAT", "json"),
+		TracingExporter:                getEnv("TRACING_EXPORTER", defaultTracingExporter(otlpEndpoint)),
+		OTLPEndpoint:                   otlpEndpoint,
+		TracingServiceName:             getEnv("OTEL_SERVICE_NAME", "health-tracker"),
+		TracingSampleRatio:             getEnvFloat("TRACING_SAMPLE_RATIO", 1),
+		RateLimitStore:                 getEnv("RATE_LIMIT_STORE", "memory"),
+		RedisURL:                       getEnv("REDIS_URL", ""),
+		RateLimitGlobal:                getEnvLimit("RATE_LIMIT_GLOBAL", "300/1m"),
+		RateLimitUser:                  getEnvLimit("RATE_LIMIT_USER", "100/1m"),
+		RateLimitAuth:                  getEnvLimit("RATE_LIMIT_AUTH", "10/1m"),
+		TrustedProxies:                 splitList(getEnv("TRUSTED_PROXIES", "")),
+		TrustedPlatform:                getEnv("TRUSTED_PLATFORM", ""),
+		MetricsEnabled:                 getEnv("METRICS_ENABLED", "false") == "true",
+		MetricsToken:                   getEnv("METRICS_TOKEN", ""),
+		CORSAllowedOrigins:             splitList(getEnv("CORS_ALLOWED_ORIGINS", defaultCORSOrigins(frontendURL))),
+		CORSAllowedMethods:             splitList(getEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS")),
+		CORSAllowCredentials:           getEnv("CORS_ALLOW_CREDENTIALS", "false") == "true",
+		CORSMaxAgeSeconds:              getEnvInt("CORS_MAX_AGE_SECONDS", 600),
+		HSTSMaxAgeSeconds:              getEnvInt("HSTS_MAX_AGE_SECONDS", 31536000),
+		HSTSIncludeSubdomains:          getEnv("HSTS_INCLUDE_SUBDOMAINS", "false") == "true",
+		ContentSecurityPolicy:          getEnv("CONTENT_SECURITY_POLICY", "default-src 'none'; frame-ancestors 'none'; base-uri 'none'; form-action 'none'"),
+		ShutdownTimeoutSeconds:         getEnvInt("SHUTDOWN_TIMEOUT_SECONDS", 20),
+		BodyLimitDefaultKB:             getEnvInt("BODY_LIMIT_DEFAULT_KB", 64),
+		BodyLimitAuthKB:                getEnvInt("BODY_LIMIT_AUTH_KB", 8),
+		BodyLimitForumKB:               getEnvInt("BODY_LIMIT_FORUM_KB", 32),
+		BodyLimitImportKB:              getEnvInt("BODY_LIMIT_IMPORT_KB", 50*1024),
+		StrictJSON:                     getEnv("STRICT_JSON", "true") == "true",
+		SymptomBatchMax:                getEnvInt("SYMPTOM_BATCH_MAX", 50),
+		LegacyRoutesEnabled:            getEnv("LEGACY_ROUTES_ENABLED", "true") == "true",
+		LegacyRoutesDeprecatedAt:       getEnvDate("LEGACY_ROUTES_DEPRECATED_AT", "2026-10-18"),
+		LegacyRoutesSunset:             getEnvDate("LEGACY_ROUTES_SUNSET", "2027-04-30"),
+		JWTSecret:                      getEnv("JWT_SECRET", "default-secret-key"),
+		AccessTokenExpiryMinutes:       getEnvInt("ACCESS_TOKEN_EXPIRY_MINUTES", 15),
+		RefreshTokenExpiryDays:         getEnvInt("REFRESH_TOKEN_EXPIRY_DAYS", 30),
+		DatabaseDriver:                 databaseDriver,
+		DatabaseURL:                    databaseURL,
+		DatabaseMaxOpenConns:           getEnvInt("DATABASE_MAX_OPEN_CONNS", 10),
+		DatabaseMaxIdleConns:           getEnvInt("DATABASE_MAX_IDLE_CONNS", 5),
+		DatabaseConnMaxLifetimeMinutes: getEnvInt("DATABASE_CONN_MAX_LIFETIME_MINUTES", 30),
+		DatabaseLogLevel:               getEnv("DATABASE_LOG_LEVEL", "warn"),
+		DatabaseAutoMigrate:            getEnv("DATABASE_AUTO_MIGRATE", "true") == "true",
+		AdminEmails:                    splitList(getEnv("ADMIN_EMAILS", "")),
+		FrontendURL:                    frontendURL,
+		PublicURL:                      strings.TrimSuffix(getEnv("PUBLIC_URL", ""), "/"),
+		PasswordResetExpiryMinutes:     getEnvInt("PASSWORD_RESET_EXPIRY_MINUTES", 30),
+		LoginMaxAttempts:               getEnvInt("LOGIN_MAX_ATTEMPTS", 5),
+		LoginLockoutBaseMinutes:        getEnvInt("LOGIN_LOCKOUT_BASE_MINUTES", 1),
+		LoginLockoutMaxMinutes:         getEnvInt("LOGIN_LOCKOUT_MAX_MINUTES", 60),
+		ExportDir:                      getEnv("EXPORT_DIR", "./exports"),
+		ExportLinkExpiryHours:          getEnvInt("EXPORT_LINK_EXPIRY_HOURS", 24),
+		ExportAsyncThreshold:           getEnvInt("EXPORT_ASYNC_THRESHOLD", 5000),
+		MailDriver:                     getEnv("MAIL_DRIVER", "log"),
+		MailFrom:                       getEnv("MAIL_FROM", "no-reply@health-tracker.local"),
+		MailFileDir:                    getEnv("MAIL_FILE_DIR", "./mail"),
+		SMTPHost:                       getEnv("SMTP_HOST", ""),
+		SMTPPort:                       getEnv("SMTP_PORT", "587"),
+		SMTPUsername:                   getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:                   getEnv("SMTP_PASSWORD", ""),
+	}
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
 	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvLimit reads a rate limit such as "100/1m" or "100/1m:20" (rate/period:burst)
+func getEnvLimit(key, defaultValue string) ratelimit.Limit {
+	if limit, err := ratelimit.ParseLimit(os.Getenv(key)); err == nil {
+		return limit
+	}
+	limit, _ := ratelimit.ParseLimit(defaultValue)
+	return limit
+}
+
+// getEnvDate reads a YYYY-MM-DD date; "none" yields the zero time
+func getEnvDate(key, defaultValue string) time.Time {
+	value := getEnv(key, defaultValue)
+	if value == "none" {
+		return time.Time{}
+	}
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date
+	}
+	date, _ := time.Parse("2006-01-02", defaultValue)
+	return date
 }
 
 func getEnv(key, defaultValue string) string {
@@ -37,3 +193,41 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// defaultDatabaseDriver infers the driver from the shape of DATABASE_URL
+func defaultDatabaseDriver(url string) string {
+	if strings.HasPrefix(url, "postgres://") || strings.HasPrefix(url, "postgresql://") || strings.Contains(url, "host=") {
+		return "postgres"
+	}
+	return "sqlite"
+}
+
+// defaultTracingExporter exports over OTLP only when a collector endpoint is configured
+func defaultTracingExporter(endpoint string) string {
+	if endpoint != "" {
+		return "otlp"
+	}
+	return "none"
+}
+
+// defaultCORSOrigins allows the frontend plus the local Vite and React dev servers
+func defaultCORSOrigins(frontendURL string) string {
+	return strings.Join([]string{
+		frontendURL,
+		"http://localhost:5173",
+		"http://localhost:5174",
+		"http://localhost:3000",
+		"http://127.0.0.1:5173",
+	}, ",")
+}
+
+// splitList parses a comma-separated environment value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
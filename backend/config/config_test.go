@@ -0,0 +1,30 @@
+package config
+
+import "testing"
+
+func TestDatabaseSettings(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		driver string
+		url    string
+	}{
+		{"default sqlite file", nil, "sqlite", "./health_tracker.db"},
+		{"legacy DATABASE_PATH", map[string]string{"DATABASE_PATH": "/data/app.db"}, "sqlite", "/data/app.db"},
+		{"postgres URL", map[string]string{"DATABASE_URL": "postgres://u:p@db:5432/app"}, "postgres", "postgres://u:p@db:5432/app"},
+		{"postgresql URL", map[string]string{"DATABASE_URL": "postgresql://db/app"}, "postgres", "postgresql://db/app"},
+		{"postgres DSN", map[string]string{"DATABASE_URL": "host=db user=u dbname=app"}, "postgres", "host=db user=u dbname=app"},
+		{"explicit driver wins", map[string]string{"DATABASE_DRIVER": "sqlite", "DATABASE_URL": "postgres://db/app"}, "sqlite", "postgres://db/app"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"DATABASE_DRIVER", "DATABASE_URL", "DATABASE_PATH"} {
+				t.Setenv(key, tt.env[key])
+			}
+			LoadConfig()
+			if AppConfig.DatabaseDriver != tt.driver || AppConfig.DatabaseURL != tt.url {
+				t.Errorf("driver %q, url %q; want %q, %q", AppConfig.DatabaseDriver, AppConfig.DatabaseURL, tt.driver, tt.url)
+			}
+		})
+	}
+}
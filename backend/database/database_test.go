@@ -0,0 +1,28 @@
+package database
+
+import "testing"
+
+func TestOpenDialector(t *testing.T) {
+	tests := []struct {
+		driver  string
+		url     string
+		name    string
+		wantErr bool
+	}{
+		{"sqlite", ":memory:", "sqlite", false},
+		{"sqlite", "./health_tracker.db", "sqlite", false},
+		{"postgres", "postgres://u:p@db:5432/app", "postgres", false},
+		{"postgres", "", "", true},
+		{"mysql", "mysql://db/app", "", true},
+	}
+	for _, tt := range tests {
+		dialector, err := openDialector(tt.driver, tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("openDialector(%q, %q) error = %v, want error %v", tt.driver, tt.url, err, tt.wantErr)
+			continue
+		}
+		if err == nil && dialector.Name() != tt.name {
+			t.Errorf("openDialector(%q, %q) = %s dialector, want %s", tt.driver, tt.url, dialector.Name(), tt.name)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+// Migration is one numbered schema change with its rollback
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// SchemaMigration records an applied migration
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// MigrationStatus describes a migration and whether it has been applied
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// SchemaState summarises how far the database is behind the embedded migrations
+type SchemaState struct {
+	Current int `json:"current"`
+	Latest  int `json:"latest"`
+	Pending int `json:"pending"`
+}
+
+// LoadMigrations reads the embedded migrations for a driver, ordered by version
+func LoadMigrations(driver string) ([]Migration, error) {
+	dir := path.Join("migrations", driver)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for driver %q", driver)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		// File names look like 0001_initial_schema.up.sql
+		name := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		prefix, label, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("invalid migration file name %q", name)
+		}
+
+		content, err := fs.ReadFile(migrationFiles, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// MigrateUp applies every pending migration and returns how many ran
+func MigrateUp(db *gorm.DB, driver string) (int, error) {
+	migrations, err := LoadMigrations(driver)
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return MigrateTo(db, driver, migrations[len(migrations)-1].Version)
+}
+
+// MigrateDown rolls back the given number of most recently applied migrations
+func MigrateDown(db *gorm.DB, driver string, steps int) (int, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return 0, err
+	}
+
+	versions := sortedVersions(applied)
+	target := 0
+	if steps < len(versions) {
+		target = versions[len(versions)-steps-1]
+	}
+	return MigrateTo(db, driver, target)
+}
+
+// MigrateTo applies or rolls back migrations until the schema is at the given version.
+// Version 0 rolls back everything.
+func MigrateTo(db *gorm.DB, driver string, target int) (int, error) {
+	migrations, err := LoadMigrations(driver)
+	if err != nil {
+		return 0, err
+	}
+
+	if target != 0 && !hasVersion(migrations, target) {
+		return 0, fmt.Errorf("unknown migration version %d", target)
+	}
+
+	if err := prepareMigrationTable(db, migrations); err != nil {
+		return 0, err
+	}
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+
+	// Roll back newest first
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.Version <= target || !applied[m.Version] {
+			continue
+		}
+		if err := runMigration(db, m, false); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	for _, m := range migrations {
+		if m.Version > target || applied[m.Version] {
+			continue
+		}
+		if err := runMigration(db, m, true); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// GetMigrationStatus lists every known migration with its applied time, if any
+func GetMigrationStatus(db *gorm.DB, driver string) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := prepareMigrationTable(db, migrations); err != nil {
+		return nil, err
+	}
+
+	var records []SchemaMigration
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	appliedAt := map[int]time.Time{}
+	for _, r := range records {
+		appliedAt[r.Version] = r.AppliedAt
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if t, ok := appliedAt[m.Version]; ok {
+			status.AppliedAt = &t
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// GetSchemaState compares applied migrations with those embedded in the binary.
+// Unlike GetMigrationStatus it never touches the schema, so it is safe to call from probes.
+func GetSchemaState(db *gorm.DB, driver string) (SchemaState, error) {
+	migrations, err := LoadMigrations(driver)
+	if err != nil {
+		return SchemaState{}, err
+	}
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return SchemaState{}, err
+	}
+
+	var state SchemaState
+	for _, m := range migrations {
+		state.Latest = m.Version
+		if applied[m.Version] {
+			state.Current = m.Version
+		} else {
+			state.Pending++
+		}
+	}
+	return state, nil
+}
+
+// runMigration executes one migration and updates schema_migrations in a single transaction
+func runMigration(db *gorm.DB, m Migration, up bool) error {
+	script, action := m.Down, "roll back"
+	if up {
+		script, action = m.Up, "apply"
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range splitStatements(script) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		if up {
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		}
+		return tx.Delete(&SchemaMigration{}, m.Version).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to %s migration %04d_%s: %w", action, m.Version, m.Name, err)
+	}
+	return nil
+}
+
+// prepareMigrationTable creates schema_migrations. Databases created by the old
+// AutoMigrate already have tables but no history, so matching migrations are
+// recorded as applied instead of being run again.
+func prepareMigrationTable(db *gorm.DB, migrations []Migration) error {
+	migrator := db.Migrator()
+	if migrator.HasTable(&SchemaMigration{}) {
+		return nil
+	}
+
+	if err := migrator.CreateTable(&SchemaMigration{}); err != nil {
+		return err
+	}
+
+	baseline := map[int]bool{
+		1: migrator.HasTable("users"),
+		2: migrator.HasTable("sessions") && migrator.HasColumn("users", "locked_until"),
+	}
+
+	for _, m := range migrations {
+		if !baseline[m.Version] {
+			break
+		}
+		if err := db.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appliedVersions(db *gorm.DB) (map[int]bool, error) {
+	applied := map[int]bool{}
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return applied, nil
+	}
+
+	var versions []int
+	if err := db.Model(&SchemaMigration{}).Pluck("version", &versions).Error; err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		applied[v] = true
+	}
+	return applied, nil
+}
+
+func sortedVersions(applied map[int]bool) []int {
+	versions := make([]int, 0, len(applied))
+	for v := range applied {
+		versions = append(versions, v)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+func hasVersion(migrations []Migration, version int) bool {
+	for _, m := range migrations {
+		if m.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// splitStatements breaks a migration script on semicolons that end a line.
+// Comment lines are dropped so they never become empty statements.
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+
+	"health-tracker/config"
+)
+
+const migrateUsage = "usage: migrate up | down [n] | status | to <version>"
+
+// RunMigrateCommand handles `./main migrate ...` without starting the server
+func RunMigrateCommand(args []string) error {
+	Connect()
+
+	driver := config.AppConfig.DatabaseDriver
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		count, err := MigrateUp(DB, driver)
+		fmt.Printf("Applied %d migration(s)\n", count)
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+			steps = n
+		}
+		count, err := MigrateDown(DB, driver, steps)
+		fmt.Printf("Rolled back %d migration(s)\n", count)
+		return err
+
+	case "to":
+		if len(args) < 2 {
+			return fmt.Errorf(migrateUsage)
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		count, err := MigrateTo(DB, driver, version)
+		fmt.Printf("Ran %d migration(s)\n", count)
+		return err
+
+	case "status":
+		statuses, err := GetMigrationStatus(DB, driver)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-30s %s\n", s.Version, s.Name, applied)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf(migrateUsage)
+	}
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB returns an empty in-memory SQLite database
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	return db
+}
+
+func TestLoadMigrationsMatchAcrossDrivers(t *testing.T) {
+	names := map[string][]string{}
+	for _, driver := range []string{"sqlite", "postgres"} {
+		migrations, err := LoadMigrations(driver)
+		if err != nil {
+			t.Fatalf("LoadMigrations(%s): %v", driver, err)
+		}
+		for i, m := range migrations {
+			if m.Version != i+1 {
+				t.Errorf("%s: migration %d has version %d", driver, i+1, m.Version)
+			}
+			if m.Up == "" || m.Down == "" {
+				t.Errorf("%s: %04d_%s is missing its up or down script", driver, m.Version, m.Name)
+			}
+			names[driver] = append(names[driver], m.Name)
+		}
+	}
+	if !reflect.DeepEqual(names["sqlite"], names["postgres"]) {
+		t.Errorf("sqlite migrations %v differ from postgres %v", names["sqlite"], names["postgres"])
+	}
+
+	if _, err := LoadMigrations("mysql"); err == nil {
+		t.Error("LoadMigrations(mysql) succeeded, want an error")
+	}
+}
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	db := openTestDB(t)
+	migrations, err := LoadMigrations("sqlite")
+	if err != nil {
+		t.Fatalf("LoadMigrations: %v", err)
+	}
+	latest := migrations[len(migrations)-1].Version
+
+	state := func() SchemaState {
+		t.Helper()
+		state, err := GetSchemaState(db, "sqlite")
+		if err != nil {
+			t.Fatalf("GetSchemaState: %v", err)
+		}
+		return state
+	}
+	if got := state(); got != (SchemaState{Latest: latest, Pending: latest}) {
+		t.Errorf("state of an empty database = %+v", got)
+	}
+
+	if n, err := MigrateUp(db, "sqlite"); err != nil || n != latest {
+		t.Fatalf("MigrateUp = %d, %v; want %d", n, err, latest)
+	}
+	if got := state(); got != (SchemaState{Current: latest, Latest: latest}) {
+		t.Errorf("state after up = %+v", got)
+	}
+	if n, err := MigrateUp(db, "sqlite"); err != nil || n != 0 {
+		t.Errorf("second MigrateUp = %d, %v; want 0", n, err)
+	}
+
+	if n, err := MigrateDown(db, "sqlite", 1); err != nil || n != 1 {
+		t.Fatalf("MigrateDown(1) = %d, %v", n, err)
+	}
+	if got := state(); got.Current != latest-1 || got.Pending != 1 {
+		t.Errorf("state after one step down = %+v", got)
+	}
+
+	// Every down script has to undo its up script, so the whole chain runs twice
+	if _, err := MigrateTo(db, "sqlite", 0); err != nil {
+		t.Fatalf("MigrateTo(0): %v", err)
+	}
+	if db.Migrator().HasTable("users") {
+		t.Error("users table still exists after rolling back everything")
+	}
+	if n, err := MigrateUp(db, "sqlite"); err != nil || n != latest {
+		t.Fatalf("MigrateUp after full rollback = %d, %v; want %d", n, err, latest)
+	}
+}
+
+func TestMigrationStatusBaselinesAutoMigratedDatabase(t *testing.T) {
+	db := openTestDB(t)
+	// A database created by AutoMigrate before versioned migrations had the tables but no history
+	if err := db.Exec("CREATE TABLE users (id integer PRIMARY KEY, email text)").Error; err != nil {
+		t.Fatalf("create users: %v", err)
+	}
+
+	statuses, err := GetMigrationStatus(db, "sqlite")
+	if err != nil {
+		t.Fatalf("GetMigrationStatus: %v", err)
+	}
+	for _, s := range statuses {
+		if applied := s.AppliedAt != nil; applied != (s.Version == 1) {
+			t.Errorf("migration %04d_%s applied = %v", s.Version, s.Name, applied)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := "-- create things\nCREATE TABLE a (\n  id integer\n);\n\nCREATE INDEX idx_a ON a (id);\n"
+	want := []string{"CREATE TABLE a (\n  id integer\n);", "CREATE INDEX idx_a ON a (id);"}
+	if got := splitStatements(script); !reflect.DeepEqual(got, want) {
+		t.Errorf("splitStatements = %q, want %q", got, want)
+	}
+}
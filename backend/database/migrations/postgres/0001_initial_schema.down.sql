@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS "reminders";
+DROP TABLE IF EXISTS "goals";
+DROP TABLE IF EXISTS "water_intakes";
+DROP TABLE IF EXISTS "likes";
+DROP TABLE IF EXISTS "comments";
+DROP TABLE IF EXISTS "posts";
+DROP TABLE IF EXISTS "articles";
+DROP TABLE IF EXISTS "recommendations";
+DROP TABLE IF EXISTS "family_members";
+DROP TABLE IF EXISTS "symptom_templates";
+DROP TABLE IF EXISTS "symptoms";
+DROP TABLE IF EXISTS "health_data";
+DROP TABLE IF EXISTS "users";
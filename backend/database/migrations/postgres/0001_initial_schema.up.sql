@@ -0,0 +1,145 @@
+-- Tables as they were created by AutoMigrate before versioned migrations
+
+CREATE TABLE "users" (
+  "id" bigserial PRIMARY KEY,
+  "email" text NOT NULL,
+  "password" text NOT NULL,
+  "name" text NOT NULL,
+  "birth_date" timestamptz,
+  "height_cm" decimal,
+  "weight_kg" decimal,
+  "activity_level" text DEFAULT 'sedentary',
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  CONSTRAINT "uni_users_email" UNIQUE ("email")
+);
+
+CREATE TABLE "health_data" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "weight_kg" decimal,
+  "height_cm" decimal,
+  "bmi" decimal,
+  "activity_level" text,
+  "emotional_state" text,
+  "daily_schedule" text,
+  "notes" text,
+  "record_date" timestamptz,
+  "created_at" timestamptz
+);
+
+CREATE TABLE "symptoms" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "symptom_type" text NOT NULL,
+  "symptom_name" text NOT NULL,
+  "severity" bigint,
+  "notes" text,
+  "logged_at" timestamptz
+);
+
+CREATE TABLE "symptom_templates" (
+  "id" bigserial PRIMARY KEY,
+  "symptom_type" text,
+  "symptom_name" text,
+  "description" text
+);
+
+CREATE TABLE "family_members" (
+  "id" bigserial PRIMARY KEY,
+  "owner_id" bigint NOT NULL,
+  "member_user_id" bigint NOT NULL,
+  "member_email" text NOT NULL,
+  "relationship" text,
+  "status" text DEFAULT 'pending',
+  "can_view_health" boolean DEFAULT true,
+  "created_at" timestamptz,
+  "updated_at" timestamptz
+);
+
+CREATE TABLE "recommendations" (
+  "id" bigserial PRIMARY KEY,
+  "category" text,
+  "condition" text,
+  "title" text,
+  "description" text,
+  "details" text,
+  "priority" bigint
+);
+
+CREATE TABLE "articles" (
+  "id" bigserial PRIMARY KEY,
+  "title" varchar(200) NOT NULL,
+  "content" text NOT NULL,
+  "summary" varchar(500),
+  "category" varchar(50) NOT NULL,
+  "image_url" varchar(500),
+  "read_time" bigint,
+  "created_at" timestamptz
+);
+
+CREATE TABLE "posts" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "title" varchar(200) NOT NULL,
+  "content" text NOT NULL,
+  "likes_count" bigint DEFAULT 0,
+  "comments_count" bigint DEFAULT 0,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  CONSTRAINT "fk_posts_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+
+CREATE TABLE "comments" (
+  "id" bigserial PRIMARY KEY,
+  "post_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "content" text NOT NULL,
+  "created_at" timestamptz,
+  CONSTRAINT "fk_comments_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+  CONSTRAINT "fk_posts_comments" FOREIGN KEY ("post_id") REFERENCES "posts"("id")
+);
+
+CREATE TABLE "likes" (
+  "id" bigserial PRIMARY KEY,
+  "post_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "created_at" timestamptz,
+  CONSTRAINT "fk_posts_likes" FOREIGN KEY ("post_id") REFERENCES "posts"("id")
+);
+
+CREATE TABLE "water_intakes" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "glasses" bigint DEFAULT 0,
+  "goal" bigint DEFAULT 8,
+  "date" varchar(10) NOT NULL,
+  "created_at" timestamptz,
+  "updated_at" timestamptz
+);
+
+CREATE TABLE "goals" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "title" varchar(200) NOT NULL,
+  "description" varchar(500),
+  "type" varchar(50) NOT NULL,
+  "target" decimal NOT NULL,
+  "current" decimal DEFAULT 0,
+  "unit" varchar(20),
+  "deadline" varchar(10),
+  "is_completed" boolean DEFAULT false,
+  "created_at" timestamptz,
+  "updated_at" timestamptz
+);
+
+CREATE TABLE "reminders" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "type" varchar(50) NOT NULL,
+  "label" varchar(200) NOT NULL,
+  "time" varchar(10) NOT NULL,
+  "is_active" boolean DEFAULT true,
+  "created_at" timestamptz,
+  "updated_at" timestamptz
+);
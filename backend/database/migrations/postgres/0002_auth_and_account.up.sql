@@ -0,0 +1,70 @@
+-- Sessions, password reset, 2FA, login history, data export and user roles
+
+ALTER TABLE "users" ADD COLUMN "role" varchar(20) DEFAULT 'user';
+ALTER TABLE "users" ADD COLUMN "suspended_at" timestamptz;
+ALTER TABLE "users" ADD COLUMN "suspended_reason" varchar(500);
+ALTER TABLE "users" ADD COLUMN "two_factor_enabled" boolean DEFAULT false;
+ALTER TABLE "users" ADD COLUMN "two_factor_secret" text;
+ALTER TABLE "users" ADD COLUMN "two_factor_last_step" bigint;
+ALTER TABLE "users" ADD COLUMN "failed_logins" bigint DEFAULT 0;
+ALTER TABLE "users" ADD COLUMN "last_failed_login_at" timestamptz;
+ALTER TABLE "users" ADD COLUMN "locked_until" timestamptz;
+
+CREATE TABLE "sessions" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "refresh_token_hash" varchar(64) NOT NULL,
+  "user_agent" varchar(255),
+  "ip_address" varchar(64),
+  "expires_at" timestamptz,
+  "revoked_at" timestamptz,
+  "last_used_at" timestamptz,
+  "created_at" timestamptz
+);
+CREATE UNIQUE INDEX "idx_sessions_refresh_token_hash" ON "sessions"("refresh_token_hash");
+CREATE INDEX "idx_sessions_user_id" ON "sessions"("user_id");
+
+CREATE TABLE "password_reset_tokens" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "token_hash" varchar(64) NOT NULL,
+  "expires_at" timestamptz,
+  "used_at" timestamptz,
+  "created_at" timestamptz
+);
+CREATE UNIQUE INDEX "idx_password_reset_tokens_token_hash" ON "password_reset_tokens"("token_hash");
+CREATE INDEX "idx_password_reset_tokens_user_id" ON "password_reset_tokens"("user_id");
+
+CREATE TABLE "recovery_codes" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "code_hash" varchar(64) NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz
+);
+CREATE INDEX "idx_recovery_codes_user_id" ON "recovery_codes"("user_id");
+
+CREATE TABLE "login_attempts" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint,
+  "email" varchar(255),
+  "ip_address" varchar(64),
+  "user_agent" varchar(255),
+  "success" boolean,
+  "reason" varchar(50),
+  "created_at" timestamptz
+);
+CREATE INDEX "idx_login_attempts_email" ON "login_attempts"("email");
+CREATE INDEX "idx_login_attempts_user_id" ON "login_attempts"("user_id");
+
+CREATE TABLE "export_jobs" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "status" varchar(20) NOT NULL,
+  "file_path" varchar(500),
+  "error" varchar(500),
+  "completed_at" timestamptz,
+  "expires_at" timestamptz,
+  "created_at" timestamptz
+);
+CREATE INDEX "idx_export_jobs_user_id" ON "export_jobs"("user_id");
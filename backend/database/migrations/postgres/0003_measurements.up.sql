@@ -0,0 +1,17 @@
+-- Vital sign measurements: blood pressure, heart rate, glucose, SpO2, temperature, cholesterol
+
+CREATE TABLE "measurements" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "type" varchar(30) NOT NULL,
+  "value" decimal,
+  "diastolic" decimal,
+  "context" varchar(20),
+  "unit" varchar(10),
+  "category" varchar(30),
+  "risk_level" bigint,
+  "notes" text,
+  "measured_at" timestamptz,
+  "created_at" timestamptz
+);
+CREATE INDEX "idx_measurements_user_type_measured_at" ON "measurements"("user_id", "type", "measured_at");
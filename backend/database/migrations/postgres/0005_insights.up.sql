@@ -0,0 +1,18 @@
+-- Stored trend, projection and anomaly insights
+
+CREATE TABLE "insights" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "kind" varchar(20) NOT NULL,
+  "metric" varchar(30) NOT NULL,
+  "severity" varchar(10) NOT NULL,
+  "title" varchar(200),
+  "message" varchar(500),
+  "value" decimal,
+  "fingerprint" varchar(100) NOT NULL,
+  "observed_at" timestamptz,
+  "read_at" timestamptz,
+  "created_at" timestamptz,
+  "updated_at" timestamptz
+);
+CREATE UNIQUE INDEX "idx_insights_user_fingerprint" ON "insights"("user_id", "fingerprint");
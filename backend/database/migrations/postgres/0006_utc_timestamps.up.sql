@@ -0,0 +1,2 @@
+-- timestamptz stores instants, so PostgreSQL rows need no rewrite; this keeps the
+-- migration versions of both drivers in step
@@ -0,0 +1,145 @@
+-- Tables as they were created by AutoMigrate before versioned migrations
+
+CREATE TABLE `users` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `email` text NOT NULL,
+  `password` text NOT NULL,
+  `name` text NOT NULL,
+  `birth_date` datetime,
+  `height_cm` real,
+  `weight_kg` real,
+  `activity_level` text DEFAULT "sedentary",
+  `created_at` datetime,
+  `updated_at` datetime,
+  CONSTRAINT `uni_users_email` UNIQUE (`email`)
+);
+
+CREATE TABLE `health_data` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `weight_kg` real,
+  `height_cm` real,
+  `bmi` real,
+  `activity_level` text,
+  `emotional_state` text,
+  `daily_schedule` text,
+  `notes` text,
+  `record_date` datetime,
+  `created_at` datetime
+);
+
+CREATE TABLE `symptoms` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `symptom_type` text NOT NULL,
+  `symptom_name` text NOT NULL,
+  `severity` integer,
+  `notes` text,
+  `logged_at` datetime
+);
+
+CREATE TABLE `symptom_templates` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `symptom_type` text,
+  `symptom_name` text,
+  `description` text
+);
+
+CREATE TABLE `family_members` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `owner_id` integer NOT NULL,
+  `member_user_id` integer NOT NULL,
+  `member_email` text NOT NULL,
+  `relationship` text,
+  `status` text DEFAULT "pending",
+  `can_view_health` numeric DEFAULT true,
+  `created_at` datetime,
+  `updated_at` datetime
+);
+
+CREATE TABLE `recommendations` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `category` text,
+  `condition` text,
+  `title` text,
+  `description` text,
+  `details` text,
+  `priority` integer
+);
+
+CREATE TABLE `articles` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `title` text NOT NULL,
+  `content` text NOT NULL,
+  `summary` text,
+  `category` text NOT NULL,
+  `image_url` text,
+  `read_time` integer,
+  `created_at` datetime
+);
+
+CREATE TABLE `posts` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `title` text NOT NULL,
+  `content` text NOT NULL,
+  `likes_count` integer DEFAULT 0,
+  `comments_count` integer DEFAULT 0,
+  `created_at` datetime,
+  `updated_at` datetime,
+  CONSTRAINT `fk_posts_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `comments` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `post_id` integer NOT NULL,
+  `user_id` integer NOT NULL,
+  `content` text NOT NULL,
+  `created_at` datetime,
+  CONSTRAINT `fk_comments_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `fk_posts_comments` FOREIGN KEY (`post_id`) REFERENCES `posts`(`id`)
+);
+
+CREATE TABLE `likes` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `post_id` integer NOT NULL,
+  `user_id` integer NOT NULL,
+  `created_at` datetime,
+  CONSTRAINT `fk_posts_likes` FOREIGN KEY (`post_id`) REFERENCES `posts`(`id`)
+);
+
+CREATE TABLE `water_intakes` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `glasses` integer DEFAULT 0,
+  `goal` integer DEFAULT 8,
+  `date` text NOT NULL,
+  `created_at` datetime,
+  `updated_at` datetime
+);
+
+CREATE TABLE `goals` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `title` text NOT NULL,
+  `description` text,
+  `type` text NOT NULL,
+  `target` real NOT NULL,
+  `current` real DEFAULT 0,
+  `unit` text,
+  `deadline` text,
+  `is_completed` numeric DEFAULT false,
+  `created_at` datetime,
+  `updated_at` datetime
+);
+
+CREATE TABLE `reminders` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `type` text NOT NULL,
+  `label` text NOT NULL,
+  `time` text NOT NULL,
+  `is_active` numeric DEFAULT true,
+  `created_at` datetime,
+  `updated_at` datetime
+);
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS `export_jobs`;
+DROP TABLE IF EXISTS `login_attempts`;
+DROP TABLE IF EXISTS `recovery_codes`;
+DROP TABLE IF EXISTS `password_reset_tokens`;
+DROP TABLE IF EXISTS `sessions`;
+
+ALTER TABLE `users` DROP COLUMN `locked_until`;
+ALTER TABLE `users` DROP COLUMN `last_failed_login_at`;
+ALTER TABLE `users` DROP COLUMN `failed_logins`;
+ALTER TABLE `users` DROP COLUMN `two_factor_last_step`;
+ALTER TABLE `users` DROP COLUMN `two_factor_secret`;
+ALTER TABLE `users` DROP COLUMN `two_factor_enabled`;
+ALTER TABLE `users` DROP COLUMN `suspended_reason`;
+ALTER TABLE `users` DROP COLUMN `suspended_at`;
+ALTER TABLE `users` DROP COLUMN `role`;
@@ -0,0 +1,70 @@
+-- Sessions, password reset, 2FA, login history, data export and user roles
+
+ALTER TABLE `users` ADD COLUMN `role` text DEFAULT "user";
+ALTER TABLE `users` ADD COLUMN `suspended_at` datetime;
+ALTER TABLE `users` ADD COLUMN `suspended_reason` text;
+ALTER TABLE `users` ADD COLUMN `two_factor_enabled` numeric DEFAULT false;
+ALTER TABLE `users` ADD COLUMN `two_factor_secret` text;
+ALTER TABLE `users` ADD COLUMN `two_factor_last_step` integer;
+ALTER TABLE `users` ADD COLUMN `failed_logins` integer DEFAULT 0;
+ALTER TABLE `users` ADD COLUMN `last_failed_login_at` datetime;
+ALTER TABLE `users` ADD COLUMN `locked_until` datetime;
+
+CREATE TABLE `sessions` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `refresh_token_hash` text NOT NULL,
+  `user_agent` text,
+  `ip_address` text,
+  `expires_at` datetime,
+  `revoked_at` datetime,
+  `last_used_at` datetime,
+  `created_at` datetime
+);
+CREATE UNIQUE INDEX `idx_sessions_refresh_token_hash` ON `sessions`(`refresh_token_hash`);
+CREATE INDEX `idx_sessions_user_id` ON `sessions`(`user_id`);
+
+CREATE TABLE `password_reset_tokens` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `token_hash` text NOT NULL,
+  `expires_at` datetime,
+  `used_at` datetime,
+  `created_at` datetime
+);
+CREATE UNIQUE INDEX `idx_password_reset_tokens_token_hash` ON `password_reset_tokens`(`token_hash`);
+CREATE INDEX `idx_password_reset_tokens_user_id` ON `password_reset_tokens`(`user_id`);
+
+CREATE TABLE `recovery_codes` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `code_hash` text NOT NULL,
+  `used_at` datetime,
+  `created_at` datetime
+);
+CREATE INDEX `idx_recovery_codes_user_id` ON `recovery_codes`(`user_id`);
+
+CREATE TABLE `login_attempts` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer,
+  `email` text,
+  `ip_address` text,
+  `user_agent` text,
+  `success` numeric,
+  `reason` text,
+  `created_at` datetime
+);
+CREATE INDEX `idx_login_attempts_email` ON `login_attempts`(`email`);
+CREATE INDEX `idx_login_attempts_user_id` ON `login_attempts`(`user_id`);
+
+CREATE TABLE `export_jobs` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `status` text NOT NULL,
+  `file_path` text,
+  `error` text,
+  `completed_at` datetime,
+  `expires_at` datetime,
+  `created_at` datetime
+);
+CREATE INDEX `idx_export_jobs_user_id` ON `export_jobs`(`user_id`);
@@ -0,0 +1,17 @@
+-- Vital sign measurements: blood pressure, heart rate, glucose, SpO2, temperature, cholesterol
+
+CREATE TABLE `measurements` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `type` text NOT NULL,
+  `value` real,
+  `diastolic` real,
+  `context` text,
+  `unit` text,
+  `category` text,
+  `risk_level` integer,
+  `notes` text,
+  `measured_at` datetime,
+  `created_at` datetime
+);
+CREATE INDEX `idx_measurements_user_type_measured_at` ON `measurements`(`user_id`, `type`, `measured_at`);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS `idx_symptoms_user_logged_at`;
+DROP INDEX IF EXISTS `idx_health_data_user_record_date`;
@@ -0,0 +1,4 @@
+-- Indexes backing the paginated health and symptom history
+
+CREATE INDEX `idx_health_data_user_record_date` ON `health_data`(`user_id`, `record_date`);
+CREATE INDEX `idx_symptoms_user_logged_at` ON `symptoms`(`user_id`, `logged_at`);
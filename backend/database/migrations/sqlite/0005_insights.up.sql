@@ -0,0 +1,18 @@
+-- Stored trend, projection and anomaly insights
+
+CREATE TABLE `insights` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `kind` text NOT NULL,
+  `metric` text NOT NULL,
+  `severity` text NOT NULL,
+  `title` text,
+  `message` text,
+  `value` real,
+  `fingerprint` text NOT NULL,
+  `observed_at` datetime,
+  `read_at` datetime,
+  `created_at` datetime,
+  `updated_at` datetime
+);
+CREATE UNIQUE INDEX `idx_insights_user_fingerprint` ON `insights`(`user_id`, `fingerprint`);
@@ -0,0 +1 @@
+-- The rewritten times denote the same instants, so there is nothing to undo
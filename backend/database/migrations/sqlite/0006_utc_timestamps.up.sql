@@ -0,0 +1,11 @@
+-- SQLite keeps times as text with the offset they were written with and compares them as
+-- text, so rows written before times were stored in UTC sort and filter out of order.
+-- Rewrite them in UTC, in the format the driver writes (fraction without trailing zeros).
+-- SQLite keeps milliseconds, so these older rows lose any finer precision.
+
+UPDATE `health_data` SET `record_date` = rtrim(rtrim(strftime('%Y-%m-%d %H:%M:%f', `record_date`), '0'), '.') || '+00:00'
+  WHERE `record_date` NOT LIKE '%+00:00';
+UPDATE `symptoms` SET `logged_at` = rtrim(rtrim(strftime('%Y-%m-%d %H:%M:%f', `logged_at`), '0'), '.') || '+00:00'
+  WHERE `logged_at` NOT LIKE '%+00:00';
+UPDATE `measurements` SET `measured_at` = rtrim(rtrim(strftime('%Y-%m-%d %H:%M:%f', `measured_at`), '0'), '.') || '+00:00'
+  WHERE `measured_at` NOT LIKE '%+00:00';
@@ -0,0 +1,3 @@
+-- The challenge a password login issued to a 2FA account, cleared when it is exchanged
+
+ALTER TABLE `users` ADD COLUMN `login_challenge` text;
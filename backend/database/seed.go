@@ -1,8 +1,11 @@
 package database
 
 import (
+	"health-tracker/config"
 	"health-tracker/models"
-	"log"
+	"log/slog"
+
+	"gorm.io/gorm"
 )
 
 func SeedData() {
@@ -11,7 +14,7 @@ func SeedData() {
 	DB.Model(&models.SymptomTemplate{}).Count(&count)
 	
 	if count == 0 {
-		log.Println("Seeding symptom templates...")
+		slog.Info("seeding symptom templates")
 
 		// Seed physical symptoms
 		for _, symptom := range models.PhysicalSymptoms {
@@ -39,14 +42,45 @@ func SeedData() {
 	var articleCount int64
 	DB.Model(&models.Article{}).Count(&articleCount)
 	if articleCount == 0 {
-		log.Println("Seeding health articles...")
+		slog.Info("seeding health articles")
 		articles := models.GetSampleArticles()
 		for _, article := range articles {
 			DB.Create(&article)
 		}
 	}
 
-	log.Println("Seed data completed")
+	// Bootstrap the first admin from configuration
+	promoteAdmins(DB, config.AppConfig.AdminEmails)
+
+	slog.Info("seed data completed")
+}
+
+// promoteAdmins makes the listed accounts admins while the database has no admin yet.
+// Once one exists, roles are only changed through the admin API.
+func promoteAdmins(db *gorm.DB, emails []string) {
+	if len(emails) == 0 {
+		return
+	}
+
+	var admins int64
+	if err := db.Model(&models.User{}).Where("role = ?", models.RoleAdmin).Count(&admins).Error; err != nil {
+		slog.Error("failed to count admins", "error", err)
+		return
+	}
+	if admins > 0 {
+		return
+	}
+
+	result := db.Model(&models.User{}).
+		Where("email IN ?", emails).
+		Update("role", models.RoleAdmin)
+	if result.Error != nil {
+		slog.Error("failed to promote admins", "error", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		slog.Info("promoted users to admin", "count", result.RowsAffected)
+	}
 }
 
 func seedRecommendations() {
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"testing"
+
+	"health-tracker/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestPromoteAdminsOnlyBootstraps(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if _, err := MigrateUp(db, "sqlite"); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	for _, email := range []string{"ana@example.com", "budi@example.com"} {
+		if err := db.Create(&models.User{Email: email, Name: email, Password: "!", Role: models.RoleUser}).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+	role := func(email string) string {
+		var user models.User
+		if err := db.Where("email = ?", email).First(&user).Error; err != nil {
+			t.Fatalf("find %s: %v", email, err)
+		}
+		return user.Role
+	}
+
+	promoteAdmins(db, []string{"ana@example.com", "missing@example.com"})
+	if got := role("ana@example.com"); got != models.RoleAdmin {
+		t.Fatalf("first start: role = %q, want admin", got)
+	}
+
+	// With an admin in place, later starts leave roles alone even if the list changes
+	if err := db.Model(&models.User{}).Where("email = ?", "ana@example.com").Update("role", models.RoleModerator).Error; err != nil {
+		t.Fatalf("demote: %v", err)
+	}
+	if err := db.Model(&models.User{}).Where("email = ?", "budi@example.com").Update("role", models.RoleAdmin).Error; err != nil {
+		t.Fatalf("promote: %v", err)
+	}
+	promoteAdmins(db, []string{"ana@example.com"})
+	if got := role("ana@example.com"); got != models.RoleModerator {
+		t.Errorf("later start: role = %q, want moderator", got)
+	}
+}
@@ -0,0 +1,611 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"health-tracker/models"
+	"health-tracker/utils"
+
+	"github.com/gin-gonic/gin/binding"
+	"gorm.io/gorm"
+)
+
+// Entity describes one kind of user data in an archive. Every entity is written as
+// <Name>.json and <Name>.csv; entities with a Restore func can also be imported.
+// Restore checks every row against the rules of the matching create endpoint and returns
+// a *ValidationError listing the invalid ones.
+type Entity struct {
+	Name    string
+	Load    func(db *gorm.DB, userID uint) (interface{}, error)
+	Restore func(tx *gorm.DB, userID uint, data []byte) (int, error)
+}
+
+// Entities lists everything the backend holds about a user, in archive order
+var Entities = []Entity{
+	{
+		Name: "profile",
+		Load: func(db *gorm.DB, userID uint) (interface{}, error) {
+			var user models.User
+			err := db.First(&user, userID).Error
+			return []models.User{user}, err
+		},
+		Restore: restoreProfile,
+	},
+	{
+		Name: "health_data",
+		Load: func(db *gorm.DB, userID uint) (interface{}, error) {
+			var rows []models.HealthData
+			err := db.Where("user_id = ?", userID).Order("record_date asc").Find(&rows).Error
+			return rows, err
+		},
+		Restore: func(tx *gorm.DB, userID uint, data []byte) (int, error) {
+			now := time.Now()
+			return restoreRows(tx, data, func(r *models.HealthData) []utils.FieldError {
+				errs := validateRequest(models.HealthDataRequest{
+					WeightKg:       r.WeightKg,
+					HeightCm:       r.HeightCm,
+					ActivityLevel:  r.ActivityLevel,
+					EmotionalState: r.EmotionalState,
+					DailySchedule:  r.DailySchedule,
+					Notes:          r.Notes,
+				})
+				errs = append(errs, checkPast("record_date", r.RecordDate, now)...)
+				r.ID, r.UserID = 0, userID
+				r.BMI = models.CalculateBMI(r.WeightKg, r.HeightCm)
+				r.RecordDate = r.RecordDate.UTC()
+				return errs
+			})
+		},
+	},
+	{
+		Name: "measurements",
+		Load: func(db *gorm.DB, userID uint) (interface{}, error) {
+			var rows []models.Measurement
+			err := db.Where("user_id = ?", userID).Order("measured_at asc").Find(&rows).Error
+			return rows, err
+		},
+		Restore: func(tx *gorm.DB, userID uint, data []byte) (int, error) {
+			now := time.Now()
+			return restoreRows(tx, data, func(r *models.Measurement) []utils.FieldError {
+				req := models.MeasurementRequest{
+					Type:       r.Type,
+					Value:      r.Value,
+					Diastolic:  r.Diastolic,
+					Context:    r.Context,
+					Unit:       r.Unit,
+					MeasuredAt: &r.MeasuredAt,
+					Notes:      r.Notes,
+				}
+				if errs := append(validateRequest(req), checkPast("measured_at", r.MeasuredAt, now)...); len(errs) > 0 {
+					return errs
+				}
+				// Recomputes the canonical value, category and risk level like POST /measurements
+				m, err := models.NewMeasurement(userID, req, now)
+				var fieldErr *models.MeasurementFieldError
+				if errors.As(err, &fieldErr) {
+					return []utils.FieldError{{Field: fieldErr.Field, Rule: fieldErr.Rule, Message: fieldErr.Message}}
+				}
+				m.CreatedAt = r.CreatedAt
+				*r = *m
+				return nil
+			})
+		},
+	},
+	{
+		// Insights are derived from the readings and regenerated by the next analysis, so they are not restored
+		Name: "insights",
+		Load: func(db *gorm.DB, userID uint) (interface{}, error) {
+			var rows []models.Insight
+			err := db.Where("user_id = ?", userID).Order("created_at asc").Find(&rows).Error
+			return rows, err
+		},
+	},
+	{
+		Name: "symptoms",
+		Load: func(db *gorm.DB, userID uint) (interface{}, error) {
+			var rows []models.Symptom
+			err := db.Where("user_id = ?", userID).Order("logged_at asc").Find(&rows).Error
+			return rows, err
+		},
+		Restore: func(tx *gorm.DB, userID uint, data []byte) (int, error) {
+			now := time.Now()
+			return restoreRows(tx, data, func(r *models.Symptom) []utils.FieldError {
+				errs := validateRequest(models.SymptomRequest{
+					SymptomType: r.SymptomType,
+					SymptomName: r.SymptomName,
+					Severity:    r.Severity,
+					Notes:       r.Notes,
+				})
+				errs = append(errs, checkPast("logged_at", r.LoggedAt, now)...)
+				r.ID, r.UserID = 0, userID
+				r.LoggedAt = r.LoggedAt.UTC()
+				return errs
+			})
+		},
+	},
+	{
+		Name: "water_intake",
+		Load: func(db *gorm.DB, userID uint) (interface{}, error) {
+			var rows []models.WaterIntake
+			err := db.Where("user_id = ?", userID).Order("date asc").Find(&rows).Error
+			return rows, err
+		},
+		Restore: func(tx *gorm.DB, userID uint, data []byte) (int, error) {
+			return restoreRows(tx, data, func(r *models.WaterIntake) []utils.FieldError {
+				errs := validateRequest(models.UpdateWaterGoalRequest{Goal: r.Goal})
+				if r.Glasses < 0 {
+					errs = append(errs, utils.FieldError{Field: "glasses", Rule: "min", Param: "0", Message: "must be at least 0"})
+				}
+				errs = append(errs, checkLayout("date", r.Date, "2006-01-02", "must be a date (YYYY-MM-DD)")...)
+				r.ID, r.UserID = 0, userID
+				return errs
+			})
+		},
+	},
+	{
+		Name: "goals",
+		Load: func(db *gorm.DB, userID uint) (interface{}, error) {
+			var rows []models.Goal
+			err := db.Where("user_id = ?", userID).Order("created_at asc").Find(&rows).Error
+			return rows, err
+		},
+		Restore: func(tx *gorm.DB, userID uint, data []byte) (int, error) {
+			return restoreRows(tx, data, func(r *models.Goal) []utils.FieldError {
+				errs := validateRequest(models.CreateGoalRequest{
+					Title:       r.Title,
+					Description: r.Description,
+					Type:        r.Type,
+					Target:      r.Target,
+					Unit:        r.Unit,
+					Deadline:    r.Deadline,
+				})
+				if r.Deadline != "" {
+					errs = append(errs, checkLayout("deadline", r.Deadline, "2006-01-02", "must be a date (YYYY-MM-DD)")...)
+				}
+				r.ID, r.UserID = 0, userID
+				return errs
+			})
+		},
+	},
+	{
+		Name: "reminders",
+		Load: func(db *gorm.DB, userID uint) (interface{}, error) {
+			var rows []models.Reminder
+			err := db.Where("user_id = ?", userID).Order("time asc").Find(&rows).Error
+			return rows, err
+		},
+		Restore: func(tx *gorm.DB, userID uint, data []byte) (int, error) {
+			// A fresh account already has the default reminders; the archived ones replace them
+			if err := tx.Where("user_id = ?", userID).Delete(&models.Reminder{}).Error; err != nil {
+				return 0, err
+			}
+			return restoreRows(tx, data, func(r *models.Reminder) []utils.FieldError {
+				errs := validateRequest(models.CreateReminderRequest{Type: r.Type, Label: r.Label, Time: r.Time})
+				if r.Time != "" {
+					errs = append(errs, checkLayout("time", r.Time, "15:04", "must be a time (HH:MM)")...)
+				}
+				r.ID, r.UserID = 0, userID
+				return errs
+			})
+		},
+	},
+	{
+		// Family links reference other accounts, so they are exported but not restored
+		Name: "family_links",
+		Load: func(db *gorm.DB, userID uint) (interface{}, error) {
+			var rows []models.FamilyMember
+			err := db.Where("owner_id = ? OR member_user_id = ?", userID, userID).Order("created_at asc").Find(&rows).Error
+			return rows, err
+		},
+	},
+	{
+		// Forum content stays in the shared forum and is not duplicated on import
+		Name: "forum_posts",
+		Load: func(db *gorm.DB, userID uint) (interface{}, error) {
+			var posts []models.Post
+			if err := db.Where("user_id = ?", userID).Order("created_at asc").Find(&posts).Error; err != nil {
+				return nil, err
+			}
+			rows := make([]models.PostResponse, len(posts))
+			for i, p := range posts {
+				rows[i] = models.PostResponse{
+					ID:            p.ID,
+					UserID:        p.UserID,
+					Title:         p.Title,
+					Content:       p.Content,
+					LikesCount:    p.LikesCount,
+					CommentsCount: p.CommentsCount,
+					CreatedAt:     p.CreatedAt,
+				}
+			}
+			return rows, nil
+		},
+	},
+	{
+		Name: "forum_comments",
+		Load: func(db *gorm.DB, userID uint) (interface{}, error) {
+			var comments []models.Comment
+			if err := db.Where("user_id = ?", userID).Order("created_at asc").Find(&comments).Error; err != nil {
+				return nil, err
+			}
+			rows := make([]models.CommentResponse, len(comments))
+			for i, cm := range comments {
+				rows[i] = models.CommentResponse{
+					ID:        cm.ID,
+					PostID:    cm.PostID,
+					UserID:    cm.UserID,
+					Content:   cm.Content,
+					CreatedAt: cm.CreatedAt,
+				}
+			}
+			return rows, nil
+		},
+	},
+}
+
+// manifestFile describes the archive itself
+const manifestFile = "manifest.json"
+
+// Manifest is stored at the root of every archive
+type Manifest struct {
+	Version    int            `json:"version"`
+	UserID     uint           `json:"user_id"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Counts     map[string]int `json:"counts"`
+}
+
+const archiveVersion = 1
+
+// CountRows returns how many rows an export of the user would contain
+func CountRows(db *gorm.DB, userID uint) (int64, error) {
+	var total int64
+	owned := []interface{}{
+		&models.HealthData{},
+		&models.Measurement{},
+		&models.Insight{},
+		&models.Symptom{},
+		&models.WaterIntake{},
+		&models.Goal{},
+		&models.Reminder{},
+		&models.Post{},
+		&models.Comment{},
+	}
+	for _, model := range owned {
+		var n int64
+		if err := db.Model(model).Where("user_id = ?", userID).Count(&n).Error; err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// WriteArchive writes a ZIP with one JSON and one CSV file per entity
+func WriteArchive(db *gorm.DB, userID uint, w io.Writer) error {
+	zw := zip.NewWriter(w)
+	manifest := Manifest{
+		Version:    archiveVersion,
+		UserID:     userID,
+		ExportedAt: time.Now(),
+		Counts:     map[string]int{},
+	}
+
+	for _, entity := range Entities {
+		rows, err := entity.Load(db, userID)
+		if err != nil {
+			return fmt.Errorf("load %s: %w", entity.Name, err)
+		}
+		manifest.Counts[entity.Name] = reflect.ValueOf(rows).Len()
+
+		jw, err := zw.Create(entity.Name + ".json")
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(jw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(rows); err != nil {
+			return fmt.Errorf("encode %s: %w", entity.Name, err)
+		}
+
+		cw, err := zw.Create(entity.Name + ".csv")
+		if err != nil {
+			return err
+		}
+		if err := writeCSV(cw, rows); err != nil {
+			return fmt.Errorf("write %s csv: %w", entity.Name, err)
+		}
+	}
+
+	mw, err := zw.Create(manifestFile)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(mw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// ImportResult reports how many rows were restored per entity
+type ImportResult struct {
+	Restored map[string]int `json:"restored"`
+	Skipped  []string       `json:"skipped"`
+}
+
+// maxImportErrors caps how many invalid fields one rejected import reports
+const maxImportErrors = 100
+
+// ValidationError rejects an archive whose rows fail validation. Each detail's field is
+// "<entity>[<row>].<field>", with rows counted from 0 in the entity's JSON file.
+type ValidationError struct {
+	Details []utils.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("archive contains %d invalid fields", len(e.Details))
+}
+
+// ImportArchive restores the JSON files of an archive into the given user's account.
+// It must run inside a transaction: when any row is invalid nothing should be kept, and
+// the returned *ValidationError lists the invalid rows of every entity.
+func ImportArchive(tx *gorm.DB, userID uint, archive []byte) (*ImportResult, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	manifestData, err := readZipFile(files[manifestFile])
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: missing %s", manifestFile)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+	if manifest.Version != archiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %d", manifest.Version)
+	}
+
+	result := &ImportResult{Restored: map[string]int{}, Skipped: []string{}}
+	invalid := &ValidationError{}
+	for _, entity := range Entities {
+		if entity.Restore == nil {
+			result.Skipped = append(result.Skipped, entity.Name)
+			continue
+		}
+
+		f, ok := files[entity.Name+".json"]
+		if !ok {
+			continue
+		}
+		data, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		n, err := entity.Restore(tx, userID, data)
+		var rowErrs *ValidationError
+		if errors.As(err, &rowErrs) {
+			for _, detail := range rowErrs.Details {
+				detail.Field = entity.Name + detail.Field
+				invalid.Details = append(invalid.Details, detail)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("restore %s: %w", entity.Name, err)
+		}
+		result.Restored[entity.Name] = n
+	}
+
+	if len(invalid.Details) > 0 {
+		if len(invalid.Details) > maxImportErrors {
+			invalid.Details = invalid.Details[:maxImportErrors]
+		}
+		return nil, invalid
+	}
+	return result, nil
+}
+
+// maxArchiveFileSize guards against zip bombs when reading archive entries
+const maxArchiveFileSize = 50 << 20
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	if f == nil {
+		return nil, fmt.Errorf("file not found in archive")
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxArchiveFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxArchiveFileSize {
+		return nil, fmt.Errorf("%s is too large", f.Name)
+	}
+	return data, nil
+}
+
+// restoreRows decodes an entity's rows and inserts them. prepare validates a row and
+// assigns it to the importing user; if any row is invalid nothing is inserted and the
+// errors come back as a *ValidationError with "[<row>].<field>" paths.
+func restoreRows[T any](tx *gorm.DB, data []byte, prepare func(*T) []utils.FieldError) (int, error) {
+	var rows []T
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	invalid := &ValidationError{}
+	for i := range rows {
+		for _, detail := range prepare(&rows[i]) {
+			detail.Field = fmt.Sprintf("[%d].%s", i, detail.Field)
+			invalid.Details = append(invalid.Details, detail)
+		}
+	}
+	if len(invalid.Details) > 0 {
+		return 0, invalid
+	}
+
+	if err := tx.CreateInBatches(&rows, 100).Error; err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// validateRequest applies the binding rules of a create endpoint's request struct
+func validateRequest(req interface{}) []utils.FieldError {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return utils.ValidationDetails(err)
+	}
+	return nil
+}
+
+// checkPast requires a timestamp that is set and not in the future, like the create endpoints
+func checkPast(field string, t, now time.Time) []utils.FieldError {
+	if t.IsZero() {
+		return []utils.FieldError{{Field: field, Rule: "required", Message: "is required"}}
+	}
+	if t.After(now.Add(models.MaxClockSkew)) {
+		return []utils.FieldError{{Field: field, Rule: "past", Message: "cannot be in the future"}}
+	}
+	return nil
+}
+
+// checkLayout requires value to parse with the given time layout
+func checkLayout(field, value, layout, message string) []utils.FieldError {
+	if _, err := time.Parse(layout, value); err != nil {
+		return []utils.FieldError{{Field: field, Rule: "datetime", Param: layout, Message: message}}
+	}
+	return nil
+}
+
+// restoreProfile copies body metrics from the archived profile; identity fields
+// (email, password, 2FA) always stay those of the importing account
+func restoreProfile(tx *gorm.DB, userID uint, data []byte) (int, error) {
+	var profiles []models.User
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return 0, err
+	}
+	if len(profiles) == 0 {
+		return 0, nil
+	}
+
+	p := profiles[0]
+	err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"birth_date":     p.BirthDate,
+		"height_cm":      p.HeightCm,
+		"weight_kg":      p.WeightKg,
+		"activity_level": p.ActivityLevel,
+	}).Error
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// writeCSV writes a slice of structs as CSV using their JSON field names as headers.
+// Nested structs and slices are skipped; they are only available in the JSON file.
+func writeCSV(w io.Writer, rows interface{}) error {
+	v := reflect.ValueOf(rows)
+	t := v.Type().Elem()
+
+	type column struct {
+		name  string
+		index int
+	}
+	var columns []column
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || !isScalar(f.Type) {
+			continue
+		}
+		columns = append(columns, column{name: name, index: i})
+	}
+
+	cw := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(columns))
+	for r := 0; r < v.Len(); r++ {
+		row := v.Index(r)
+		for i, col := range columns {
+			record[i] = formatValue(row.Field(col.index))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func isScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Map, reflect.Array:
+		return false
+	}
+	return true
+}
+
+func formatValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
@@ -0,0 +1,155 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"health-tracker/models"
+	"health-tracker/utils"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.User{}, &models.HealthData{}, &models.Measurement{}, &models.Symptom{},
+		&models.WaterIntake{}, &models.Goal{}, &models.Reminder{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := db.Create(&models.User{ID: 1, Email: "ana@example.com", Name: "Ana"}).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return db
+}
+
+func buildArchive(t *testing.T, files map[string]interface{}) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files[manifestFile] = Manifest{Version: archiveVersion, UserID: 9, ExportedAt: time.Now()}
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		if err := json.NewEncoder(w).Encode(content); err != nil {
+			t.Fatalf("encode %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestImportArchiveRejectsInvalidRows(t *testing.T) {
+	utils.ConfigureValidator()
+	db := newTestDB(t)
+	past := time.Now().Add(-24 * time.Hour)
+
+	archive := buildArchive(t, map[string]interface{}{
+		"health_data.json": []models.HealthData{
+			{WeightKg: 70, HeightCm: 170, RecordDate: past},
+			{WeightKg: 900, HeightCm: 170, RecordDate: time.Now().Add(48 * time.Hour)},
+		},
+		"measurements.json": []models.Measurement{
+			{Type: models.MeasurementHeartRate, Value: 500, Unit: "bpm", MeasuredAt: past},
+		},
+		"symptoms.json": []models.Symptom{
+			{SymptomType: "physical", SymptomName: "Flu", Severity: 11, LoggedAt: past},
+		},
+		"water_intake.json": []models.WaterIntake{{Glasses: 3, Goal: 8, Date: "18-10-2026"}},
+	})
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		_, err := ImportArchive(tx, 1, archive)
+		return err
+	})
+
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("ImportArchive error = %v, want *ValidationError", err)
+	}
+	got := map[string]string{}
+	for _, d := range invalid.Details {
+		got[d.Field] = d.Rule
+	}
+	want := map[string]string{
+		"health_data[1].weight_kg":   "max",
+		"health_data[1].record_date": "past",
+		"measurements[0].value":      "range",
+		"symptoms[0].severity":       "max",
+		"water_intake[0].date":       "datetime",
+	}
+	for field, rule := range want {
+		if got[field] != rule {
+			t.Errorf("%s: rule = %q, want %q (all: %v)", field, got[field], rule, got)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d invalid fields, want %d: %v", len(got), len(want), got)
+	}
+
+	var count int64
+	db.Model(&models.HealthData{}).Count(&count)
+	if count != 0 {
+		t.Errorf("%d health records kept after a rejected import", count)
+	}
+}
+
+func TestImportArchiveNormalizesRows(t *testing.T) {
+	utils.ConfigureValidator()
+	db := newTestDB(t)
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	recorded := time.Date(2026, 1, 2, 8, 0, 0, 0, jakarta)
+
+	archive := buildArchive(t, map[string]interface{}{
+		"health_data.json": []models.HealthData{{ID: 44, UserID: 9, WeightKg: 72, HeightCm: 180, BMI: 1, RecordDate: recorded}},
+		"measurements.json": []models.Measurement{
+			{ID: 45, UserID: 9, Type: models.MeasurementBloodGlucose, Value: 7.5, Unit: "mmol/L", Context: models.GlucoseFasting, MeasuredAt: recorded},
+		},
+	})
+
+	var result *ImportResult
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		result, err = ImportArchive(tx, 1, archive)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("ImportArchive: %v", err)
+	}
+	if result.Restored["health_data"] != 1 || result.Restored["measurements"] != 1 {
+		t.Errorf("restored = %v", result.Restored)
+	}
+
+	var record models.HealthData
+	db.First(&record)
+	if record.UserID != 1 || record.ID == 44 {
+		t.Errorf("record kept the archived owner or ID: user %d, id %d", record.UserID, record.ID)
+	}
+	if want := models.CalculateBMI(72, 180); record.BMI != want {
+		t.Errorf("bmi = %v, want %v", record.BMI, want)
+	}
+	if !record.RecordDate.Equal(recorded) {
+		t.Errorf("record_date = %v, want %v", record.RecordDate, recorded)
+	}
+
+	var m models.Measurement
+	db.First(&m)
+	if m.Unit != "mg/dL" || m.Value < 130 || m.Value > 140 || m.Category == "" {
+		t.Errorf("measurement not converted and classified: %+v", m)
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+
+	"health-tracker/insights"
+	"health-tracker/models"
+	"health-tracker/repository"
+	"health-tracker/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountHandler serves account deletion and personal data export/import
+type AccountHandler struct {
+	users         repository.UserRepository
+	recoveryCodes repository.RecoveryCodeRepository
+	exports       repository.ExportRepository
+	analyzer      *insights.Analyzer
+}
+
+// NewAccountHandler creates an AccountHandler
+func NewAccountHandler(users repository.UserRepository, recoveryCodes repository.RecoveryCodeRepository, exports repository.ExportRepository, analyzer *insights.Analyzer) *AccountHandler {
+	return &AccountHandler{users: users, recoveryCodes: recoveryCodes, exports: exports, analyzer: analyzer}
+}
+
+// DeleteAccount permanently deletes the current user and everything they own.
+// Forum posts and comments are kept but reassigned to an anonymous placeholder account.
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	var req models.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, err := h.users.FindByID(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if !utils.CheckPassword(req.Password, user.Password) {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid password")
+		return
+	}
+
+	if user.TwoFactorEnabled && !verifySecondFactor(ctx, h.users, h.recoveryCodes, user, req.Code) {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid verification code")
+		return
+	}
+
+	exportFiles, err := h.users.Purge(ctx, user.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete account")
+		return
+	}
+
+	// Archived exports on disk contain the same personal data
+	for _, path := range exportFiles {
+		os.Remove(path)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Akun dan seluruh data Anda telah dihapus", nil)
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"health-tracker/middleware"
+	"health-tracker/models"
+	"health-tracker/repository"
+	"health-tracker/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler serves user management for moderators and admins
+type AdminHandler struct {
+	users    repository.UserRepository
+	sessions repository.SessionRepository
+	tx       repository.Transactor
+}
+
+// NewAdminHandler creates an AdminHandler
+func NewAdminHandler(users repository.UserRepository, sessions repository.SessionRepository, tx repository.Transactor) *AdminHandler {
+	return &AdminHandler{users: users, sessions: sessions, tx: tx}
+}
+
+// AdminListUsers returns a paginated user list, optionally filtered by search term, role or status
+func (h *AdminHandler) AdminListUsers(c *gin.Context) {
+	ctx := c.Request.Context()
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	users, total, err := h.users.List(ctx, repository.UserFilter{
+		Query:  c.Query("q"),
+		Role:   c.Query("role"),
+		Status: c.Query("status"),
+		Offset: (page - 1) * limit,
+		Limit:  limit,
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch users")
+		return
+	}
+
+	utils.PaginatedResponse(c, "Users retrieved", users, utils.NewPagination(page, limit, total))
+}
+
+// AdminGetUser returns a single user
+func (h *AdminHandler) AdminGetUser(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := h.users.FindByID(ctx, paramID(c, "id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User retrieved", user)
+}
+
+// AdminSuspendUser suspends an account and signs it out everywhere
+func (h *AdminHandler) AdminSuspendUser(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req models.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, ok := h.loadManageableUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.updateAndSignOut(ctx, user.ID, map[string]interface{}{
+		"suspended_at":     time.Now(),
+		"suspended_reason": req.Reason,
+	}); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to suspend user")
+		return
+	}
+
+	h.respondWithUser(c, user.ID, "User suspended")
+}
+
+// AdminUnsuspendUser lifts a suspension
+func (h *AdminHandler) AdminUnsuspendUser(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, ok := h.loadManageableUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.users.Update(ctx, user.ID, map[string]interface{}{
+		"suspended_at":     nil,
+		"suspended_reason": "",
+	}); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to unsuspend user")
+		return
+	}
+
+	h.respondWithUser(c, user.ID, "User unsuspended")
+}
+
+// AdminUpdateUserRole assigns a role; existing sessions are revoked so the new role applies immediately
+func (h *AdminHandler) AdminUpdateUserRole(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req models.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, ok := h.loadManageableUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.updateAndSignOut(ctx, user.ID, map[string]interface{}{"role": req.Role}); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update role")
+		return
+	}
+
+	h.respondWithUser(c, user.ID, "Role updated")
+}
+
+// updateAndSignOut updates the user and revokes all their sessions in one transaction, so
+// the change never lands while old sessions stay valid
+func (h *AdminHandler) updateAndSignOut(ctx context.Context, userID uint, fields map[string]interface{}) error {
+	return h.tx.Transaction(ctx, func(tx *repository.Repositories) error {
+		if err := tx.Users.Update(ctx, userID, fields); err != nil {
+			return err
+		}
+		_, err := tx.Sessions.RevokeAll(ctx, userID)
+		return err
+	})
+}
+
+// loadManageableUser loads the target user and checks the caller may act on them:
+// nobody can manage themselves and only admins can manage other admins
+func (h *AdminHandler) loadManageableUser(c *gin.Context) (*models.User, bool) {
+	ctx := c.Request.Context()
+	user, err := h.users.FindByID(ctx, paramID(c, "id"))
+	if err != nil || user.Email == models.DeletedUserEmail {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return nil, false
+	}
+
+	if user.ID == c.GetUint("userID") {
+		utils.ErrorResponse(c, http.StatusBadRequest, "You cannot change your own account")
+		return nil, false
+	}
+
+	if user.Role == models.RoleAdmin && middleware.GetUserRole(c) != models.RoleAdmin {
+		utils.ErrorResponse(c, http.StatusForbidden, "Only admins can manage other admins")
+		return nil, false
+	}
+
+	return user, true
+}
+
+// respondWithUser reloads the user after an update and returns it
+func (h *AdminHandler) respondWithUser(c *gin.Context, id uint, message string) {
+	ctx := c.Request.Context()
+	user, err := h.users.FindByID(ctx, id)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, message, user)
+}
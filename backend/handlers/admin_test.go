@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"health-tracker/models"
+	"health-tracker/repository"
+)
+
+func newTestAdminHandler(users *fakeUsers, sessions *fakeSessions) *AdminHandler {
+	return NewAdminHandler(users, sessions, &repository.Repositories{Users: users, Sessions: sessions})
+}
+
+func adminTestFixtures() (*fakeUsers, *fakeSessions) {
+	users := newFakeUsers(
+		models.User{ID: 1, Email: "admin@example.com", Role: models.RoleAdmin},
+		models.User{ID: 2, Email: "ana@example.com", Role: models.RoleUser},
+	)
+	sessions := &fakeSessions{sessions: []*models.Session{
+		{ID: 1, UserID: 2, ExpiresAt: time.Now().Add(time.Hour)},
+		{ID: 2, UserID: 2, ExpiresAt: time.Now().Add(time.Hour)},
+	}}
+	return users, sessions
+}
+
+func TestAdminSuspendUserRevokesSessions(t *testing.T) {
+	users, sessions := adminTestFixtures()
+	h := newTestAdminHandler(users, sessions)
+	r := newTestRouter(1, models.RoleAdmin)
+	r.PUT("/admin/users/:id/suspend", h.AdminSuspendUser)
+
+	w := serve(r, http.MethodPut, "/admin/users/2/suspend", models.SuspendUserRequest{Reason: "spam"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("suspend = %d, want 200: %s", w.Code, w.Body)
+	}
+
+	var user models.User
+	decodeResponse(t, w, &user)
+	if user.SuspendedAt == nil || user.SuspendedReason != "spam" {
+		t.Errorf("response user not suspended: %+v", user)
+	}
+	for _, session := range sessions.sessions {
+		if session.RevokedAt == nil {
+			t.Errorf("session %d still active after suspension", session.ID)
+		}
+	}
+}
+
+func TestAdminUpdateUserRoleFailsWhenSessionsStayActive(t *testing.T) {
+	users, sessions := adminTestFixtures()
+	sessions.revokeErr = errors.New("database is locked")
+	h := newTestAdminHandler(users, sessions)
+	r := newTestRouter(1, models.RoleAdmin)
+	r.PUT("/admin/users/:id/role", h.AdminUpdateUserRole)
+
+	w := serve(r, http.MethodPut, "/admin/users/2/role", models.UpdateRoleRequest{Role: models.RoleModerator})
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("role change with failing revocation = %d, want 500", w.Code)
+	}
+}
+
+func TestAdminCannotManageThemselves(t *testing.T) {
+	users, sessions := adminTestFixtures()
+	h := newTestAdminHandler(users, sessions)
+	r := newTestRouter(1, models.RoleAdmin)
+	r.PUT("/admin/users/:id/suspend", h.AdminSuspendUser)
+
+	if w := serve(r, http.MethodPut, "/admin/users/1/suspend", models.SuspendUserRequest{}); w.Code != http.StatusBadRequest {
+		t.Errorf("self suspension = %d, want 400", w.Code)
+	}
+	if users.users[1].SuspendedAt != nil {
+		t.Error("admin suspended themselves")
+	}
+}
@@ -1,47 +1,50 @@
 package handlers
 
 import (
-	"health-tracker/database"
-	"health-tracker/models"
+	"health-tracker/repository"
+	"health-tracker/utils"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
-// GetArticles returns all articles with optional category filter
-func GetArticles(c *gin.Context) {
-	var articles []models.Article
-	category := c.Query("category")
+// ArticleHandler serves health articles
+type ArticleHandler struct {
+	articles repository.ArticleRepository
+}
 
-	query := database.DB.Order("created_at DESC")
-	if category != "" && category != "all" {
-		query = query.Where("category = ?", category)
-	}
+// NewArticleHandler creates an ArticleHandler
+func NewArticleHandler(articles repository.ArticleRepository) *ArticleHandler {
+	return &ArticleHandler{articles: articles}
+}
 
-	if err := query.Find(&articles).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch articles"})
+// GetArticles returns all articles with optional category filter
+func (h *ArticleHandler) GetArticles(c *gin.Context) {
+	ctx := c.Request.Context()
+	articles, err := h.articles.List(ctx, articleCategory(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch articles")
 		return
 	}
 
-	c.JSON(http.StatusOK, articles)
+	utils.SuccessResponse(c, http.StatusOK, "Articles retrieved", articles)
 }
 
 // GetArticle returns a single article by ID
-func GetArticle(c *gin.Context) {
-	id := c.Param("id")
-	var article models.Article
-
-	if err := database.DB.First(&article, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+func (h *ArticleHandler) GetArticle(c *gin.Context) {
+	ctx := c.Request.Context()
+	article, err := h.articles.FindByID(ctx, paramID(c, "id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Article not found")
 		return
 	}
 
-	c.JSON(http.StatusOK, article)
+	utils.SuccessResponse(c, http.StatusOK, "Article retrieved", article)
 }
 
 // GetArticleCategories returns all available categories
-func GetArticleCategories(c *gin.Context) {
+func (h *ArticleHandler) GetArticleCategories(c *gin.Context) {
 	categories := []map[string]string{
 		{"id": "all", "name": "Semua", "icon": "📚"},
 		{"id": "nutrisi", "name": "Nutrisi", "icon": "🥗"},
@@ -50,53 +53,55 @@ func GetArticleCategories(c *gin.Context) {
 		{"id": "tidur", "name": "Tidur", "icon": "😴"},
 		{"id": "umum", "name": "Umum", "icon": "❤️"},
 	}
-	c.JSON(http.StatusOK, categories)
+	utils.SuccessResponse(c, http.StatusOK, "Article categories retrieved", categories)
 }
 
 // SearchArticles searches articles by keyword
-func SearchArticles(c *gin.Context) {
+func (h *ArticleHandler) SearchArticles(c *gin.Context) {
+	ctx := c.Request.Context()
 	keyword := c.Query("q")
 	if keyword == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Search keyword required"})
+		utils.ErrorResponse(c, http.StatusBadRequest, "Search keyword required")
 		return
 	}
 
-	var articles []models.Article
-	searchPattern := "%" + keyword + "%"
-	
-	if err := database.DB.Where("title LIKE ? OR content LIKE ?", searchPattern, searchPattern).
-		Order("created_at DESC").Find(&articles).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search articles"})
+	articles, err := h.articles.Search(ctx, keyword)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to search articles")
 		return
 	}
 
-	c.JSON(http.StatusOK, articles)
+	utils.SuccessResponse(c, http.StatusOK, "Search results retrieved", articles)
 }
 
 // Pagination helper
-func GetPaginatedArticles(c *gin.Context) {
+func (h *ArticleHandler) GetPaginatedArticles(c *gin.Context) {
+	ctx := c.Request.Context()
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	category := c.Query("category")
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
 
 	offset := (page - 1) * limit
 
-	var articles []models.Article
-	var total int64
-
-	query := database.DB.Model(&models.Article{})
-	if category != "" && category != "all" {
-		query = query.Where("category = ?", category)
+	articles, total, err := h.articles.Paginate(ctx, articleCategory(c), offset, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch articles")
+		return
 	}
 
-	query.Count(&total)
-	query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&articles)
+	utils.PaginatedResponse(c, "Articles retrieved", articles, utils.NewPagination(page, limit, total))
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"articles": articles,
-		"total":    total,
-		"page":     page,
-		"limit":    limit,
-		"pages":    (total + int64(limit) - 1) / int64(limit),
-	})
+// articleCategory returns the requested category, or "" for all categories
+func articleCategory(c *gin.Context) string {
+	category := c.Query("category")
+	if category == "all" {
+		return ""
+	}
+	return category
 }
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"health-tracker/models"
+)
+
+func TestGetArticle(t *testing.T) {
+	h := NewArticleHandler(&fakeArticles{articles: []models.Article{{ID: 1, Title: "Pola tidur sehat", Category: "tidur"}}})
+	r := newTestRouter(0, "")
+	r.GET("/articles/:id", h.GetArticle)
+
+	w := serve(r, http.MethodGet, "/articles/1", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get = %d, want 200", w.Code)
+	}
+	var article models.Article
+	decodeResponse(t, w, &article)
+	if article.Title != "Pola tidur sehat" {
+		t.Errorf("article = %+v", article)
+	}
+
+	if w := serve(r, http.MethodGet, "/articles/2", nil); w.Code != http.StatusNotFound {
+		t.Errorf("missing article = %d, want 404", w.Code)
+	}
+}
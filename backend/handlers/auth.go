@@ -1,27 +1,72 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
-
-	"health-tracker/database"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"health-tracker/config"
+	"health-tracker/mailer"
+	"health-tracker/metrics"
 	"health-tracker/models"
+	"health-tracker/repository"
 	"health-tracker/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+// AuthHandler serves registration, login, sessions, password reset and 2FA
+type AuthHandler struct {
+	users         repository.UserRepository
+	sessions      repository.SessionRepository
+	resets        repository.PasswordResetRepository
+	recoveryCodes repository.RecoveryCodeRepository
+	loginAttempts repository.LoginAttemptRepository
+	tx            repository.Transactor
+}
+
+// NewAuthHandler creates an AuthHandler
+func NewAuthHandler(
+	users repository.UserRepository,
+	sessions repository.SessionRepository,
+	resets repository.PasswordResetRepository,
+	recoveryCodes repository.RecoveryCodeRepository,
+	loginAttempts repository.LoginAttemptRepository,
+	tx repository.Transactor,
+) *AuthHandler {
+	return &AuthHandler{
+		users:         users,
+		sessions:      sessions,
+		resets:        resets,
+		recoveryCodes: recoveryCodes,
+		loginAttempts: loginAttempts,
+		tx:            tx,
+	}
+}
+
 // Register creates a new user account
-func Register(c *gin.Context) {
+func (h *AuthHandler) Register(c *gin.Context) {
+	ctx := c.Request.Context()
 	var req models.RegisterRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	// The anonymized-content placeholder account can never be claimed
+	if strings.EqualFold(req.Email, models.DeletedUserEmail) {
+		utils.ErrorResponse(c, http.StatusConflict, "Email already registered")
 		return
 	}
 
 	// Check if email already exists
-	var existingUser models.User
-	if result := database.DB.Where("email = ?", req.Email).First(&existingUser); result.RowsAffected > 0 {
+	if _, err := h.users.FindByEmail(ctx, req.Email); err == nil {
 		utils.ErrorResponse(c, http.StatusConflict, "Email already registered")
 		return
 	}
@@ -40,65 +85,102 @@ func Register(c *gin.Context) {
 		Name:     req.Name,
 	}
 
-	if result := database.DB.Create(&user); result.Error != nil {
+	if err := h.users.Create(ctx, &user); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create user")
 		return
 	}
+	metrics.UserRegistrations.Inc()
 
-	// Generate token
-	token, err := utils.GenerateToken(user.ID, user.Email)
+	// Start session
+	response, err := h.startSession(c, user)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate token")
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusCreated, "Registration successful", models.LoginResponse{
-		Token: token,
-		User:  user,
-	})
+	utils.SuccessResponse(c, http.StatusCreated, "Registration successful", response)
 }
 
 // Login authenticates user and returns JWT
-func Login(c *gin.Context) {
+func (h *AuthHandler) Login(c *gin.Context) {
+	ctx := c.Request.Context()
 	var req models.LoginRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
 	// Find user by email
-	var user models.User
-	if result := database.DB.Where("email = ?", req.Email).First(&user); result.Error != nil {
+	user, err := h.users.FindByEmail(ctx, req.Email)
+	if err != nil {
+		h.recordLoginAttempt(c, nil, req.Email, false, models.LoginReasonUnknownEmail)
 		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid email or password")
 		return
 	}
 
+	if h.rejectLockedAccount(c, user) {
+		return
+	}
+
 	// Check password
 	if !utils.CheckPassword(req.Password, user.Password) {
+		h.registerFailedLogin(c, user, models.LoginReasonInvalidPassword)
 		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid email or password")
 		return
 	}
 
-	// Generate token
-	token, err := utils.GenerateToken(user.ID, user.Email)
+	if user.IsSuspended() {
+		h.recordLoginAttempt(c, &user.ID, user.Email, false, models.LoginReasonSuspended)
+		utils.ErrorResponseWithCode(c, http.StatusForbidden, utils.CodeAccountSuspended, "Akun Anda telah ditangguhkan")
+		return
+	}
+
+	// Accounts with 2FA must complete a second step before a session is created
+	if user.TwoFactorEnabled {
+		// Only the newest challenge can be exchanged, and only once
+		challengeID, err := utils.GenerateRandomToken(16)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate token")
+			return
+		}
+		if err := h.users.Update(ctx, user.ID, map[string]interface{}{"login_challenge": utils.HashToken(challengeID)}); err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate token")
+			return
+		}
+		challenge, err := utils.GenerateChallengeToken(user.ID, user.Email, challengeID)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate token")
+			return
+		}
+
+		utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication required", models.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+			ExpiresIn:         int64(utils.TwoFactorChallengeTTL.Seconds()),
+		})
+		return
+	}
+
+	h.registerSuccessfulLogin(c, user)
+
+	// Start session
+	response, err := h.startSession(c, *user)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate token")
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Login successful", models.LoginResponse{
-		Token: token,
-		User:  user,
-	})
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", response)
 }
 
 // GetCurrentUser returns the authenticated user's profile
-func GetCurrentUser(c *gin.Context) {
+func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
-	var user models.User
-	if result := database.DB.First(&user, userID); result.Error != nil {
+	user, err := h.users.FindByID(ctx, userID)
+	if err != nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
 		return
 	}
@@ -107,17 +189,18 @@ func GetCurrentUser(c *gin.Context) {
 }
 
 // UpdateProfile updates user profile information
-func UpdateProfile(c *gin.Context) {
+func (h *AuthHandler) UpdateProfile(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
 	var req models.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
-	var user models.User
-	if result := database.DB.First(&user, userID); result.Error != nil {
+	user, err := h.users.FindByID(ctx, userID)
+	if err != nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
 		return
 	}
@@ -139,30 +222,90 @@ func UpdateProfile(c *gin.Context) {
 		user.ActivityLevel = req.ActivityLevel
 	}
 
-	database.DB.Save(&user)
+	if err := h.users.Save(ctx, user); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update profile")
+		return
+	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Profile updated", user)
 }
 
-// ResetPasswordRequest represents the request body for password reset
-type ResetPasswordRequest struct {
-	Email       string `json:"email" binding:"required,email"`
-	NewPassword string `json:"new_password" binding:"required,min=6"`
+// ForgotPassword issues a single-use reset token and emails it to the account owner
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req models.ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	// Always answer the same way so the endpoint can't be used to discover accounts
+	const message = "Jika email terdaftar, tautan reset password telah dikirim"
+
+	user, err := h.users.FindByEmail(ctx, req.Email)
+	if err != nil {
+		utils.SuccessResponse(c, http.StatusOK, message, nil)
+		return
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate reset token")
+		return
+	}
+
+	err = h.tx.Transaction(ctx, func(tx *repository.Repositories) error {
+		// Only the most recent token stays valid
+		if err := tx.PasswordResets.InvalidateAll(ctx, user.ID); err != nil {
+			return err
+		}
+
+		return tx.PasswordResets.Create(ctx, &models.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: utils.HashToken(token),
+			ExpiresAt: time.Now().Add(time.Duration(config.AppConfig.PasswordResetExpiryMinutes) * time.Minute),
+		})
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create reset token")
+		return
+	}
+
+	link := config.AppConfig.FrontendURL + "/reset-password?token=" + url.QueryEscape(token)
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset password Health Tracker",
+		Body: "Halo " + user.Name + ",\n\n" +
+			"Kami menerima permintaan untuk mereset password akun Anda. Buka tautan berikut untuk membuat password baru:\n\n" +
+			link + "\n\n" +
+			"Tautan ini hanya berlaku " + strconv.Itoa(config.AppConfig.PasswordResetExpiryMinutes) + " menit dan hanya dapat digunakan sekali. " +
+			"Abaikan email ini jika Anda tidak meminta reset password.",
+	}
+	// Sent after responding: waiting on SMTP only for existing accounts would reveal them
+	mailCtx := context.WithoutCancel(ctx)
+	goBackground(func() {
+		if err := mailer.Send(msg); err != nil {
+			slog.ErrorContext(mailCtx, "failed to send password reset email", "user_id", user.ID, "error", err)
+		}
+	})
+
+	utils.SuccessResponse(c, http.StatusOK, message, nil)
 }
 
-// ResetPassword resets user password by email verification
-func ResetPassword(c *gin.Context) {
-	var req ResetPasswordRequest
+// ResetPassword consumes a reset token and sets a new password
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req models.ResetPasswordRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
-	// Find user by email
-	var user models.User
-	if result := database.DB.Where("email = ?", req.Email).First(&user); result.Error != nil {
-		utils.ErrorResponse(c, http.StatusNotFound, "Email tidak ditemukan dalam sistem")
+	resetToken, err := h.resets.FindByTokenHash(ctx, utils.HashToken(req.Token))
+	if err != nil || !resetToken.IsUsable() {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Token reset tidak valid atau sudah kedaluwarsa")
 		return
 	}
 
@@ -173,12 +316,40 @@ func ResetPassword(c *gin.Context) {
 		return
 	}
 
-	// Update password
-	user.Password = hashedPassword
-	if result := database.DB.Save(&user); result.Error != nil {
+	err = h.tx.Transaction(ctx, func(tx *repository.Repositories) error {
+		// Mark the token used first so a concurrent request with the same token fails
+		consumed, err := tx.PasswordResets.MarkUsed(ctx, resetToken.ID)
+		if err != nil {
+			return err
+		}
+		if !consumed {
+			return errResetTokenUsed
+		}
+
+		// The owner proved control of the email, so a lockout from guessing ends here
+		if err := tx.Users.Update(ctx, resetToken.UserID, map[string]interface{}{
+			"password":             hashedPassword,
+			"failed_logins":        0,
+			"last_failed_login_at": nil,
+			"locked_until":         nil,
+		}); err != nil {
+			return err
+		}
+
+		// A new password signs out every existing session
+		_, err = tx.Sessions.RevokeAll(ctx, resetToken.UserID)
+		return err
+	})
+	if errors.Is(err, errResetTokenUsed) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Token reset tidak valid atau sudah kedaluwarsa")
+		return
+	}
+	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update password")
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Password berhasil direset", nil)
 }
+
+var errResetTokenUsed = errors.New("reset token already used")
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+)
+
+// background tracks work handlers start that outlives the request, like sending email and
+// building export archives
+var background sync.WaitGroup
+
+// goBackground runs fn in its own goroutine and lets WaitBackground wait for it
+func goBackground(fn func()) {
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		fn()
+	}()
+}
+
+// WaitBackground blocks until background work has finished or ctx is done. Call it on
+// shutdown after the server stopped taking requests.
+func WaitBackground(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"health-tracker/config"
+	"health-tracker/export"
+	"health-tracker/models"
+	"health-tracker/repository"
+	"health-tracker/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportData streams a ZIP of all the user's data, or starts a background job for large accounts
+func (h *AccountHandler) ExportData(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	rows, err := h.exports.CountRows(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to prepare export")
+		return
+	}
+
+	if c.Query("async") != "true" && rows <= int64(config.AppConfig.ExportAsyncThreshold) {
+		c.Header("Content-Type", "application/zip")
+		c.Header("Content-Disposition", `attachment; filename="`+exportFileName(userID)+`"`)
+		if err := h.exports.WriteArchive(ctx, userID, c.Writer); err != nil {
+			slog.ErrorContext(ctx, "failed to export data", "error", err)
+			c.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	job := models.ExportJob{UserID: userID, Status: models.ExportStatusPending}
+	if err := h.exports.CreateJob(ctx, &job); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to start export")
+		return
+	}
+
+	// The job outlives the request, so it keeps the request ID for logging but not its cancellation
+	jobCtx := context.WithoutCancel(ctx)
+	goBackground(func() { h.runExportJob(jobCtx, job.ID, userID) })
+
+	utils.SuccessResponse(c, http.StatusAccepted, "Export started", toExportJobResponse(c, job))
+}
+
+// GetExportJob returns the status of a background export, with a download link once ready
+func (h *AccountHandler) GetExportJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	job, err := h.exports.FindJob(ctx, paramID(c, "id"), userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Export not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Export status retrieved", toExportJobResponse(c, *job))
+}
+
+// DownloadExport serves a finished export through a signed, expiring link
+func (h *AccountHandler) DownloadExport(c *gin.Context) {
+	ctx := c.Request.Context()
+	jobID := c.Param("id")
+	expires := c.Query("expires")
+
+	if !utils.VerifySignedValue(exportLinkPayload(jobID, expires), c.Query("signature")) {
+		utils.ErrorResponse(c, http.StatusForbidden, "Invalid download link")
+		return
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		utils.ErrorResponse(c, http.StatusGone, "Download link has expired")
+		return
+	}
+
+	job, err := h.exports.FindCompletedJob(ctx, paramID(c, "id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Export not found")
+		return
+	}
+
+	if _, err := os.Stat(job.FilePath); err != nil {
+		utils.ErrorResponse(c, http.StatusGone, "Export file is no longer available")
+		return
+	}
+
+	c.FileAttachment(job.FilePath, exportFileName(job.UserID))
+}
+
+// ImportData restores an export archive into the current, still empty, account
+func (h *AccountHandler) ImportData(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	// The archive size is capped by the route's body limit
+	fileHeader, err := c.FormFile("archive")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		utils.PayloadTooLargeResponse(c, tooLarge.Limit)
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Upload the export ZIP as form field 'archive'")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read archive")
+		return
+	}
+	defer file.Close()
+
+	archive, err := io.ReadAll(file)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read archive")
+		return
+	}
+
+	// Importing on top of existing records would create duplicates
+	hasData, err := h.exports.HasHealthData(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to check existing data")
+		return
+	}
+	if hasData {
+		utils.ErrorResponse(c, http.StatusConflict, "Import is only possible into an account without existing health data")
+		return
+	}
+
+	result, err := h.exports.ImportArchive(ctx, userID, archive)
+	var invalid *export.ValidationError
+	if errors.As(err, &invalid) {
+		utils.FieldErrorResponse(c, invalid.Details...)
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Import failed: "+err.Error())
+		return
+	}
+	refreshInsights(ctx, h.analyzer, userID)
+
+	utils.SuccessResponse(c, http.StatusOK, "Data imported successfully", result)
+}
+
+// runExportJob writes the archive to disk in the background and records the outcome
+func (h *AccountHandler) runExportJob(ctx context.Context, jobID, userID uint) {
+	h.exports.UpdateJob(ctx, jobID, map[string]interface{}{"status": models.ExportStatusRunning})
+
+	path, err := h.writeExportFile(ctx, jobID, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "export job failed", "job_id", jobID, "error", err)
+		h.exports.UpdateJob(ctx, jobID, map[string]interface{}{
+			"status": models.ExportStatusFailed,
+			"error":  "Export failed",
+		})
+		return
+	}
+
+	now := time.Now()
+	h.exports.UpdateJob(ctx, jobID, map[string]interface{}{
+		"status":       models.ExportStatusCompleted,
+		"file_path":    path,
+		"completed_at": now,
+		"expires_at":   now.Add(time.Duration(config.AppConfig.ExportLinkExpiryHours) * time.Hour),
+	})
+}
+
+func (h *AccountHandler) writeExportFile(ctx context.Context, jobID, userID uint) (string, error) {
+	if err := os.MkdirAll(config.AppConfig.ExportDir, 0o700); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(config.AppConfig.ExportDir, fmt.Sprintf("export-%d.zip", jobID))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", err
+	}
+
+	if err := h.exports.WriteArchive(ctx, userID, f); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// exportCleanupInterval is how often expired export archives are removed from disk
+const exportCleanupInterval = time.Hour
+
+// RunExportCleanup removes expired export archives now and then every hour until ctx is
+// done, so files go away even when no further exports run
+func RunExportCleanup(ctx context.Context, exports repository.ExportRepository) {
+	ticker := time.NewTicker(exportCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		removeExpiredExports(ctx, exports)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// removeExpiredExports deletes archive files whose download links have expired
+func removeExpiredExports(ctx context.Context, exports repository.ExportRepository) {
+	expired, err := exports.ListExpiredJobs(ctx, time.Now())
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to list expired exports", "error", err)
+		return
+	}
+
+	for _, job := range expired {
+		if err := os.Remove(job.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.ErrorContext(ctx, "failed to remove expired export", "job_id", job.ID, "error", err)
+			continue
+		}
+		if err := exports.UpdateJob(ctx, job.ID, map[string]interface{}{"file_path": ""}); err != nil {
+			slog.ErrorContext(ctx, "failed to clear expired export", "job_id", job.ID, "error", err)
+		}
+	}
+}
+
+func toExportJobResponse(c *gin.Context, job models.ExportJob) models.ExportJobResponse {
+	response := models.ExportJobResponse{
+		ID:          job.ID,
+		Status:      job.Status,
+		Error:       job.Error,
+		CompletedAt: job.CompletedAt,
+		ExpiresAt:   job.ExpiresAt,
+		CreatedAt:   job.CreatedAt,
+	}
+
+	if job.Status == models.ExportStatusCompleted && job.ExpiresAt != nil && time.Now().Before(*job.ExpiresAt) {
+		id := strconv.FormatUint(uint64(job.ID), 10)
+		expires := strconv.FormatInt(job.ExpiresAt.Unix(), 10)
+
+		query := url.Values{}
+		query.Set("expires", expires)
+		query.Set("signature", utils.SignValue(exportLinkPayload(id, expires)))
+
+		// Host and X-Forwarded-* come from the client, so only the configured URL makes the link absolute
+		response.DownloadURL = config.AppConfig.PublicURL + exportDownloadPath(c, id) + "?" + query.Encode()
+	}
+
+	return response
+}
+
+// exportDownloadPath is the download route under the API prefix the current request came
+// through: /v1, or the root for legacy routes
+func exportDownloadPath(c *gin.Context, id string) string {
+	base, _, _ := strings.Cut(c.FullPath(), "/account/")
+	return base + "/account/export/download/" + id
+}
+
+func exportLinkPayload(jobID, expires string) string {
+	return "export:" + jobID + ":" + expires
+}
+
+func exportFileName(userID uint) string {
+	return fmt.Sprintf("health-tracker-export-%d-%s.zip", userID, time.Now().Format("20060102"))
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"health-tracker/config"
+	"health-tracker/export"
+	"health-tracker/models"
+	"health-tracker/utils"
+)
+
+func importRequest(t *testing.T) (*bytes.Buffer, string) {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("archive", "export.zip")
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	part.Write([]byte("PK"))
+	form.Close()
+	return &body, form.FormDataContentType()
+}
+
+func TestImportDataReportsInvalidRows(t *testing.T) {
+	exports := &fakeExports{importErr: &export.ValidationError{Details: []utils.FieldError{
+		{Field: "health_data[0].weight_kg", Rule: "max", Param: "500", Message: "must be at most 500"},
+	}}}
+	h := NewAccountHandler(newFakeUsers(), nil, exports, nil)
+	r := newTestRouter(1, "")
+	r.POST("/account/import", h.ImportData)
+
+	body, contentType := importRequest(t)
+	w := serve(r, http.MethodPost, "/account/import", body, "Content-Type", contentType)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("import = %d, want 400: %s", w.Code, w.Body)
+	}
+	resp := decodeResponse(t, w, nil)
+	if resp.Code != utils.CodeValidationFailed || len(resp.Details) != 1 || resp.Details[0].Field != "health_data[0].weight_kg" {
+		t.Errorf("response = %+v, want the row's field error", resp.APIResponse)
+	}
+}
+
+func TestImportDataRefusesAccountWithData(t *testing.T) {
+	exports := &fakeExports{hasData: true}
+	h := NewAccountHandler(newFakeUsers(), nil, exports, nil)
+	r := newTestRouter(1, "")
+	r.POST("/account/import", h.ImportData)
+
+	body, contentType := importRequest(t)
+	if w := serve(r, http.MethodPost, "/account/import", body, "Content-Type", contentType); w.Code != http.StatusConflict {
+		t.Errorf("import into account with data = %d, want 409", w.Code)
+	}
+	if exports.imported != 0 {
+		t.Error("archive imported into an account with data")
+	}
+}
+
+func TestExportJobRunsInBackgroundAndDrains(t *testing.T) {
+	config.AppConfig.ExportDir = t.TempDir()
+	exports := &fakeExports{release: make(chan struct{})}
+	h := NewAccountHandler(newFakeUsers(), nil, exports, nil)
+	r := newTestRouter(1, "")
+	r.GET("/account/export", h.ExportData)
+
+	// The request returns while the archive is still being written
+	if w := serve(r, http.MethodGet, "/account/export?async=true", nil); w.Code != http.StatusAccepted {
+		t.Fatalf("export = %d, want 202: %s", w.Code, w.Body)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := WaitBackground(ctx); err == nil {
+		t.Fatal("WaitBackground returned before the export job finished")
+	}
+
+	close(exports.release)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := WaitBackground(ctx); err != nil {
+		t.Fatalf("WaitBackground: %v", err)
+	}
+	if job := exports.jobs[0]; job.Status != models.ExportStatusCompleted || job.FilePath == "" {
+		t.Errorf("job = %+v, want a completed export on disk", job)
+	}
+}
+
+func TestExportDownloadURLIgnoresRequestHost(t *testing.T) {
+	expires := time.Now().Add(time.Hour)
+	exports := &fakeExports{jobs: []*models.ExportJob{{ID: 1, UserID: 1, Status: models.ExportStatusCompleted, ExpiresAt: &expires}}}
+	h := NewAccountHandler(newFakeUsers(), nil, exports, nil)
+	r := newTestRouter(1, "")
+	r.GET("/v1/account/export/jobs/:id", h.GetExportJob)
+
+	previous := config.AppConfig.PublicURL
+	t.Cleanup(func() { config.AppConfig.PublicURL = previous })
+
+	for _, tt := range []struct {
+		publicURL string
+		prefix    string
+	}{
+		{"", "/v1/account/export/download/1?"},
+		{"https://api.example.com", "https://api.example.com/v1/account/export/download/1?"},
+	} {
+		config.AppConfig.PublicURL = tt.publicURL
+		w := serve(r, http.MethodGet, "/v1/account/export/jobs/1", nil, "X-Forwarded-Proto", "https", "X-Forwarded-Host", "attacker.example")
+		if w.Code != http.StatusOK {
+			t.Fatalf("job = %d, want 200: %s", w.Code, w.Body)
+		}
+		var job models.ExportJobResponse
+		decodeResponse(t, w, &job)
+		if !strings.HasPrefix(job.DownloadURL, tt.prefix) {
+			t.Errorf("PUBLIC_URL %q: download URL = %q, want prefix %q", tt.publicURL, job.DownloadURL, tt.prefix)
+		}
+	}
+}
@@ -0,0 +1,565 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"sort"
+	"time"
+
+	"health-tracker/database"
+	"health-tracker/export"
+	"health-tracker/models"
+	"health-tracker/repository"
+)
+
+// The fakes embed their repository interface and implement only the methods the
+// handler tests reach; anything else panics on the nil embedded interface.
+
+type fakeUsers struct {
+	repository.UserRepository
+	users   map[uint]*models.User
+	updates []map[string]interface{}
+	err     error
+}
+
+func newFakeUsers(users ...models.User) *fakeUsers {
+	f := &fakeUsers{users: map[uint]*models.User{}}
+	for i := range users {
+		f.users[users[i].ID] = &users[i]
+	}
+	return f
+}
+
+func (f *fakeUsers) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	found := *user
+	return &found, nil
+}
+
+func (f *fakeUsers) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	for _, user := range f.users {
+		if user.Email == email {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeUsers) Update(ctx context.Context, id uint, fields map[string]interface{}) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.updates = append(f.updates, fields)
+	user, ok := f.users[id]
+	if !ok {
+		return nil
+	}
+	for key, value := range fields {
+		switch key {
+		case "role":
+			user.Role = value.(string)
+		case "suspended_at":
+			at, _ := value.(time.Time)
+			user.SuspendedAt = &at
+		case "suspended_reason":
+			user.SuspendedReason = value.(string)
+		case "locked_until":
+			user.LockedUntil = nil
+			if at, ok := value.(time.Time); ok {
+				user.LockedUntil = &at
+			}
+		case "last_failed_login_at":
+			user.LastFailedLoginAt = nil
+			if at, ok := value.(time.Time); ok {
+				user.LastFailedLoginAt = &at
+			}
+		case "password":
+			user.Password = value.(string)
+		case "failed_logins":
+			user.FailedLogins = value.(int)
+		case "login_challenge":
+			user.LoginChallenge = value.(string)
+		case "weight_kg":
+			user.WeightKg = value.(float64)
+		case "height_cm":
+			user.HeightCm = value.(float64)
+		}
+	}
+	return nil
+}
+
+func (f *fakeUsers) RecordFailedLogin(ctx context.Context, id uint, at time.Time, window time.Duration) (int, error) {
+	user := f.users[id]
+	user.FailedLogins++
+	user.LastFailedLoginAt = &at
+	return user.FailedLogins, nil
+}
+
+func (f *fakeUsers) AdvanceTwoFactorStep(ctx context.Context, id uint, step int64) (bool, error) {
+	user := f.users[id]
+	if user.TwoFactorLastStep >= step {
+		return false, nil
+	}
+	user.TwoFactorLastStep = step
+	return true, nil
+}
+
+func (f *fakeUsers) ConsumeLoginChallenge(ctx context.Context, id uint, hash string) (bool, error) {
+	user := f.users[id]
+	if hash == "" || user.LoginChallenge != hash {
+		return false, nil
+	}
+	user.LoginChallenge = ""
+	return true, nil
+}
+
+type fakeSessions struct {
+	repository.SessionRepository
+	sessions  []*models.Session
+	revokeErr error
+}
+
+func (f *fakeSessions) Create(ctx context.Context, session *models.Session) error {
+	session.ID = uint(len(f.sessions) + 1)
+	stored := *session
+	f.sessions = append(f.sessions, &stored)
+	return nil
+}
+
+func (f *fakeSessions) FindByTokenHash(ctx context.Context, hash string) (*models.Session, error) {
+	for _, session := range f.sessions {
+		if session.RefreshTokenHash == hash {
+			found := *session
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeSessions) Rotate(ctx context.Context, oldHash string, session *models.Session) (int64, error) {
+	for _, stored := range f.sessions {
+		if stored.RefreshTokenHash == oldHash && stored.IsActive() {
+			stored.RefreshTokenHash = session.RefreshTokenHash
+			stored.LastUsedAt = session.LastUsedAt
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (f *fakeSessions) RevokeAll(ctx context.Context, userID uint) (int64, error) {
+	if f.revokeErr != nil {
+		return 0, f.revokeErr
+	}
+	now := time.Now()
+	var revoked int64
+	for _, session := range f.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+type fakeLoginAttempts struct {
+	repository.LoginAttemptRepository
+	attempts []models.LoginAttempt
+}
+
+func (f *fakeLoginAttempts) Create(ctx context.Context, attempt *models.LoginAttempt) error {
+	f.attempts = append(f.attempts, *attempt)
+	return nil
+}
+
+type fakePasswordResets struct {
+	repository.PasswordResetRepository
+	tokens []*models.PasswordResetToken
+}
+
+func (f *fakePasswordResets) InvalidateAll(ctx context.Context, userID uint) error {
+	now := time.Now()
+	for _, token := range f.tokens {
+		if token.UserID == userID && token.UsedAt == nil {
+			token.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+func (f *fakePasswordResets) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	token.ID = uint(len(f.tokens) + 1)
+	stored := *token
+	f.tokens = append(f.tokens, &stored)
+	return nil
+}
+
+func (f *fakePasswordResets) FindByTokenHash(ctx context.Context, hash string) (*models.PasswordResetToken, error) {
+	for _, token := range f.tokens {
+		if token.TokenHash == hash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakePasswordResets) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	for _, token := range f.tokens {
+		if token.ID == id && token.UsedAt == nil {
+			now := time.Now()
+			token.UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeRecoveryCodes struct {
+	repository.RecoveryCodeRepository
+	unused map[string]bool // by hash
+}
+
+func (f *fakeRecoveryCodes) Use(ctx context.Context, userID uint, hash string) (bool, error) {
+	if !f.unused[hash] {
+		return false, nil
+	}
+	delete(f.unused, hash)
+	return true, nil
+}
+
+type fakeExports struct {
+	repository.ExportRepository
+	hasData   bool
+	importErr error
+	imported  int
+	jobs      []*models.ExportJob
+	release   chan struct{} // WriteArchive waits for it when set
+}
+
+func (f *fakeExports) CountRows(ctx context.Context, userID uint) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeExports) WriteArchive(ctx context.Context, userID uint, w io.Writer) error {
+	if f.release != nil {
+		<-f.release
+	}
+	_, err := w.Write([]byte("PK"))
+	return err
+}
+
+func (f *fakeExports) CreateJob(ctx context.Context, job *models.ExportJob) error {
+	job.ID = uint(len(f.jobs) + 1)
+	stored := *job
+	f.jobs = append(f.jobs, &stored)
+	return nil
+}
+
+func (f *fakeExports) FindJob(ctx context.Context, id, userID uint) (*models.ExportJob, error) {
+	for _, job := range f.jobs {
+		if job.ID == id && job.UserID == userID {
+			found := *job
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeExports) UpdateJob(ctx context.Context, id uint, fields map[string]interface{}) error {
+	for _, job := range f.jobs {
+		if job.ID != id {
+			continue
+		}
+		for key, value := range fields {
+			switch key {
+			case "status":
+				job.Status = value.(string)
+			case "file_path":
+				job.FilePath = value.(string)
+			case "expires_at":
+				at := value.(time.Time)
+				job.ExpiresAt = &at
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeExports) HasHealthData(ctx context.Context, userID uint) (bool, error) {
+	return f.hasData, nil
+}
+
+func (f *fakeExports) ImportArchive(ctx context.Context, userID uint, archive []byte) (*export.ImportResult, error) {
+	if f.importErr != nil {
+		return nil, f.importErr
+	}
+	f.imported++
+	return &export.ImportResult{Restored: map[string]int{}}, nil
+}
+
+type fakeHealth struct {
+	repository.HealthRepository
+	records []models.HealthData
+}
+
+func (f *fakeHealth) Create(ctx context.Context, record *models.HealthData) error {
+	record.ID = uint(len(f.records) + 1)
+	f.records = append(f.records, *record)
+	return nil
+}
+
+func (f *fakeHealth) Find(ctx context.Context, id, userID uint) (*models.HealthData, error) {
+	for _, record := range f.records {
+		if record.ID == id && record.UserID == userID {
+			return &record, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeHealth) Save(ctx context.Context, record *models.HealthData) error {
+	for i := range f.records {
+		if f.records[i].ID == record.ID {
+			f.records[i] = *record
+		}
+	}
+	return nil
+}
+
+func (f *fakeHealth) Delete(ctx context.Context, record *models.HealthData) error {
+	for i := range f.records {
+		if f.records[i].ID == record.ID {
+			f.records = append(f.records[:i], f.records[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// Latest returns the record with the newest RecordDate
+func (f *fakeHealth) Latest(ctx context.Context, userID uint) (*models.HealthData, error) {
+	var latest *models.HealthData
+	for i, record := range f.records {
+		if record.UserID == userID && (latest == nil || record.RecordDate.After(latest.RecordDate)) {
+			latest = &f.records[i]
+		}
+	}
+	if latest == nil {
+		return nil, repository.ErrNotFound
+	}
+	found := *latest
+	return &found, nil
+}
+
+func (f *fakeHealth) ListSince(ctx context.Context, userID uint, since time.Time) ([]models.HealthData, error) {
+	var records []models.HealthData
+	for _, record := range f.records {
+		if record.UserID == userID && !record.RecordDate.Before(since) {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].RecordDate.Before(records[j].RecordDate) })
+	return records, nil
+}
+
+type fakeMeasurements struct {
+	repository.MeasurementRepository
+	measurements []models.Measurement
+}
+
+func (f *fakeMeasurements) Create(ctx context.Context, m *models.Measurement) error {
+	m.ID = uint(len(f.measurements) + 1)
+	f.measurements = append(f.measurements, *m)
+	return nil
+}
+
+func (f *fakeMeasurements) ListSince(ctx context.Context, userID uint, since time.Time) ([]models.Measurement, error) {
+	var rows []models.Measurement
+	for _, m := range f.measurements {
+		if m.UserID == userID && !m.MeasuredAt.Before(since) {
+			rows = append(rows, m)
+		}
+	}
+	return rows, nil
+}
+
+func (f *fakeMeasurements) Delete(ctx context.Context, id, userID uint) (int64, error) {
+	for i, m := range f.measurements {
+		if m.ID == id && m.UserID == userID {
+			f.measurements = append(f.measurements[:i], f.measurements[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+type fakeGoals struct {
+	repository.GoalRepository
+	goals []models.Goal
+}
+
+func (f *fakeGoals) Create(ctx context.Context, goal *models.Goal) error {
+	goal.ID = uint(len(f.goals) + 1)
+	f.goals = append(f.goals, *goal)
+	return nil
+}
+
+func (f *fakeGoals) ListByUser(ctx context.Context, userID uint) ([]models.Goal, error) {
+	var goals []models.Goal
+	for _, goal := range f.goals {
+		if goal.UserID == userID {
+			goals = append(goals, goal)
+		}
+	}
+	return goals, nil
+}
+
+type fakeInsights struct {
+	repository.InsightRepository
+	syncs int
+}
+
+func (f *fakeInsights) Sync(ctx context.Context, userID uint, week string, insights []models.Insight) (int, error) {
+	f.syncs++
+	return 0, nil
+}
+
+type fakeSymptoms struct {
+	repository.SymptomRepository
+	symptoms []models.Symptom
+}
+
+func (f *fakeSymptoms) Create(ctx context.Context, symptom *models.Symptom) error {
+	symptom.ID = uint(len(f.symptoms) + 1)
+	f.symptoms = append(f.symptoms, *symptom)
+	return nil
+}
+
+func (f *fakeSymptoms) Recent(ctx context.Context, userID uint, symptomType string, limit int) ([]models.Symptom, error) {
+	var symptoms []models.Symptom
+	for _, symptom := range f.symptoms {
+		if symptom.UserID == userID && (symptomType == "" || symptom.SymptomType == symptomType) {
+			symptoms = append(symptoms, symptom)
+		}
+	}
+	if len(symptoms) > limit {
+		symptoms = symptoms[:limit]
+	}
+	return symptoms, nil
+}
+
+type fakeFamily struct {
+	repository.FamilyRepository
+	links []models.FamilyMember
+}
+
+func (f *fakeFamily) FindViewable(ctx context.Context, id, ownerID uint) (*models.FamilyMember, error) {
+	for _, link := range f.links {
+		if link.ID == id && link.OwnerID == ownerID && link.Status == "approved" && link.CanViewHealth {
+			found := link
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+type fakeForum struct {
+	repository.ForumRepository
+	posts   []models.Post
+	deleted []uint
+}
+
+func (f *fakeForum) FindPost(ctx context.Context, id uint, withComments bool) (*models.Post, error) {
+	for _, post := range f.posts {
+		if post.ID == id {
+			found := post
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeForum) DeletePost(ctx context.Context, id uint) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+type fakeWater struct {
+	repository.WaterRepository
+	intakes []*models.WaterIntake
+}
+
+func (f *fakeWater) FindByDate(ctx context.Context, userID uint, date string) (*models.WaterIntake, error) {
+	for _, intake := range f.intakes {
+		if intake.UserID == userID && intake.Date == date {
+			found := *intake
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeWater) Create(ctx context.Context, intake *models.WaterIntake) error {
+	intake.ID = uint(len(f.intakes) + 1)
+	stored := *intake
+	f.intakes = append(f.intakes, &stored)
+	return nil
+}
+
+func (f *fakeWater) Save(ctx context.Context, intake *models.WaterIntake) error {
+	for _, stored := range f.intakes {
+		if stored.ID == intake.ID {
+			*stored = *intake
+		}
+	}
+	return nil
+}
+
+type fakeReminders struct {
+	repository.ReminderRepository
+	reminders []models.Reminder
+}
+
+func (f *fakeReminders) Delete(ctx context.Context, id, userID uint) (int64, error) {
+	for i, reminder := range f.reminders {
+		if reminder.ID == id && reminder.UserID == userID {
+			f.reminders = append(f.reminders[:i], f.reminders[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+type fakeArticles struct {
+	repository.ArticleRepository
+	articles []models.Article
+}
+
+func (f *fakeArticles) FindByID(ctx context.Context, id uint) (*models.Article, error) {
+	for _, article := range f.articles {
+		if article.ID == id {
+			found := article
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+type fakeSystem struct {
+	pingErr   error
+	schemaErr error
+	schema    database.SchemaState
+}
+
+func (f *fakeSystem) Ping(ctx context.Context) error {
+	return f.pingErr
+}
+
+func (f *fakeSystem) SchemaState(ctx context.Context) (database.SchemaState, error) {
+	return f.schema, f.schemaErr
+}
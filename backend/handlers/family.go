@@ -1,29 +1,43 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
-	"strconv"
 
-	"health-tracker/database"
 	"health-tracker/models"
+	"health-tracker/repository"
 	"health-tracker/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+// FamilyHandler serves family links and shared health views
+type FamilyHandler struct {
+	family   repository.FamilyRepository
+	users    repository.UserRepository
+	health   repository.HealthRepository
+	symptoms repository.SymptomRepository
+}
+
+// NewFamilyHandler creates a FamilyHandler
+func NewFamilyHandler(family repository.FamilyRepository, users repository.UserRepository, health repository.HealthRepository, symptoms repository.SymptomRepository) *FamilyHandler {
+	return &FamilyHandler{family: family, users: users, health: health, symptoms: symptoms}
+}
+
 // InviteFamilyMember sends an invitation to a family member
-func InviteFamilyMember(c *gin.Context) {
+func (h *FamilyHandler) InviteFamilyMember(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
 	var req models.FamilyInviteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
 	// Check if member exists
-	var memberUser models.User
-	if result := database.DB.Where("email = ?", req.MemberEmail).First(&memberUser); result.Error != nil {
+	memberUser, err := h.users.FindByEmail(ctx, req.MemberEmail)
+	if err != nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "User with this email not found. They need to register first.")
 		return
 	}
@@ -35,8 +49,7 @@ func InviteFamilyMember(c *gin.Context) {
 	}
 
 	// Check if invitation already exists
-	var existing models.FamilyMember
-	if result := database.DB.Where("owner_id = ? AND member_user_id = ?", userID, memberUser.ID).First(&existing); result.RowsAffected > 0 {
+	if _, err := h.family.Find(ctx, userID, memberUser.ID); err == nil {
 		utils.ErrorResponse(c, http.StatusConflict, "Invitation already sent to this user")
 		return
 	}
@@ -51,7 +64,7 @@ func InviteFamilyMember(c *gin.Context) {
 		CanViewHealth: true,
 	}
 
-	if result := database.DB.Create(&invitation); result.Error != nil {
+	if err := h.family.Create(ctx, &invitation); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to send invitation")
 		return
 	}
@@ -60,21 +73,23 @@ func InviteFamilyMember(c *gin.Context) {
 }
 
 // GetFamilyMembers returns approved family members
-func GetFamilyMembers(c *gin.Context) {
+func (h *FamilyHandler) GetFamilyMembers(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
-	var members []models.FamilyMember
-	database.DB.Where("owner_id = ? AND status = ?", userID, "approved").Find(&members)
+	members, err := h.family.ListByOwner(ctx, userID, "approved")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch family members")
+		return
+	}
 
 	// Get user details for each member
 	var response []models.FamilyMemberResponse
 	for _, m := range members {
-		var user models.User
-		database.DB.First(&user, m.MemberUserID)
 		response = append(response, models.FamilyMemberResponse{
 			ID:            m.ID,
 			MemberEmail:   m.MemberEmail,
-			MemberName:    user.Name,
+			MemberName:    h.userName(ctx, m.MemberUserID),
 			Relationship:  m.Relationship,
 			Status:        m.Status,
 			CanViewHealth: m.CanViewHealth,
@@ -86,18 +101,24 @@ func GetFamilyMembers(c *gin.Context) {
 }
 
 // GetFamilyRequests returns pending invitations for current user
-func GetFamilyRequests(c *gin.Context) {
+func (h *FamilyHandler) GetFamilyRequests(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
 	// Invitations sent to me (where I am the member)
-	var received []models.FamilyMember
-	database.DB.Where("member_user_id = ? AND status = ?", userID, "pending").Find(&received)
+	received, err := h.family.ListByMember(ctx, userID, "pending")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch family requests")
+		return
+	}
 
 	// Get owner details
 	var receivedResponse []map[string]interface{}
 	for _, r := range received {
 		var owner models.User
-		database.DB.First(&owner, r.OwnerID)
+		if found, err := h.users.FindByID(ctx, r.OwnerID); err == nil {
+			owner = *found
+		}
 		receivedResponse = append(receivedResponse, map[string]interface{}{
 			"id":           r.ID,
 			"from_email":   owner.Email,
@@ -108,8 +129,11 @@ func GetFamilyRequests(c *gin.Context) {
 	}
 
 	// Invitations I sent
-	var sent []models.FamilyMember
-	database.DB.Where("owner_id = ? AND status = ?", userID, "pending").Find(&sent)
+	sent, err := h.family.ListByOwner(ctx, userID, "pending")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch family requests")
+		return
+	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Family requests retrieved", gin.H{
 		"received": receivedResponse,
@@ -118,64 +142,65 @@ func GetFamilyRequests(c *gin.Context) {
 }
 
 // ApproveFamilyRequest approves a family invitation
-func ApproveFamilyRequest(c *gin.Context) {
+func (h *FamilyHandler) ApproveFamilyRequest(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
-	requestID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 
-	var invitation models.FamilyMember
-	if result := database.DB.Where("id = ? AND member_user_id = ? AND status = ?", requestID, userID, "pending").First(&invitation); result.Error != nil {
+	invitation, err := h.family.FindPendingInvitation(ctx, paramID(c, "id"), userID)
+	if err != nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "Invitation not found")
 		return
 	}
 
 	invitation.Status = "approved"
-	database.DB.Save(&invitation)
-
-	// Create reverse relationship so member can also see owner's health
-	reverseRelation := models.FamilyMember{
-		OwnerID:       userID,
-		MemberUserID:  invitation.OwnerID,
-		MemberEmail:   "",
-		Relationship:  getReverseRelationship(invitation.Relationship),
-		Status:        "approved",
-		CanViewHealth: true,
+	if err := h.family.Save(ctx, invitation); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to approve invitation")
+		return
 	}
-	
-	// Check if reverse doesn't exist
-	var existing models.FamilyMember
-	if database.DB.Where("owner_id = ? AND member_user_id = ?", userID, invitation.OwnerID).First(&existing).RowsAffected == 0 {
-		database.DB.Create(&reverseRelation)
+
+	// Create reverse relationship so member can also see owner's health, unless it already exists
+	if _, err := h.family.Find(ctx, userID, invitation.OwnerID); err != nil {
+		h.family.Create(ctx, &models.FamilyMember{
+			OwnerID:       userID,
+			MemberUserID:  invitation.OwnerID,
+			MemberEmail:   "",
+			Relationship:  getReverseRelationship(invitation.Relationship),
+			Status:        "approved",
+			CanViewHealth: true,
+		})
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Invitation approved", invitation)
 }
 
 // RejectFamilyRequest rejects a family invitation
-func RejectFamilyRequest(c *gin.Context) {
+func (h *FamilyHandler) RejectFamilyRequest(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
-	requestID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 
-	var invitation models.FamilyMember
-	if result := database.DB.Where("id = ? AND member_user_id = ? AND status = ?", requestID, userID, "pending").First(&invitation); result.Error != nil {
+	invitation, err := h.family.FindPendingInvitation(ctx, paramID(c, "id"), userID)
+	if err != nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "Invitation not found")
 		return
 	}
 
 	invitation.Status = "rejected"
-	database.DB.Save(&invitation)
+	if err := h.family.Save(ctx, invitation); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to reject invitation")
+		return
+	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Invitation rejected", nil)
 }
 
 // GetFamilyMemberHealth returns health data of a family member
-func GetFamilyMemberHealth(c *gin.Context) {
+func (h *FamilyHandler) GetFamilyMemberHealth(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
-	familyMemberID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 
 	// Find family member record by its ID
-	var familyMember models.FamilyMember
-	if result := database.DB.Where("id = ? AND owner_id = ? AND status = ? AND can_view_health = ?", 
-		familyMemberID, userID, "approved", true).First(&familyMember); result.Error != nil {
+	familyMember, err := h.family.FindViewable(ctx, paramID(c, "id"), userID)
+	if err != nil {
 		utils.ErrorResponse(c, http.StatusForbidden, "You don't have permission to view this member's health")
 		return
 	}
@@ -183,20 +208,14 @@ func GetFamilyMemberHealth(c *gin.Context) {
 	// Get the actual member user ID from the family member record
 	memberUserID := familyMember.MemberUserID
 
-	// Get member info
-	var memberUser models.User
-	database.DB.First(&memberUser, memberUserID)
-
 	// Get latest health data
-	var latestHealth models.HealthData
-	database.DB.Where("user_id = ?", memberUserID).Order("record_date desc").First(&latestHealth)
+	latestHealth := latestHealthData(ctx, h.health, memberUserID)
 
 	// Get recent symptoms
-	var recentSymptoms []models.Symptom
-	database.DB.Where("user_id = ?", memberUserID).Order("logged_at desc").Limit(5).Find(&recentSymptoms)
+	recentSymptoms, _ := h.symptoms.Recent(ctx, memberUserID, "", 5)
 
 	response := models.FamilyHealthView{
-		MemberName:     memberUser.Name,
+		MemberName:     h.userName(ctx, memberUserID),
 		Relationship:   familyMember.Relationship,
 		LatestHealth:   &latestHealth,
 		BMICategory:    models.GetBMICategory(latestHealth.BMI),
@@ -207,22 +226,32 @@ func GetFamilyMemberHealth(c *gin.Context) {
 }
 
 // RemoveFamilyMember removes a family member connection
-func RemoveFamilyMember(c *gin.Context) {
+func (h *FamilyHandler) RemoveFamilyMember(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
-	memberID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	memberID := paramID(c, "id")
 
-	result := database.DB.Where("owner_id = ? AND member_user_id = ?", userID, memberID).Delete(&models.FamilyMember{})
-	if result.RowsAffected == 0 {
+	deleted, err := h.family.Delete(ctx, userID, memberID)
+	if err != nil || deleted == 0 {
 		utils.ErrorResponse(c, http.StatusNotFound, "Family member not found")
 		return
 	}
 
 	// Also remove reverse relationship
-	database.DB.Where("owner_id = ? AND member_user_id = ?", memberID, userID).Delete(&models.FamilyMember{})
+	h.family.Delete(ctx, memberID, userID)
 
 	utils.SuccessResponse(c, http.StatusOK, "Family member removed", nil)
 }
 
+// userName returns the user's display name, or "" if the user no longer exists
+func (h *FamilyHandler) userName(ctx context.Context, id uint) string {
+	user, err := h.users.FindByID(ctx, id)
+	if err != nil {
+		return ""
+	}
+	return user.Name
+}
+
 func getReverseRelationship(rel string) string {
 	switch rel {
 	case "parent":
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"health-tracker/models"
+)
+
+func TestGetFamilyMemberHealthRequiresApprovedLink(t *testing.T) {
+	family := &fakeFamily{links: []models.FamilyMember{
+		{ID: 1, OwnerID: 1, MemberUserID: 2, Relationship: "parent", Status: "approved", CanViewHealth: true},
+		{ID: 2, OwnerID: 1, MemberUserID: 3, Relationship: "sibling", Status: "pending", CanViewHealth: true},
+	}}
+	users := newFakeUsers(models.User{ID: 2, Name: "Budi"}, models.User{ID: 3, Name: "Citra"})
+	health := &fakeHealth{records: []models.HealthData{
+		{ID: 1, UserID: 2, WeightKg: 70, HeightCm: 170, BMI: 24.2, RecordDate: time.Now().Add(-24 * time.Hour)},
+		{ID: 2, UserID: 2, WeightKg: 68, HeightCm: 170, BMI: 23.5, RecordDate: time.Now()},
+	}}
+	h := NewFamilyHandler(family, users, health, &fakeSymptoms{})
+	r := newTestRouter(1, "")
+	r.GET("/family/:id/health", h.GetFamilyMemberHealth)
+
+	w := serve(r, http.MethodGet, "/family/1/health", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("approved member = %d, want 200: %s", w.Code, w.Body)
+	}
+	var view models.FamilyHealthView
+	decodeResponse(t, w, &view)
+	if view.MemberName != "Budi" || view.LatestHealth == nil || view.LatestHealth.WeightKg != 68 {
+		t.Errorf("view = %+v, want Budi's latest record", view)
+	}
+
+	if w := serve(r, http.MethodGet, "/family/2/health", nil); w.Code != http.StatusForbidden {
+		t.Errorf("pending member = %d, want 403", w.Code)
+	}
+}
@@ -1,266 +1,218 @@
 package handlers
 
 import (
-	"health-tracker/database"
+	"health-tracker/middleware"
 	"health-tracker/models"
+	"health-tracker/repository"
+	"health-tracker/utils"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// ForumHandler serves the community forum
+type ForumHandler struct {
+	forum repository.ForumRepository
+}
+
+// NewForumHandler creates a ForumHandler
+func NewForumHandler(forum repository.ForumRepository) *ForumHandler {
+	return &ForumHandler{forum: forum}
+}
+
 // GetPosts returns all forum posts
-func GetPosts(c *gin.Context) {
-	userID, _ := c.Get("userID")
-	var posts []models.Post
+func (h *ForumHandler) GetPosts(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	posts, err := h.forum.ListPosts(ctx)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch posts")
+		return
+	}
 
-	if err := database.DB.Preload("User").Order("created_at DESC").Find(&posts).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+	postIDs := make([]uint, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+	}
+
+	liked, err := h.forum.LikedPostIDs(ctx, userID, postIDs)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch posts")
 		return
 	}
 
 	// Build response with user names and like status
 	var response []models.PostResponse
 	for _, post := range posts {
-		isLiked := false
-		if userID != nil {
-			var like models.Like
-			if err := database.DB.Where("post_id = ? AND user_id = ?", post.ID, userID).First(&like).Error; err == nil {
-				isLiked = true
-			}
-		}
-
-		response = append(response, models.PostResponse{
-			ID:            post.ID,
-			UserID:        post.UserID,
-			UserName:      post.User.Name,
-			Title:         post.Title,
-			Content:       post.Content,
-			LikesCount:    post.LikesCount,
-			CommentsCount: post.CommentsCount,
-			IsLiked:       isLiked,
-			CreatedAt:     post.CreatedAt,
-		})
+		response = append(response, toPostResponse(post, liked[post.ID]))
 	}
 
-	c.JSON(http.StatusOK, response)
+	utils.SuccessResponse(c, http.StatusOK, "Posts retrieved", response)
 }
 
 // CreatePost creates a new forum post
-func CreatePost(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
+func (h *ForumHandler) CreatePost(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
 
-	var input struct {
-		Title   string `json:"title" binding:"required"`
-		Content string `json:"content" binding:"required"`
-	}
+	var input models.CreatePostRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
 	post := models.Post{
-		UserID:    userID.(uint),
+		UserID:    userID,
 		Title:     input.Title,
 		Content:   input.Content,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	if err := database.DB.Create(&post).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create post"})
+	if err := h.forum.CreatePost(ctx, &post); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create post")
 		return
 	}
 
-	// Load user data
-	database.DB.Preload("User").First(&post, post.ID)
-
-	c.JSON(http.StatusCreated, models.PostResponse{
-		ID:            post.ID,
-		UserID:        post.UserID,
-		UserName:      post.User.Name,
-		Title:         post.Title,
-		Content:       post.Content,
-		LikesCount:    0,
-		CommentsCount: 0,
-		IsLiked:       false,
-		CreatedAt:     post.CreatedAt,
-	})
+	utils.SuccessResponse(c, http.StatusCreated, "Post created", toPostResponse(post, false))
 }
 
 // GetPost returns a single post with comments
-func GetPost(c *gin.Context) {
-	userID, _ := c.Get("userID")
-	id := c.Param("id")
-	var post models.Post
+func (h *ForumHandler) GetPost(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
 
-	if err := database.DB.Preload("User").Preload("Comments.User").First(&post, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+	post, err := h.forum.FindPost(ctx, paramID(c, "id"), true)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Post not found")
 		return
 	}
 
-	isLiked := false
-	if userID != nil {
-		var like models.Like
-		if err := database.DB.Where("post_id = ? AND user_id = ?", post.ID, userID).First(&like).Error; err == nil {
-			isLiked = true
-		}
+	liked, err := h.forum.LikedPostIDs(ctx, userID, []uint{post.ID})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch post")
+		return
 	}
 
 	// Build comments response
 	var comments []models.CommentResponse
 	for _, comment := range post.Comments {
-		comments = append(comments, models.CommentResponse{
-			ID:        comment.ID,
-			PostID:    comment.PostID,
-			UserID:    comment.UserID,
-			UserName:  comment.User.Name,
-			Content:   comment.Content,
-			CreatedAt: comment.CreatedAt,
-		})
+		comments = append(comments, toCommentResponse(comment))
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"post": models.PostResponse{
-			ID:            post.ID,
-			UserID:        post.UserID,
-			UserName:      post.User.Name,
-			Title:         post.Title,
-			Content:       post.Content,
-			LikesCount:    post.LikesCount,
-			CommentsCount: post.CommentsCount,
-			IsLiked:       isLiked,
-			CreatedAt:     post.CreatedAt,
-		},
+	utils.SuccessResponse(c, http.StatusOK, "Post retrieved", gin.H{
+		"post":     toPostResponse(*post, liked[post.ID]),
 		"comments": comments,
 	})
 }
 
 // AddComment adds a comment to a post
-func AddComment(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
+func (h *ForumHandler) AddComment(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
 
-	postID := c.Param("id")
-	var post models.Post
-	if err := database.DB.First(&post, postID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+	post, err := h.forum.FindPost(ctx, paramID(c, "id"), false)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Post not found")
 		return
 	}
 
-	var input struct {
-		Content string `json:"content" binding:"required"`
-	}
+	var input models.CreateCommentRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
 	comment := models.Comment{
 		PostID:    post.ID,
-		UserID:    userID.(uint),
+		UserID:    userID,
 		Content:   input.Content,
 		CreatedAt: time.Now(),
 	}
 
-	if err := database.DB.Create(&comment).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add comment"})
+	if err := h.forum.AddComment(ctx, &comment); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to add comment")
 		return
 	}
 
-	// Update comments count
-	database.DB.Model(&post).Update("comments_count", post.CommentsCount+1)
-
-	// Load user data
-	database.DB.Preload("User").First(&comment, comment.ID)
-
-	c.JSON(http.StatusCreated, models.CommentResponse{
-		ID:        comment.ID,
-		PostID:    comment.PostID,
-		UserID:    comment.UserID,
-		UserName:  comment.User.Name,
-		Content:   comment.Content,
-		CreatedAt: comment.CreatedAt,
-	})
+	utils.SuccessResponse(c, http.StatusCreated, "Comment added", toCommentResponse(comment))
 }
 
 // ToggleLike toggles a like on a post
-func ToggleLike(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
+func (h *ForumHandler) ToggleLike(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
 
-	postID := c.Param("id")
-	var post models.Post
-	if err := database.DB.First(&post, postID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+	post, err := h.forum.FindPost(ctx, paramID(c, "id"), false)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Post not found")
 		return
 	}
 
-	var existingLike models.Like
-	isLiked := false
-
-	if err := database.DB.Where("post_id = ? AND user_id = ?", post.ID, userID).First(&existingLike).Error; err == nil {
-		// Unlike - remove existing like
-		database.DB.Delete(&existingLike)
-		database.DB.Model(&post).Update("likes_count", post.LikesCount-1)
-		isLiked = false
-	} else {
-		// Like - add new like
-		like := models.Like{
-			PostID:    post.ID,
-			UserID:    userID.(uint),
-			CreatedAt: time.Now(),
-		}
-		database.DB.Create(&like)
-		database.DB.Model(&post).Update("likes_count", post.LikesCount+1)
-		isLiked = true
+	isLiked, likesCount, err := h.forum.ToggleLike(ctx, post.ID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update like")
+		return
 	}
 
-	// Get updated count
-	database.DB.First(&post, postID)
-
-	c.JSON(http.StatusOK, gin.H{
+	utils.SuccessResponse(c, http.StatusOK, "Like updated", gin.H{
 		"is_liked":    isLiked,
-		"likes_count": post.LikesCount,
+		"likes_count": likesCount,
 	})
 }
 
-// DeletePost deletes a post (only by owner)
-func DeletePost(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+// DeletePost deletes a post (by its owner or a moderator)
+func (h *ForumHandler) DeletePost(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	post, err := h.forum.FindPost(ctx, paramID(c, "id"), false)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Post not found")
 		return
 	}
 
-	postID := c.Param("id")
-	var post models.Post
-	if err := database.DB.First(&post, postID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+	// Moderators and admins may remove any post
+	role := middleware.GetUserRole(c)
+	if post.UserID != userID && role != models.RoleModerator && role != models.RoleAdmin {
+		utils.ErrorResponse(c, http.StatusForbidden, "Not authorized to delete this post")
 		return
 	}
 
-	if post.UserID != userID.(uint) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to delete this post"})
+	// Comments and likes are deleted together with the post
+	if err := h.forum.DeletePost(ctx, post.ID); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete post")
 		return
 	}
 
-	// Delete comments and likes first
-	database.DB.Where("post_id = ?", post.ID).Delete(&models.Comment{})
-	database.DB.Where("post_id = ?", post.ID).Delete(&models.Like{})
-	database.DB.Delete(&post)
+	utils.SuccessResponse(c, http.StatusOK, "Post deleted", nil)
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Post deleted"})
+func toPostResponse(post models.Post, isLiked bool) models.PostResponse {
+	return models.PostResponse{
+		ID:            post.ID,
+		UserID:        post.UserID,
+		UserName:      post.User.Name,
+		Title:         post.Title,
+		Content:       post.Content,
+		LikesCount:    post.LikesCount,
+		CommentsCount: post.CommentsCount,
+		IsLiked:       isLiked,
+		CreatedAt:     post.CreatedAt,
+	}
+}
+
+func toCommentResponse(comment models.Comment) models.CommentResponse {
+	return models.CommentResponse{
+		ID:        comment.ID,
+		PostID:    comment.PostID,
+		UserID:    comment.UserID,
+		UserName:  comment.User.Name,
+		Content:   comment.Content,
+		CreatedAt: comment.CreatedAt,
+	}
 }
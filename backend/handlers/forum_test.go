@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"health-tracker/models"
+)
+
+func TestDeletePostAllowsOwnerAndModerators(t *testing.T) {
+	forum := &fakeForum{posts: []models.Post{{ID: 1, UserID: 2, Title: "Tips tidur"}}}
+	h := NewForumHandler(forum)
+
+	r := newTestRouter(3, models.RoleUser)
+	r.DELETE("/forum/posts/:id", h.DeletePost)
+	if w := serve(r, http.MethodDelete, "/forum/posts/1", nil); w.Code != http.StatusForbidden {
+		t.Errorf("delete by another user = %d, want 403", w.Code)
+	}
+	if w := serve(r, http.MethodDelete, "/forum/posts/9", nil); w.Code != http.StatusNotFound {
+		t.Errorf("delete of missing post = %d, want 404", w.Code)
+	}
+	if len(forum.deleted) != 0 {
+		t.Fatalf("posts deleted: %v", forum.deleted)
+	}
+
+	r = newTestRouter(3, models.RoleModerator)
+	r.DELETE("/forum/posts/:id", h.DeletePost)
+	if w := serve(r, http.MethodDelete, "/forum/posts/1", nil); w.Code != http.StatusOK {
+		t.Errorf("delete by moderator = %d, want 200", w.Code)
+	}
+	if len(forum.deleted) != 1 || forum.deleted[0] != 1 {
+		t.Errorf("deleted = %v, want [1]", forum.deleted)
+	}
+}
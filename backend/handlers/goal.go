@@ -1,25 +1,47 @@
 package handlers
 
 import (
-	"health-tracker/database"
+	"context"
+	"health-tracker/insights"
+	"health-tracker/metrics"
 	"health-tracker/models"
+	"health-tracker/repository"
+	"health-tracker/utils"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// GoalHandler serves personal goals
+type GoalHandler struct {
+	goals    repository.GoalRepository
+	analyzer *insights.Analyzer
+}
+
+// NewGoalHandler creates a GoalHandler
+func NewGoalHandler(goals repository.GoalRepository, analyzer *insights.Analyzer) *GoalHandler {
+	return &GoalHandler{goals: goals, analyzer: analyzer}
+}
+
+// refreshWeightInsights updates the weight projection when a weight goal changes
+func (h *GoalHandler) refreshWeightInsights(ctx context.Context, goal models.Goal) {
+	if goal.Type == models.GoalTypeWeight {
+		refreshInsights(ctx, h.analyzer, goal.UserID)
+	}
+}
+
 // calculateDaysLeft calculates days remaining until deadline
 func calculateDaysLeft(deadline string) int {
 	if deadline == "" {
 		return -1
 	}
-	
+
 	deadlineTime, err := time.Parse("2006-01-02", deadline)
 	if err != nil {
 		return -1
 	}
-	
+
 	days := int(deadlineTime.Sub(time.Now()).Hours() / 24)
 	if days < 0 {
 		return 0
@@ -28,63 +50,38 @@ func calculateDaysLeft(deadline string) int {
 }
 
 // GetGoals returns all goals for the user
-func GetGoals(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
+func (h *GoalHandler) GetGoals(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
 
-	var goals []models.Goal
-	if err := database.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&goals).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goals"})
+	goals, err := h.goals.ListByUser(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch goals")
 		return
 	}
 
 	var response []models.GoalResponse
 	for _, goal := range goals {
-		response = append(response, models.GoalResponse{
-			ID:          goal.ID,
-			Title:       goal.Title,
-			Description: goal.Description,
-			Type:        goal.Type,
-			Target:      goal.Target,
-			Current:     goal.Current,
-			Unit:        goal.Unit,
-			Deadline:    goal.Deadline,
-			IsCompleted: goal.IsCompleted,
-			Progress:    goal.GetProgress(),
-			DaysLeft:    calculateDaysLeft(goal.Deadline),
-		})
-	}
-
-	c.JSON(http.StatusOK, response)
+		response = append(response, toGoalResponse(goal))
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Goals retrieved", response)
 }
 
 // CreateGoal creates a new goal
-func CreateGoal(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
+func (h *GoalHandler) CreateGoal(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
 
-	var input struct {
-		Title       string  `json:"title" binding:"required"`
-		Description string  `json:"description"`
-		Type        string  `json:"type" binding:"required"`
-		Target      float64 `json:"target" binding:"required"`
-		Unit        string  `json:"unit"`
-		Deadline    string  `json:"deadline"`
-	}
+	var input models.CreateGoalRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
 	goal := models.Goal{
-		UserID:      userID.(uint),
+		UserID:      userID,
 		Title:       input.Title,
 		Description: input.Description,
 		Type:        input.Type,
@@ -97,50 +94,34 @@ func CreateGoal(c *gin.Context) {
 		UpdatedAt:   time.Now(),
 	}
 
-	if err := database.DB.Create(&goal).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create goal"})
+	if err := h.goals.Create(ctx, &goal); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create goal")
 		return
 	}
+	h.refreshWeightInsights(ctx, goal)
 
-	c.JSON(http.StatusCreated, models.GoalResponse{
-		ID:          goal.ID,
-		Title:       goal.Title,
-		Description: goal.Description,
-		Type:        goal.Type,
-		Target:      goal.Target,
-		Current:     goal.Current,
-		Unit:        goal.Unit,
-		Deadline:    goal.Deadline,
-		IsCompleted: goal.IsCompleted,
-		Progress:    goal.GetProgress(),
-		DaysLeft:    calculateDaysLeft(goal.Deadline),
-	})
+	utils.SuccessResponse(c, http.StatusCreated, "Goal created", toGoalResponse(goal))
 }
 
 // UpdateGoalProgress updates the current progress of a goal
-func UpdateGoalProgress(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
+func (h *GoalHandler) UpdateGoalProgress(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
 
-	goalID := c.Param("id")
-	var goal models.Goal
-	if err := database.DB.Where("id = ? AND user_id = ?", goalID, userID).First(&goal).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+	goal, err := h.goals.Find(ctx, paramID(c, "id"), userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Goal not found")
 		return
 	}
 
-	var input struct {
-		Current float64 `json:"current" binding:"required"`
-	}
+	var input models.UpdateGoalProgressRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
+	wasCompleted := goal.IsCompleted
 	goal.Current = input.Current
 	goal.UpdatedAt = time.Now()
 
@@ -149,63 +130,83 @@ func UpdateGoalProgress(c *gin.Context) {
 		goal.IsCompleted = true
 	}
 
-	database.DB.Save(&goal)
+	if err := h.goals.Save(ctx, goal); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update goal")
+		return
+	}
+	if goal.IsCompleted && !wasCompleted {
+		metrics.GoalsCompleted.Inc()
+	}
+	h.refreshWeightInsights(ctx, *goal)
 
-	c.JSON(http.StatusOK, models.GoalResponse{
-		ID:          goal.ID,
-		Title:       goal.Title,
-		Description: goal.Description,
-		Type:        goal.Type,
-		Target:      goal.Target,
-		Current:     goal.Current,
-		Unit:        goal.Unit,
-		Deadline:    goal.Deadline,
-		IsCompleted: goal.IsCompleted,
-		Progress:    goal.GetProgress(),
-		DaysLeft:    calculateDaysLeft(goal.Deadline),
-	})
+	utils.SuccessResponse(c, http.StatusOK, "Goal progress updated", toGoalResponse(*goal))
 }
 
 // DeleteGoal deletes a goal
-func DeleteGoal(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+func (h *GoalHandler) DeleteGoal(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	goal, err := h.goals.Find(ctx, paramID(c, "id"), userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Goal not found")
 		return
 	}
 
-	goalID := c.Param("id")
-	var goal models.Goal
-	if err := database.DB.Where("id = ? AND user_id = ?", goalID, userID).First(&goal).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+	if err := h.goals.Delete(ctx, goal); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete goal")
 		return
 	}
+	h.refreshWeightInsights(ctx, *goal)
 
-	database.DB.Delete(&goal)
-
-	c.JSON(http.StatusOK, gin.H{"message": "Goal deleted"})
+	utils.SuccessResponse(c, http.StatusOK, "Goal deleted", nil)
 }
 
 // ToggleGoalComplete toggles the completion status of a goal
-func ToggleGoalComplete(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
+func (h *GoalHandler) ToggleGoalComplete(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
 
-	goalID := c.Param("id")
-	var goal models.Goal
-	if err := database.DB.Where("id = ? AND user_id = ?", goalID, userID).First(&goal).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+	goal, err := h.goals.Find(ctx, paramID(c, "id"), userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Goal not found")
 		return
 	}
 
 	goal.IsCompleted = !goal.IsCompleted
 	goal.UpdatedAt = time.Now()
-	database.DB.Save(&goal)
+	if err := h.goals.Save(ctx, goal); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update goal")
+		return
+	}
+	if goal.IsCompleted {
+		metrics.GoalsCompleted.Inc()
+	}
+	h.refreshWeightInsights(ctx, *goal)
+
+	utils.SuccessResponse(c, http.StatusOK, "Goal updated", toGoalResponse(*goal))
+}
+
+// GetGoalStats returns summary of goals
+func (h *GoalHandler) GetGoalStats(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	total, completed, err := h.goals.Counts(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch goal stats")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Goal stats retrieved", gin.H{
+		"total":       total,
+		"completed":   completed,
+		"in_progress": total - completed,
+	})
+}
 
-	c.JSON(http.StatusOK, models.GoalResponse{
+func toGoalResponse(goal models.Goal) models.GoalResponse {
+	return models.GoalResponse{
 		ID:          goal.ID,
 		Title:       goal.Title,
 		Description: goal.Description,
@@ -217,26 +218,5 @@ func ToggleGoalComplete(c *gin.Context) {
 		IsCompleted: goal.IsCompleted,
 		Progress:    goal.GetProgress(),
 		DaysLeft:    calculateDaysLeft(goal.Deadline),
-	})
-}
-
-// GetGoalStats returns summary of goals
-func GetGoalStats(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
 	}
-
-	var total, completed, inProgress int64
-	
-	database.DB.Model(&models.Goal{}).Where("user_id = ?", userID).Count(&total)
-	database.DB.Model(&models.Goal{}).Where("user_id = ? AND is_completed = ?", userID, true).Count(&completed)
-	inProgress = total - completed
-
-	c.JSON(http.StatusOK, gin.H{
-		"total":       total,
-		"completed":   completed,
-		"in_progress": inProgress,
-	})
 }
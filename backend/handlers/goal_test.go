@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"health-tracker/models"
+)
+
+func TestCreateGoalRefreshesInsightsForWeightGoals(t *testing.T) {
+	stores := newTestStores()
+	h := NewGoalHandler(stores.goals, stores.analyzer)
+	r := newTestRouter(1, "")
+	r.POST("/goals", h.CreateGoal)
+
+	w := serve(r, http.MethodPost, "/goals", models.CreateGoalRequest{Title: "Jalan kaki", Type: "steps", Target: 10000})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create = %d, want 201: %s", w.Code, w.Body)
+	}
+	if stores.insights.syncs != 0 {
+		t.Errorf("insights refreshed for a %q goal", "steps")
+	}
+
+	w = serve(r, http.MethodPost, "/goals", models.CreateGoalRequest{Title: "Turun berat", Type: models.GoalTypeWeight, Target: 70, Unit: "kg"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create = %d, want 201: %s", w.Code, w.Body)
+	}
+	if stores.insights.syncs != 1 {
+		t.Errorf("insights synced %d times after a weight goal, want 1", stores.insights.syncs)
+	}
+
+	var goal models.GoalResponse
+	decodeResponse(t, w, &goal)
+	if goal.Type != models.GoalTypeWeight || goal.Target != 70 {
+		t.Errorf("goal = %+v", goal)
+	}
+}
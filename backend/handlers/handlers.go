@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"strconv"
+
+	"health-tracker/insights"
+	"health-tracker/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handlers groups every HTTP handler together with its dependencies
+type Handlers struct {
+	Auth           *AuthHandler
+	Account        *AccountHandler
+	Admin          *AdminHandler
+	Health         *HealthHandler
+	Measurement    *MeasurementHandler
+	Symptom        *SymptomHandler
+	Family         *FamilyHandler
+	Recommendation *RecommendationHandler
+	Forum          *ForumHandler
+	Water          *WaterHandler
+	Goal           *GoalHandler
+	Reminder       *ReminderHandler
+	Article        *ArticleHandler
+	Probe          *ProbeHandler
+}
+
+// New builds all handlers from the given repositories
+func New(repos *repository.Repositories) *Handlers {
+	analyzer := insights.NewAnalyzer(repos.Health, repos.Measurements, repos.Goals, repos.Insights)
+	return &Handlers{
+		Auth:           NewAuthHandler(repos.Users, repos.Sessions, repos.PasswordResets, repos.RecoveryCodes, repos.LoginAttempts, repos),
+		Account:        NewAccountHandler(repos.Users, repos.RecoveryCodes, repos.Exports, analyzer),
+		Admin:          NewAdminHandler(repos.Users, repos.Sessions, repos),
+		Health:         NewHealthHandler(repos.Health, repos.Symptoms, repos.Measurements, repos.Water, repos.Insights, analyzer, repos.Users),
+		Measurement:    NewMeasurementHandler(repos.Measurements, analyzer),
+		Symptom:        NewSymptomHandler(repos.Symptoms),
+		Family:         NewFamilyHandler(repos.Family, repos.Users, repos.Health, repos.Symptoms),
+		Recommendation: NewRecommendationHandler(repos.Users, repos.Health, repos.Symptoms),
+		Forum:          NewForumHandler(repos.Forum),
+		Water:          NewWaterHandler(repos.Water),
+		Goal:           NewGoalHandler(repos.Goals, analyzer),
+		Reminder:       NewReminderHandler(repos.Reminders),
+		Article:        NewArticleHandler(repos.Articles),
+		Probe:          NewProbeHandler(repos.System),
+	}
+}
+
+// paramID parses a numeric path parameter; invalid values yield 0, which matches no row
+func paramID(c *gin.Context, name string) uint {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint(id)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"health-tracker/config"
+	"health-tracker/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	config.LoadConfig()
+	utils.ConfigureValidator()
+	os.Exit(m.Run())
+}
+
+// newTestRouter returns a router whose requests are authenticated as userID with role,
+// like the auth middleware would leave them; userID 0 leaves requests anonymous
+func newTestRouter(userID uint, role string) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if userID != 0 {
+			c.Set("userID", userID)
+			c.Set("userRole", role)
+		}
+		c.Next()
+	})
+	return r
+}
+
+// serve sends a request to r; body is sent as is when it is an io.Reader and as JSON otherwise
+func serve(r *gin.Engine, method, path string, body interface{}, headers ...string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+	default:
+		payload, _ := json.Marshal(b)
+		reader = bytes.NewReader(payload)
+		headers = append(headers, "Content-Type", "application/json")
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// testResponse is the response envelope with its data left undecoded
+type testResponse struct {
+	utils.APIResponse
+	Data json.RawMessage `json:"data"`
+}
+
+// decodeResponse decodes the envelope and, if data is not nil, its data field into data
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder, data interface{}) testResponse {
+	t.Helper()
+	var resp testResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+	if data != nil {
+		if err := json.Unmarshal(resp.Data, data); err != nil {
+			t.Fatalf("decode data %s: %v", resp.Data, err)
+		}
+	}
+	return resp
+}
@@ -1,77 +1,212 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
 	"net/http"
 	"time"
 
-	"health-tracker/database"
+	"health-tracker/insights"
 	"health-tracker/models"
+	"health-tracker/repository"
 	"health-tracker/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+// HealthHandler serves health records, the dashboard and graphs
+type HealthHandler struct {
+	health       repository.HealthRepository
+	symptoms     repository.SymptomRepository
+	measurements repository.MeasurementRepository
+	water        repository.WaterRepository
+	insights     repository.InsightRepository
+	analyzer     *insights.Analyzer
+	users        repository.UserRepository
+}
+
+// NewHealthHandler creates a HealthHandler
+func NewHealthHandler(health repository.HealthRepository, symptoms repository.SymptomRepository, measurements repository.MeasurementRepository, water repository.WaterRepository,
+	insightRepo repository.InsightRepository, analyzer *insights.Analyzer, users repository.UserRepository) *HealthHandler {
+	return &HealthHandler{health: health, symptoms: symptoms, measurements: measurements, water: water, insights: insightRepo, analyzer: analyzer, users: users}
+}
+
+// dashboardInsights is how many unread insights the dashboard shows
+const dashboardInsights = 5
+
+// vitalsMaxAge is how old a measurement may be and still count towards the dashboard
+const vitalsMaxAge = 30 * 24 * time.Hour
+
 // CreateHealthData submits new health data
-func CreateHealthData(c *gin.Context) {
+func (h *HealthHandler) CreateHealthData(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
 	var req models.HealthDataRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+	if !bindHealthDataRequest(c, &req) {
 		return
 	}
 
-	// Calculate BMI
-	bmi := models.CalculateBMI(req.WeightKg, req.HeightCm)
-
 	healthData := models.HealthData{
-		UserID:         userID,
-		WeightKg:       req.WeightKg,
-		HeightCm:       req.HeightCm,
-		BMI:            bmi,
-		ActivityLevel:  req.ActivityLevel,
-		EmotionalState: req.EmotionalState,
-		DailySchedule:  req.DailySchedule,
-		Notes:          req.Notes,
-		RecordDate:     time.Now(),
-	}
-
-	if result := database.DB.Create(&healthData); result.Error != nil {
+		UserID:     userID,
+		RecordDate: time.Now().UTC(),
+	}
+	applyHealthDataRequest(&healthData, req)
+
+	if err := h.health.Create(ctx, &healthData); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to save health data")
 		return
 	}
 
-	// Also update user's base info
-	database.DB.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
-		"weight_kg":      req.WeightKg,
-		"height_cm":      req.HeightCm,
-		"activity_level": req.ActivityLevel,
-	})
+	// A backdated record may not be the latest, so copy the base info from whichever is
+	h.syncUserMetrics(ctx, userID)
+	refreshInsights(ctx, h.analyzer, userID)
 
 	utils.SuccessResponse(c, http.StatusCreated, "Health data saved", gin.H{
 		"health_data":  healthData,
-		"bmi_category": models.GetBMICategory(bmi),
+		"bmi_category": models.GetBMICategory(healthData.BMI),
 	})
 }
 
-// GetHealthData returns all health records for current user
-func GetHealthData(c *gin.Context) {
+// UpdateHealthData replaces one of the user's records, e.g. to fix a mistyped weight
+func (h *HealthHandler) UpdateHealthData(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
-	var healthData []models.HealthData
-	database.DB.Where("user_id = ?", userID).Order("record_date desc").Find(&healthData)
+	healthData, err := h.health.Find(ctx, paramID(c, "id"), userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Health data not found")
+		return
+	}
+
+	var req models.HealthDataRequest
+	if !bindHealthDataRequest(c, &req) {
+		return
+	}
+	applyHealthDataRequest(healthData, req)
+
+	if err := h.health.Save(ctx, healthData); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update health data")
+		return
+	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Health data retrieved", healthData)
+	h.syncUserMetrics(ctx, userID)
+	refreshInsights(ctx, h.analyzer, userID)
+
+	utils.SuccessResponse(c, http.StatusOK, "Health data updated", gin.H{
+		"health_data":  healthData,
+		"bmi_category": models.GetBMICategory(healthData.BMI),
+	})
 }
 
-// GetLatestHealthData returns the latest health record
-func GetLatestHealthData(c *gin.Context) {
+// DeleteHealthData deletes one of the user's records
+func (h *HealthHandler) DeleteHealthData(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	healthData, err := h.health.Find(ctx, paramID(c, "id"), userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Health data not found")
+		return
+	}
+
+	if err := h.health.Delete(ctx, healthData); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete health data")
+		return
+	}
+
+	h.syncUserMetrics(ctx, userID)
+	refreshInsights(ctx, h.analyzer, userID)
+
+	utils.SuccessResponse(c, http.StatusOK, "Health data deleted", nil)
+}
+
+// bindHealthDataRequest binds and validates a create/update body, responding on failure
+func bindHealthDataRequest(c *gin.Context, req *models.HealthDataRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return false
+	}
+	if req.RecordDate != nil && req.RecordDate.After(time.Now().Add(models.MaxClockSkew)) {
+		utils.FieldErrorResponse(c, utils.FieldError{Field: "record_date", Rule: "past", Message: "cannot be in the future"})
+		return false
+	}
+	return true
+}
+
+// applyHealthDataRequest copies the request onto a record and recomputes its BMI
+func applyHealthDataRequest(record *models.HealthData, req models.HealthDataRequest) {
+	record.WeightKg = req.WeightKg
+	record.HeightCm = req.HeightCm
+	record.BMI = models.CalculateBMI(req.WeightKg, req.HeightCm)
+	record.ActivityLevel = req.ActivityLevel
+	record.EmotionalState = req.EmotionalState
+	record.DailySchedule = req.DailySchedule
+	record.Notes = req.Notes
+	if req.RecordDate != nil {
+		// Stored in UTC, see the repository package doc
+		record.RecordDate = req.RecordDate.UTC()
+	}
+}
+
+// syncUserMetrics copies weight, height and activity level from the user's latest record
+// onto the profile. Without records the profile keeps its last values: they can also be set
+// with PUT /auth/profile, and recommendations need a height and weight to work from, so
+// deleting the history does not reset what the user told us about themselves.
+func (h *HealthHandler) syncUserMetrics(ctx context.Context, userID uint) {
+	latest, err := h.health.Latest(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			slog.ErrorContext(ctx, "failed to load latest health data", "user_id", userID, "error", err)
+		}
+		return
+	}
+
+	if err := h.users.Update(ctx, userID, map[string]interface{}{
+		"weight_kg":      latest.WeightKg,
+		"height_cm":      latest.HeightCm,
+		"activity_level": latest.ActivityLevel,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to sync profile with latest health data", "user_id", userID, "error", err)
+	}
+}
+
+// GetHealthData returns a page of the user's health records. It accepts ?from= and ?to=
+// (dates in ?tz=, or timestamps), ?emotional_state=, ?sort= and cursor pagination.
+func (h *HealthHandler) GetHealthData(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
-	var healthData models.HealthData
-	result := database.DB.Where("user_id = ?", userID).Order("record_date desc").First(&healthData)
+	query := newQueryParser(c)
+	filter := repository.HealthFilter{EmotionalState: c.Query("emotional_state")}
+	filter.From, filter.To = query.dateRange(query.location())
+	page := query.page(repository.HealthSortFields, "-record_date")
+	if !query.ok() {
+		return
+	}
+
+	healthData, next, err := h.health.List(ctx, userID, filter, page)
+	if cursorError(c, err) {
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch health data")
+		return
+	}
+
+	utils.PaginatedResponse(c, "Health data retrieved", healthData, utils.NewCursorPagination(page.Limit, encodeCursor(next)))
+}
 
-	if result.Error != nil {
+// GetLatestHealthData returns the latest health record
+func (h *HealthHandler) GetLatestHealthData(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	healthData, err := h.health.Latest(ctx, userID)
+	if err != nil {
 		utils.SuccessResponse(c, http.StatusOK, "No health data found", nil)
 		return
 	}
@@ -83,31 +218,34 @@ func GetLatestHealthData(c *gin.Context) {
 }
 
 // GetDashboard returns dashboard summary data
-func GetDashboard(c *gin.Context) {
+func (h *HealthHandler) GetDashboard(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
 	// Get latest health data
-	var latestHealth models.HealthData
-	database.DB.Where("user_id = ?", userID).Order("record_date desc").First(&latestHealth)
+	latestHealth := latestHealthData(ctx, h.health, userID)
 
 	// Get total records count
-	var totalRecords int64
-	database.DB.Model(&models.HealthData{}).Where("user_id = ?", userID).Count(&totalRecords)
+	totalRecords, _ := h.health.CountByUser(ctx, userID)
 
 	// Get recent symptoms (last 7 days)
-	var recentSymptoms []models.Symptom
 	weekAgo := time.Now().AddDate(0, 0, -7)
-	database.DB.Where("user_id = ? AND logged_at > ?", userID, weekAgo).Order("logged_at desc").Find(&recentSymptoms)
+	recentSymptoms, _ := h.symptoms.ListSince(ctx, userID, weekAgo)
 
 	// Get weekly progress (last 7 records)
-	var weeklyProgress []models.HealthData
-	database.DB.Where("user_id = ?", userID).Order("record_date desc").Limit(7).Find(&weeklyProgress)
+	weeklyProgress, _ := h.health.Recent(ctx, userID, 7)
+
+	// Get the latest reading of each vital sign from the last 30 days
+	latestVitals, _ := h.measurements.LatestPerType(ctx, userID, time.Now().Add(-vitalsMaxAge))
+
+	// Unread insights, newest first
+	unreadInsights, _ := h.insights.List(ctx, userID, true, dashboardInsights)
 
 	// Calculate health score (simplified)
-	healthScore := calculateHealthScore(latestHealth, recentSymptoms)
+	healthScore := calculateHealthScore(latestHealth, recentSymptoms, latestVitals)
 
 	// Get recommendations
-	recommendations := getQuickRecommendations(latestHealth, recentSymptoms)
+	recommendations := getQuickRecommendations(latestHealth, recentSymptoms, latestVitals)
 
 	dashboard := models.DashboardData{
 		LatestHealth:    &latestHealth,
@@ -115,51 +253,263 @@ func GetDashboard(c *gin.Context) {
 		HealthScore:     healthScore,
 		TotalRecords:    totalRecords,
 		RecentSymptoms:  recentSymptoms,
+		LatestVitals:    latestVitals,
 		WeeklyProgress:  weeklyProgress,
+		Insights:        unreadInsights,
 		Recommendations: recommendations,
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Dashboard data retrieved", dashboard)
 }
 
-// GetHealthGraph returns graph data for specified period
-func GetHealthGraph(c *gin.Context) {
+// GetInsights returns the metric trends with the stored insights, optionally ?unread=true
+// ones only. It only reads: insights are refreshed by the writes that change the readings.
+func (h *HealthHandler) GetInsights(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
-	period := c.Param("period") // week, month, year
-
-	var days int
-	switch period {
-	case "week":
-		days = 7
-	case "month":
-		days = 30
-	case "year":
-		days = 365
+
+	query := newQueryParser(c)
+	limit := query.intRange("limit", 1, 100)
+	if limit == 0 {
+		limit = 20
+	}
+	unreadOnly := c.Query("unread") == "true"
+	if !query.ok() {
+		return
+	}
+
+	trends, err := h.analyzer.Trends(ctx, userID, time.Now())
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to analyse health data")
+		return
+	}
+
+	stored, err := h.insights.List(ctx, userID, unreadOnly, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch insights")
+		return
+	}
+	unread, err := h.insights.CountUnread(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch insights")
+		return
+	}
+
+	if trends == nil {
+		trends = []models.MetricTrend{}
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Insights retrieved", models.InsightsReport{
+		Trends:   trends,
+		Insights: stored,
+		Unread:   unread,
+	})
+}
+
+// MarkInsightRead marks an insight read so it leaves the dashboard
+func (h *HealthHandler) MarkInsightRead(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+	insightID := paramID(c, "id")
+	if insightID == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid insight ID")
+		return
+	}
+
+	updated, err := h.insights.MarkRead(ctx, insightID, userID, time.Now())
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update insight")
+		return
+	}
+	if updated == 0 {
+		utils.ErrorResponse(c, http.StatusNotFound, "Insight not found or already read")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Insight marked as read", nil)
+}
+
+// refreshInsights reanalyses the user's data after a write to health records, measurements
+// or weight goals. Failures are logged rather than failing the request; the next refresh
+// catches up.
+func refreshInsights(ctx context.Context, analyzer *insights.Analyzer, userID uint) {
+	if _, err := analyzer.Refresh(ctx, userID, time.Now()); err != nil {
+		slog.ErrorContext(ctx, "failed to refresh insights", "user_id", userID, "error", err)
+	}
+}
+
+// maxGraphBuckets bounds a graph to roughly a year of days or ten years of weeks
+const maxGraphBuckets = 550
+
+// graphPeriods maps a graph period to its length in days and default bucket size
+var graphPeriods = map[string]struct {
+	days   int
+	bucket string
+}{
+	"week":  {7, models.BucketDay},
+	"month": {30, models.BucketDay},
+	"year":  {365, models.BucketWeek},
+}
+
+// GetHealthGraph returns health data aggregated into ?bucket=day|week|month buckets,
+// with emotional states, symptoms and water intake per bucket. The period (week, month,
+// year or custom) sets the default range, which ?from= and ?to= override.
+func (h *HealthHandler) GetHealthGraph(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+	period := c.Param("period")
+
+	query := newQueryParser(c)
+	loc := query.location()
+	from, to := query.dateRange(loc)
+
+	preset, ok := graphPeriods[period]
+	switch {
+	case ok:
+		if to.IsZero() {
+			to = models.BucketStart(time.Now().In(loc), models.BucketDay).AddDate(0, 0, 1).UTC()
+		}
+		if from.IsZero() {
+			from = to.In(loc).AddDate(0, 0, -preset.days).UTC()
+		}
+	case period == "custom":
+		if from.IsZero() {
+			query.fail("from", "required", "is required for a custom range")
+		}
+		if to.IsZero() {
+			query.fail("to", "required", "is required for a custom range")
+		}
+		preset.bucket = defaultGraphBucket(to.Sub(from))
 	default:
-		days = 7
+		query.fail("period", "oneof", "must be one of week, month, year, custom")
 	}
 
-	startDate := time.Now().AddDate(0, 0, -days)
+	bucket := c.DefaultQuery("bucket", preset.bucket)
+	if bucket != models.BucketDay && bucket != models.BucketWeek && bucket != models.BucketMonth {
+		query.fail("bucket", "oneof", "must be one of day, week, month")
+	}
+	fill := c.DefaultQuery("fill", models.FillNull)
+	if fill != models.FillNull && fill != models.FillNone && fill != models.FillPrevious {
+		query.fail("fill", "oneof", "must be one of null, none, previous")
+	}
+	if !query.ok() {
+		return
+	}
 
-	var healthData []models.HealthData
-	database.DB.Where("user_id = ? AND record_date >= ?", userID, startDate).
-		Order("record_date asc").Find(&healthData)
+	starts := models.BucketStarts(from, to, bucket, loc)
+	if len(starts) > maxGraphBuckets {
+		utils.FieldErrorResponse(c, utils.FieldError{Field: "bucket", Rule: "max",
+			Message: fmt.Sprintf("range spans %d buckets, more than %d; use a larger bucket or a shorter range", len(starts), maxGraphBuckets)})
+		return
+	}
 
-	// Prepare graph data
-	graphData := make([]map[string]interface{}, len(healthData))
-	for i, hd := range healthData {
-		graphData[i] = map[string]interface{}{
-			"date":            hd.RecordDate.Format("2006-01-02"),
-			"weight":          hd.WeightKg,
-			"bmi":             hd.BMI,
-			"emotional_state": hd.EmotionalState,
+	q := repository.BucketQuery{Bucket: bucket, From: from, To: to, Location: loc}
+	healthRows, err := h.health.Buckets(ctx, userID, q)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch graph data")
+		return
+	}
+	emotionalStates, err := h.health.EmotionalStates(ctx, userID, q)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch graph data")
+		return
+	}
+	symptomRows, err := h.symptoms.Buckets(ctx, userID, q)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch graph data")
+		return
+	}
+	waterRows, err := h.water.Buckets(ctx, userID, q)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch graph data")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Graph data retrieved", models.HealthGraph{
+		Period:   period,
+		Bucket:   bucket,
+		From:     from.In(loc),
+		To:       to.In(loc),
+		Timezone: loc.String(),
+		Fill:     fill,
+		Buckets:  buildGraphBuckets(starts, fill, healthRows, emotionalStates, symptomRows, waterRows),
+	})
+}
+
+// defaultGraphBucket picks a bucket size that keeps a custom range to a readable number of points
+func defaultGraphBucket(span time.Duration) string {
+	switch {
+	case span <= 62*24*time.Hour:
+		return models.BucketDay
+	case span <= 2*365*24*time.Hour:
+		return models.BucketWeek
+	}
+	return models.BucketMonth
+}
+
+// buildGraphBuckets lays the aggregates out over every bucket of the range and applies the fill mode
+func buildGraphBuckets(starts []string, fill string, healthRows []models.HealthBucketRow, emotionalStates []models.BucketCount,
+	symptomRows []models.SymptomBucketRow, waterRows []models.WaterBucketRow) []models.GraphBucket {
+	byStart := make(map[string]*models.GraphBucket, len(starts))
+	buckets := make([]models.GraphBucket, len(starts))
+	for i, start := range starts {
+		buckets[i] = models.GraphBucket{Start: start, EmotionalStates: map[string]int{}}
+		byStart[start] = &buckets[i]
+	}
+
+	for _, row := range healthRows {
+		if b, ok := byStart[row.Bucket]; ok {
+			b.Count = row.Count
+			b.Weight = &models.SeriesStats{Min: row.MinWeight, Max: row.MaxWeight, Avg: round2(row.AvgWeight), Last: row.LastWeight}
+			b.BMI = &models.SeriesStats{Min: round2(row.MinBMI), Max: round2(row.MaxBMI), Avg: round2(row.AvgBMI), Last: round2(row.LastBMI)}
+		}
+	}
+	for _, row := range emotionalStates {
+		if b, ok := byStart[row.Bucket]; ok {
+			b.EmotionalStates[row.Label] = row.Count
+		}
+	}
+	for _, row := range symptomRows {
+		if b, ok := byStart[row.Bucket]; ok {
+			b.Symptoms = models.SymptomOverlay{Count: row.Count, AvgSeverity: round2(row.AvgSeverity), MaxSeverity: row.MaxSeverity}
+		}
+	}
+	for _, row := range waterRows {
+		if b, ok := byStart[row.Bucket]; ok {
+			b.Water = models.WaterOverlay{Glasses: row.Glasses, Days: row.Days, DaysGoalMet: row.DaysGoalMet}
+		}
+	}
+
+	filled := make([]models.GraphBucket, 0, len(buckets))
+	var lastWeight, lastBMI *models.SeriesStats
+	for _, b := range buckets {
+		if b.Count == 0 {
+			if fill == models.FillNone && b.Symptoms.Count == 0 && b.Water.Days == 0 {
+				continue
+			}
+			if fill == models.FillPrevious && lastWeight != nil {
+				b.Weight, b.BMI, b.Filled = lastWeight, lastBMI, true
+			}
 		}
+		lastWeight, lastBMI = b.Weight, b.BMI
+		filled = append(filled, b)
 	}
+	return filled
+}
 
-	utils.SuccessResponse(c, http.StatusOK, "Graph data retrieved", graphData)
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
 }
 
-func calculateHealthScore(health models.HealthData, symptoms []models.Symptom) int {
+// latestHealthData returns the user's most recent record, or an empty one if there is none yet
+func latestHealthData(ctx context.Context, health repository.HealthRepository, userID uint) models.HealthData {
+	record, err := health.Latest(ctx, userID)
+	if err != nil {
+		return models.HealthData{}
+	}
+	return *record
+}
+
+func calculateHealthScore(health models.HealthData, symptoms []models.Symptom, vitals []models.Measurement) int {
 	score := 100
 
 	// Deduct points based on BMI
@@ -184,6 +534,18 @@ func calculateHealthScore(health models.HealthData, symptoms []models.Symptom) i
 		score -= 15
 	}
 
+	// Deduct for vital signs outside the normal range
+	for _, vital := range vitals {
+		switch vital.RiskLevel {
+		case models.RiskWatch:
+			score -= 5
+		case models.RiskElevated:
+			score -= 10
+		case models.RiskHigh:
+			score -= 20
+		}
+	}
+
 	if score < 0 {
 		score = 0
 	}
@@ -191,7 +553,7 @@ func calculateHealthScore(health models.HealthData, symptoms []models.Symptom) i
 	return score
 }
 
-func getQuickRecommendations(health models.HealthData, symptoms []models.Symptom) []models.RecommendationItem {
+func getQuickRecommendations(health models.HealthData, symptoms []models.Symptom, vitals []models.Measurement) []models.RecommendationItem {
 	var recommendations []models.RecommendationItem
 
 	// BMI-based recommendation
@@ -215,6 +577,13 @@ func getQuickRecommendations(health models.HealthData, symptoms []models.Symptom
 		})
 	}
 
+	// Vital sign readings that need attention
+	for _, vital := range vitals {
+		if vital.RiskLevel >= models.RiskElevated {
+			recommendations = append(recommendations, vitalRecommendation(vital))
+		}
+	}
+
 	// Symptom-based
 	if len(symptoms) > 3 {
 		recommendations = append(recommendations, models.RecommendationItem{
@@ -227,3 +596,41 @@ func getQuickRecommendations(health models.HealthData, symptoms []models.Symptom
 
 	return recommendations
 }
+
+// vitalRecommendation advises on a reading with an elevated or high risk level
+func vitalRecommendation(vital models.Measurement) models.RecommendationItem {
+	spec, _ := models.FindMeasurementSpec(vital.Type)
+	item := models.RecommendationItem{
+		Type:     "health",
+		Title:    "Perhatikan " + spec.Label + " Anda",
+		Priority: "medium",
+	}
+	if vital.RiskLevel >= models.RiskHigh {
+		item.Priority = "high"
+	}
+
+	switch vital.Type {
+	case models.MeasurementBloodPressure:
+		item.Description = "Tekanan darah Anda termasuk " + vital.Category + ". Kurangi garam, tetap aktif, dan ukur ulang secara rutin. Konsultasikan dengan dokter jika tetap tinggi."
+		if vital.Category == "Hypertensive Crisis" {
+			item.Description = "Tekanan darah Anda sangat tinggi. Ukur ulang setelah 5 menit istirahat dan segera cari pertolongan medis, terutama jika disertai nyeri dada, sesak napas, atau sakit kepala hebat."
+		}
+	case models.MeasurementHeartRate:
+		item.Description = "Detak jantung istirahat Anda tinggi. Istirahat yang cukup, kurangi kafein, dan konsultasikan dengan dokter jika berlanjut."
+	case models.MeasurementBloodGlucose:
+		item.Description = "Gula darah Anda termasuk " + vital.Category + ". Batasi gula dan karbohidrat olahan, dan periksakan HbA1c ke dokter."
+		if vital.Category == "Low" {
+			item.Description = "Gula darah Anda rendah. Konsumsi karbohidrat cepat serap seperti jus buah dan ukur ulang dalam 15 menit."
+		}
+	case models.MeasurementSpO2:
+		item.Description = "Saturasi oksigen Anda rendah. Ukur ulang dalam keadaan istirahat dan segera cari pertolongan medis jika tetap rendah atau disertai sesak napas."
+	case models.MeasurementBodyTemperature:
+		item.Description = "Anda demam. Perbanyak minum dan istirahat; hubungi dokter jika suhu di atas 39°C atau berlangsung lebih dari 3 hari."
+		if vital.Category == "Hypothermia" {
+			item.Description = "Suhu tubuh Anda sangat rendah. Hangatkan tubuh dan segera cari pertolongan medis."
+		}
+	case models.MeasurementCholesterol:
+		item.Description = "Kolesterol Anda tinggi. Kurangi lemak jenuh dan gorengan, perbanyak serat, dan konsultasikan dengan dokter."
+	}
+	return item
+}
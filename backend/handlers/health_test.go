@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"health-tracker/insights"
+	"health-tracker/models"
+)
+
+// testStores holds the fakes behind the insights analyzer
+type testStores struct {
+	health       *fakeHealth
+	measurements *fakeMeasurements
+	goals        *fakeGoals
+	insights     *fakeInsights
+	analyzer     *insights.Analyzer
+}
+
+func newTestStores() *testStores {
+	s := &testStores{
+		health:       &fakeHealth{},
+		measurements: &fakeMeasurements{},
+		goals:        &fakeGoals{},
+		insights:     &fakeInsights{},
+	}
+	s.analyzer = insights.NewAnalyzer(s.health, s.measurements, s.goals, s.insights)
+	return s
+}
+
+func TestCreateHealthDataStoresUTCAndSyncsProfile(t *testing.T) {
+	stores := newTestStores()
+	users := newFakeUsers(models.User{ID: 1, Email: "ana@example.com"})
+	h := NewHealthHandler(stores.health, &fakeSymptoms{}, stores.measurements, &fakeWater{}, stores.insights, stores.analyzer, users)
+	r := newTestRouter(1, "")
+	r.POST("/health", h.CreateHealthData)
+
+	recorded := time.Now().Add(-48 * time.Hour).In(time.FixedZone("WIB", 7*60*60)).Truncate(time.Second)
+	w := serve(r, http.MethodPost, "/health", map[string]interface{}{
+		"weight_kg":   80,
+		"height_cm":   175,
+		"record_date": recorded,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create = %d, want 201: %s", w.Code, w.Body)
+	}
+
+	record := stores.health.records[0]
+	if record.RecordDate.Location() != time.UTC || !record.RecordDate.Equal(recorded) {
+		t.Errorf("record_date = %v, want %v in UTC", record.RecordDate, recorded)
+	}
+	if want := models.CalculateBMI(80, 175); record.BMI != want {
+		t.Errorf("bmi = %v, want %v", record.BMI, want)
+	}
+	if user := users.users[1]; user.WeightKg != 80 || user.HeightCm != 175 {
+		t.Errorf("profile not synced: weight %v, height %v", user.WeightKg, user.HeightCm)
+	}
+	if stores.insights.syncs != 1 {
+		t.Errorf("insights synced %d times, want 1", stores.insights.syncs)
+	}
+}
+
+func TestCreateHealthDataRejectsFutureDate(t *testing.T) {
+	stores := newTestStores()
+	h := NewHealthHandler(stores.health, &fakeSymptoms{}, stores.measurements, &fakeWater{}, stores.insights, stores.analyzer, newFakeUsers())
+	r := newTestRouter(1, "")
+	r.POST("/health", h.CreateHealthData)
+
+	w := serve(r, http.MethodPost, "/health", map[string]interface{}{
+		"weight_kg":   80,
+		"height_cm":   175,
+		"record_date": time.Now().Add(48 * time.Hour),
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("future record = %d, want 400", w.Code)
+	}
+	if resp := decodeResponse(t, w, nil); len(resp.Details) != 1 || resp.Details[0].Field != "record_date" {
+		t.Errorf("details = %+v, want a record_date error", resp.Details)
+	}
+	if len(stores.health.records) != 0 {
+		t.Error("future record stored")
+	}
+}
+
+func TestUpdateAndDeleteHealthDataResyncProfile(t *testing.T) {
+	stores := newTestStores()
+	now := time.Now().UTC()
+	stores.health.records = []models.HealthData{
+		{ID: 1, UserID: 1, WeightKg: 70, HeightCm: 170, RecordDate: now.Add(-72 * time.Hour)},
+		{ID: 2, UserID: 1, WeightKg: 72, HeightCm: 170, RecordDate: now.Add(-24 * time.Hour)},
+		{ID: 3, UserID: 2, WeightKg: 90, HeightCm: 180, RecordDate: now},
+	}
+	users := newFakeUsers(models.User{ID: 1, Email: "ana@example.com"}, models.User{ID: 2, Email: "budi@example.com"})
+	h := NewHealthHandler(stores.health, &fakeSymptoms{}, stores.measurements, &fakeWater{}, stores.insights, stores.analyzer, users)
+	r := newTestRouter(1, "")
+	r.PUT("/health/:id", h.UpdateHealthData)
+	r.DELETE("/health/:id", h.DeleteHealthData)
+
+	// Backdating the newer record makes the older one the latest
+	w := serve(r, http.MethodPut, "/health/2", map[string]interface{}{
+		"weight_kg":   71,
+		"height_cm":   171,
+		"record_date": now.Add(-96 * time.Hour),
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("update = %d, want 200: %s", w.Code, w.Body)
+	}
+	if record := stores.health.records[1]; record.BMI != models.CalculateBMI(71, 171) {
+		t.Errorf("bmi after update = %v", record.BMI)
+	}
+	if user := users.users[1]; user.WeightKg != 70 {
+		t.Errorf("profile weight after backdating = %v, want 70 from the now latest record", user.WeightKg)
+	}
+
+	for _, tt := range []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPut, "/health/3"},
+		{http.MethodDelete, "/health/3"},
+		{http.MethodDelete, "/health/99"},
+	} {
+		if w := serve(r, tt.method, tt.path, map[string]interface{}{"weight_kg": 50, "height_cm": 150}); w.Code != http.StatusNotFound {
+			t.Errorf("%s %s = %d, want 404", tt.method, tt.path, w.Code)
+		}
+	}
+
+	if w := serve(r, http.MethodDelete, "/health/1", nil); w.Code != http.StatusOK {
+		t.Fatalf("delete = %d, want 200: %s", w.Code, w.Body)
+	}
+	if user := users.users[1]; user.WeightKg != 71 || user.HeightCm != 171 {
+		t.Errorf("profile after delete = %v kg, %v cm; want the remaining record", user.WeightKg, user.HeightCm)
+	}
+	if len(stores.health.records) != 2 || users.users[2].WeightKg != 0 {
+		t.Error("another user's record or profile changed")
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"health-tracker/config"
+	"health-tracker/models"
+	"health-tracker/repository"
+	"health-tracker/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// failedLoginWindow is how long failed attempts count towards a lockout
+const failedLoginWindow = 24 * time.Hour
+
+// recordLoginAttempt stores a sign-in attempt with the caller's IP and user agent
+func (h *AuthHandler) recordLoginAttempt(c *gin.Context, userID *uint, email string, success bool, reason string) {
+	ctx := c.Request.Context()
+	h.loginAttempts.Create(ctx, &models.LoginAttempt{
+		UserID:    userID,
+		Email:     email,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Success:   success,
+		Reason:    reason,
+	})
+}
+
+// registerFailedLogin bumps the user's failure counter and locks the account with
+// exponential backoff once the configured threshold is reached. The counter is
+// incremented in SQL and read back in the same transaction, so parallel attempts each count.
+func (h *AuthHandler) registerFailedLogin(c *gin.Context, user *models.User, reason string) {
+	ctx := c.Request.Context()
+	now := time.Now()
+
+	err := h.tx.Transaction(ctx, func(tx *repository.Repositories) error {
+		failures, err := tx.Users.RecordFailedLogin(ctx, user.ID, now, failedLoginWindow)
+		if err != nil {
+			return err
+		}
+		if lockout := lockoutDuration(failures); lockout > 0 {
+			return tx.Users.Update(ctx, user.ID, map[string]interface{}{"locked_until": now.Add(lockout)})
+		}
+		return nil
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record failed login", "user_id", user.ID, "error", err)
+	}
+
+	h.recordLoginAttempt(c, &user.ID, user.Email, false, reason)
+}
+
+// registerSuccessfulLogin clears the failure counter and records the sign-in
+func (h *AuthHandler) registerSuccessfulLogin(c *gin.Context, user *models.User) {
+	ctx := c.Request.Context()
+	h.users.Update(ctx, user.ID, map[string]interface{}{
+		"failed_logins":        0,
+		"last_failed_login_at": nil,
+		"locked_until":         nil,
+	})
+
+	h.recordLoginAttempt(c, &user.ID, user.Email, true, "")
+}
+
+// lockoutDuration returns how long to lock an account after the given number of failures
+func lockoutDuration(failures int) time.Duration {
+	excess := failures - config.AppConfig.LoginMaxAttempts
+	if excess < 0 {
+		return 0
+	}
+
+	base := time.Duration(config.AppConfig.LoginLockoutBaseMinutes) * time.Minute
+	max := time.Duration(config.AppConfig.LoginLockoutMaxMinutes) * time.Minute
+
+	lockout := time.Duration(float64(base) * math.Pow(2, float64(excess)))
+	if lockout > max || lockout <= 0 {
+		return max
+	}
+	return lockout
+}
+
+// rejectLockedAccount answers with 429 and Retry-After when the account is locked
+func (h *AuthHandler) rejectLockedAccount(c *gin.Context, user *models.User) bool {
+	if !user.IsLocked() {
+		return false
+	}
+
+	h.recordLoginAttempt(c, &user.ID, user.Email, false, models.LoginReasonLocked)
+
+	retryAfter := int(math.Ceil(time.Until(*user.LockedUntil).Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	utils.ErrorResponseWithCode(c, http.StatusTooManyRequests, utils.CodeAccountLocked, "Akun dikunci sementara karena terlalu banyak percobaan login. Coba lagi dalam "+strconv.Itoa(retryAfter)+" detik")
+	return true
+}
+
+// GetLoginHistory returns recent sign-in attempts on the current user's account
+func (h *AuthHandler) GetLoginHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	attempts, err := h.loginAttempts.ListByUser(ctx, userID, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch login history")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login history retrieved", attempts)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"health-tracker/config"
+	"health-tracker/models"
+	"health-tracker/utils"
+)
+
+func TestLoginLocksAccountAfterRepeatedFailures(t *testing.T) {
+	hash, err := utils.HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	users := newFakeUsers(models.User{ID: 1, Email: "ana@example.com", Password: hash})
+	h, attempts := newTestAuthHandler(users, &fakeSessions{})
+	r := newTestRouter(0, "")
+	r.POST("/auth/login", h.Login)
+
+	wrong := models.LoginRequest{Email: "ana@example.com", Password: "wrong"}
+	for i := 0; i < config.AppConfig.LoginMaxAttempts; i++ {
+		if w := serve(r, http.MethodPost, "/auth/login", wrong); w.Code != http.StatusUnauthorized {
+			t.Fatalf("failed login %d = %d, want 401", i+1, w.Code)
+		}
+	}
+	if users.users[1].FailedLogins != config.AppConfig.LoginMaxAttempts {
+		t.Errorf("failed_logins = %d, want %d", users.users[1].FailedLogins, config.AppConfig.LoginMaxAttempts)
+	}
+
+	// Even the right password is refused while the account is locked
+	w := serve(r, http.MethodPost, "/auth/login", models.LoginRequest{Email: "ana@example.com", Password: "correct horse"})
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("login while locked = %d, want 429", w.Code)
+	}
+	if resp := decodeResponse(t, w, nil); resp.Code != utils.CodeAccountLocked {
+		t.Errorf("code = %q, want %q", resp.Code, utils.CodeAccountLocked)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("missing Retry-After header")
+	}
+
+	last := attempts.attempts[len(attempts.attempts)-1]
+	if last.Success || last.Reason != models.LoginReasonLocked {
+		t.Errorf("last attempt = %+v, want a failed attempt with reason %q", last, models.LoginReasonLocked)
+	}
+}
+
+func TestLockoutDuration(t *testing.T) {
+	previous := *config.AppConfig
+	t.Cleanup(func() { *config.AppConfig = previous })
+	config.AppConfig.LoginMaxAttempts = 5
+	config.AppConfig.LoginLockoutBaseMinutes = 1
+	config.AppConfig.LoginLockoutMaxMinutes = 60
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{4, 0},
+		{5, time.Minute},
+		{6, 2 * time.Minute},
+		{8, 8 * time.Minute},
+		{11, time.Hour}, // 64 minutes, capped
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := lockoutDuration(tt.failures); got != tt.want {
+			t.Errorf("lockoutDuration(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
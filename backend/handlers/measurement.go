@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"health-tracker/insights"
+	"health-tracker/metrics"
+	"health-tracker/models"
+	"health-tracker/repository"
+	"health-tracker/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MeasurementHandler serves vital sign readings such as blood pressure and glucose
+type MeasurementHandler struct {
+	measurements repository.MeasurementRepository
+	analyzer     *insights.Analyzer
+}
+
+// NewMeasurementHandler creates a MeasurementHandler
+func NewMeasurementHandler(measurements repository.MeasurementRepository, analyzer *insights.Analyzer) *MeasurementHandler {
+	return &MeasurementHandler{measurements: measurements, analyzer: analyzer}
+}
+
+// GetMeasurementTypes returns the supported types with their units, ranges and categories
+func (h *MeasurementHandler) GetMeasurementTypes(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Measurement types retrieved", models.MeasurementSpecs)
+}
+
+// CreateMeasurement records a reading, converted to the type's unit and classified
+func (h *MeasurementHandler) CreateMeasurement(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	var req models.MeasurementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	measurement, err := models.NewMeasurement(userID, req, time.Now())
+	var fieldErr *models.MeasurementFieldError
+	if errors.As(err, &fieldErr) {
+		utils.FieldErrorResponse(c, utils.FieldError{Field: fieldErr.Field, Rule: fieldErr.Rule, Message: fieldErr.Message})
+		return
+	}
+
+	if err := h.measurements.Create(ctx, measurement); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to save measurement")
+		return
+	}
+	metrics.MeasurementsRecorded.WithLabelValues(measurement.Type).Inc()
+	refreshInsights(ctx, h.analyzer, userID)
+
+	utils.SuccessResponse(c, http.StatusCreated, "Measurement saved", measurement)
+}
+
+// GetMeasurements returns the newest readings, optionally filtered with ?type=
+func (h *MeasurementHandler) GetMeasurements(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	measurementType := c.Query("type")
+	if _, ok := models.FindMeasurementSpec(measurementType); measurementType != "" && !ok {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Unknown measurement type")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "limit must be between 1 and 200")
+		return
+	}
+
+	measurements, err := h.measurements.List(ctx, userID, measurementType, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch measurements")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Measurements retrieved", measurements)
+}
+
+// GetLatestMeasurements returns the newest reading of each type
+func (h *MeasurementHandler) GetLatestMeasurements(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	measurements, err := h.measurements.LatestPerType(ctx, userID, time.Time{})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch measurements")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Latest measurements retrieved", measurements)
+}
+
+// DeleteMeasurement deletes a reading
+func (h *MeasurementHandler) DeleteMeasurement(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+	measurementID := paramID(c, "id")
+	if measurementID == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid measurement ID")
+		return
+	}
+
+	deleted, err := h.measurements.Delete(ctx, measurementID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete measurement")
+		return
+	}
+
+	if deleted == 0 {
+		utils.ErrorResponse(c, http.StatusNotFound, "Measurement not found")
+		return
+	}
+
+	refreshInsights(ctx, h.analyzer, userID)
+
+	utils.SuccessResponse(c, http.StatusOK, "Measurement deleted", nil)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"health-tracker/models"
+)
+
+func TestCreateMeasurementRejectsImplausibleValue(t *testing.T) {
+	stores := newTestStores()
+	h := NewMeasurementHandler(stores.measurements, stores.analyzer)
+	r := newTestRouter(1, "")
+	r.POST("/measurements", h.CreateMeasurement)
+
+	w := serve(r, http.MethodPost, "/measurements", map[string]interface{}{
+		"type":  models.MeasurementHeartRate,
+		"value": 500,
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("create = %d, want 400: %s", w.Code, w.Body)
+	}
+	if resp := decodeResponse(t, w, nil); len(resp.Details) != 1 || resp.Details[0].Field != "value" {
+		t.Errorf("details = %+v, want a value error", resp.Details)
+	}
+	if len(stores.measurements.measurements) != 0 || stores.insights.syncs != 0 {
+		t.Error("implausible reading stored or analysed")
+	}
+}
+
+func TestDeleteMeasurementOnlyDeletesOwnReadings(t *testing.T) {
+	stores := newTestStores()
+	stores.measurements.measurements = []models.Measurement{
+		{ID: 1, UserID: 2, Type: models.MeasurementHeartRate, Value: 70, MeasuredAt: time.Now()},
+	}
+	h := NewMeasurementHandler(stores.measurements, stores.analyzer)
+	r := newTestRouter(1, "")
+	r.DELETE("/measurements/:id", h.DeleteMeasurement)
+
+	if w := serve(r, http.MethodDelete, "/measurements/1", nil); w.Code != http.StatusNotFound {
+		t.Errorf("delete of another user's reading = %d, want 404", w.Code)
+	}
+	if w := serve(r, http.MethodDelete, "/measurements/abc", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("delete with invalid id = %d, want 400", w.Code)
+	}
+	if len(stores.measurements.measurements) != 1 {
+		t.Error("reading deleted")
+	}
+}
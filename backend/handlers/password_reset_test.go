@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"health-tracker/mailer"
+	"health-tracker/models"
+	"health-tracker/repository"
+	"health-tracker/utils"
+)
+
+// blockingMailer holds every message until release is closed
+type blockingMailer struct {
+	release chan struct{}
+	sent    chan mailer.Message
+}
+
+func (m *blockingMailer) Send(msg mailer.Message) error {
+	<-m.release
+	m.sent <- msg
+	return nil
+}
+
+func newTestResetHandler(users *fakeUsers, resets *fakePasswordResets) *AuthHandler {
+	sessions := &fakeSessions{}
+	attempts := &fakeLoginAttempts{}
+	repos := &repository.Repositories{Users: users, Sessions: sessions, PasswordResets: resets, LoginAttempts: attempts}
+	return NewAuthHandler(users, sessions, resets, nil, attempts, repos)
+}
+
+func TestForgotPasswordDoesNotWaitForMail(t *testing.T) {
+	m := &blockingMailer{release: make(chan struct{}), sent: make(chan mailer.Message, 1)}
+	previous := mailer.Default
+	mailer.Default = m
+	t.Cleanup(func() { mailer.Default = previous })
+
+	users := newFakeUsers(models.User{ID: 1, Email: "ana@example.com", Name: "Ana"})
+	resets := &fakePasswordResets{}
+	r := newTestRouter(0, "")
+	r.POST("/auth/forgot-password", newTestResetHandler(users, resets).ForgotPassword)
+
+	// Known and unknown addresses get the same answer without waiting on the mail server
+	known := serve(r, http.MethodPost, "/auth/forgot-password", models.ForgotPasswordRequest{Email: "ana@example.com"})
+	unknown := serve(r, http.MethodPost, "/auth/forgot-password", models.ForgotPasswordRequest{Email: "nobody@example.com"})
+	if known.Code != http.StatusOK || unknown.Code != http.StatusOK || known.Body.String() != unknown.Body.String() {
+		t.Fatalf("responses differ: %d %s / %d %s", known.Code, known.Body, unknown.Code, unknown.Body)
+	}
+	if len(resets.tokens) != 1 {
+		t.Fatalf("stored %d reset tokens, want 1", len(resets.tokens))
+	}
+
+	close(m.release)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := WaitBackground(ctx); err != nil {
+		t.Fatalf("WaitBackground: %v", err)
+	}
+	select {
+	case msg := <-m.sent:
+		if msg.To != "ana@example.com" || !strings.Contains(msg.Body, "/reset-password?token=") {
+			t.Errorf("mail = %+v, want a reset link for ana@example.com", msg)
+		}
+	default:
+		t.Fatal("no reset mail sent")
+	}
+	if len(m.sent) != 0 {
+		t.Error("mail sent for an unknown address")
+	}
+}
+
+func TestResetPasswordClearsLockout(t *testing.T) {
+	lockedUntil := time.Now().Add(time.Hour)
+	lastFailure := time.Now()
+	users := newFakeUsers(models.User{ID: 1, Email: "ana@example.com", Password: "!", FailedLogins: 6, LastFailedLoginAt: &lastFailure, LockedUntil: &lockedUntil})
+	resets := &fakePasswordResets{tokens: []*models.PasswordResetToken{{
+		ID:        1,
+		UserID:    1,
+		TokenHash: utils.HashToken("reset-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}}}
+	h := newTestResetHandler(users, resets)
+	r := newTestRouter(0, "")
+	r.POST("/auth/reset-password", h.ResetPassword)
+	r.POST("/auth/login", h.Login)
+
+	w := serve(r, http.MethodPost, "/auth/reset-password", models.ResetPasswordRequest{Token: "reset-token", NewPassword: "new secret"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("reset = %d, want 200: %s", w.Code, w.Body)
+	}
+	if user := users.users[1]; user.FailedLogins != 0 || user.LastFailedLoginAt != nil || user.LockedUntil != nil {
+		t.Errorf("user = %+v, want the lockout cleared", user)
+	}
+	if w := serve(r, http.MethodPost, "/auth/login", models.LoginRequest{Email: "ana@example.com", Password: "new secret"}); w.Code != http.StatusOK {
+		t.Errorf("login with the new password = %d, want 200", w.Code)
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"health-tracker/repository"
+	"health-tracker/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// probeTimeout bounds how long a readiness check waits for the database
+const probeTimeout = 2 * time.Second
+
+// ProbeHandler serves liveness and readiness probes
+type ProbeHandler struct {
+	system repository.SystemRepository
+}
+
+// NewProbeHandler creates a ProbeHandler
+func NewProbeHandler(system repository.SystemRepository) *ProbeHandler {
+	return &ProbeHandler{system: system}
+}
+
+// Livez reports that the process is up and serving requests. It never touches
+// the database, so a database outage does not get the container restarted.
+func (h *ProbeHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz reports whether the instance can take traffic: the database must
+// answer a ping and every embedded migration must be applied.
+func (h *ProbeHandler) Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), probeTimeout)
+	defer cancel()
+
+	// Errors are logged, not returned: they can name hosts and credentials and the probe is public
+	if err := h.system.Ping(ctx); err != nil {
+		slog.ErrorContext(ctx, "readiness check: database ping failed", "error", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":   "unavailable",
+			"database": gin.H{"status": "down"},
+		})
+		return
+	}
+
+	schema, err := h.system.SchemaState(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "readiness check: reading schema state failed", "error", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":     "unavailable",
+			"database":   gin.H{"status": "up"},
+			"migrations": gin.H{"status": "unknown"},
+		})
+		return
+	}
+
+	status, code := "ready", http.StatusOK
+	if schema.Pending > 0 {
+		status, code = "unavailable", http.StatusServiceUnavailable
+	}
+
+	c.JSON(code, gin.H{
+		"status":     status,
+		"database":   gin.H{"status": "up"},
+		"migrations": schema,
+	})
+}
+
+// ServerStatus is the legacy health check; it now reports a failing database
+func (h *ProbeHandler) ServerStatus(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), probeTimeout)
+	defer cancel()
+
+	if err := h.system.Ping(ctx); err != nil {
+		slog.ErrorContext(ctx, "status check: database ping failed", "error", err)
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, "Database is unavailable")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Health Tracker API is running", gin.H{"status": "ok"})
+}
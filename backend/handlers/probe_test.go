@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"health-tracker/database"
+)
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name   string
+		system *fakeSystem
+		want   int
+	}{
+		{"ready", &fakeSystem{schema: database.SchemaState{Current: 3, Latest: 3}}, http.StatusOK},
+		{"database down", &fakeSystem{pingErr: errors.New("dial tcp db.internal:5432: connection refused")}, http.StatusServiceUnavailable},
+		{"schema unreadable", &fakeSystem{schemaErr: errors.New("pq: password authentication failed for user \"app\"")}, http.StatusServiceUnavailable},
+		{"pending migrations", &fakeSystem{schema: database.SchemaState{Current: 2, Latest: 3, Pending: 1}}, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewProbeHandler(tt.system)
+			r := newTestRouter(0, "")
+			r.GET("/readyz", h.Readyz)
+			r.GET("/livez", h.Livez)
+
+			w := serve(r, http.MethodGet, "/readyz", nil)
+			if w.Code != tt.want {
+				t.Errorf("readyz = %d, want %d", w.Code, tt.want)
+			}
+			// Database errors stay in the server log
+			for _, leak := range []string{"db.internal", "password", "error"} {
+				if strings.Contains(w.Body.String(), leak) {
+					t.Errorf("readyz body %s contains %q", w.Body, leak)
+				}
+			}
+			// Liveness never depends on the database
+			if w := serve(r, http.MethodGet, "/livez", nil); w.Code != http.StatusOK {
+				t.Errorf("livez = %d, want 200", w.Code)
+			}
+		})
+	}
+}
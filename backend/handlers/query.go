@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"health-tracker/repository"
+	"health-tracker/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// List endpoints with cursor pagination return this many rows unless ?limit= says otherwise
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// queryParser reads list query parameters, collecting every invalid one so the client
+// gets them all in a single VALIDATION_FAILED response
+type queryParser struct {
+	c    *gin.Context
+	errs []utils.FieldError
+}
+
+func newQueryParser(c *gin.Context) *queryParser {
+	return &queryParser{c: c}
+}
+
+func (p *queryParser) fail(field, rule, message string) {
+	p.errs = append(p.errs, utils.FieldError{Field: field, Rule: rule, Message: message})
+}
+
+// ok responds with the collected errors and reports false if there were any
+func (p *queryParser) ok() bool {
+	if len(p.errs) == 0 {
+		return true
+	}
+	utils.FieldErrorResponse(p.c, p.errs...)
+	return false
+}
+
+// location parses ?tz= as an IANA zone name, defaulting to UTC
+func (p *queryParser) location() *time.Location {
+	name := p.c.Query("tz")
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || strings.EqualFold(name, "local") {
+		p.fail("tz", "timezone", "must be an IANA time zone such as Asia/Jakarta")
+		return time.UTC
+	}
+	return loc
+}
+
+// dateRange parses ?from= and ?to= as dates in loc or RFC 3339 timestamps. It returns
+// an inclusive start and exclusive end in UTC, the zone timestamps are compared in; a
+// date in ?to= includes that whole day.
+func (p *queryParser) dateRange(loc *time.Location) (time.Time, time.Time) {
+	from := p.time("from", loc, false)
+	to := p.time("to", loc, true)
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		p.fail("to", "gtfield", "must be after from")
+	}
+	return from, to
+}
+
+func (p *queryParser) time(field string, loc *time.Location, endOfDay bool) time.Time {
+	raw := p.c.Query(field)
+	if raw == "" {
+		return time.Time{}
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t.UTC()
+	}
+	day, err := time.ParseInLocation("2006-01-02", raw, loc)
+	if err != nil {
+		p.fail(field, "datetime", "must be a date (YYYY-MM-DD) or an RFC 3339 timestamp")
+		return time.Time{}
+	}
+	if endOfDay {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day.UTC()
+}
+
+// intRange parses an optional integer parameter, returning 0 when it is absent
+func (p *queryParser) intRange(field string, min, max int) int {
+	raw := p.c.Query(field)
+	if raw == "" {
+		return 0
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < min || v > max {
+		p.fail(field, "range", fmt.Sprintf("must be between %d and %d", min, max))
+		return 0
+	}
+	return v
+}
+
+// page parses ?sort= (a field name, "-" prefixed for descending), ?limit= and ?cursor=
+func (p *queryParser) page(fields map[string]repository.SortField, defaultSort string) repository.PageRequest {
+	page := repository.PageRequest{Limit: defaultPageLimit}
+
+	order := p.c.DefaultQuery("sort", defaultSort)
+	page.Sort = strings.TrimPrefix(order, "-")
+	page.Desc = strings.HasPrefix(order, "-")
+	if _, ok := fields[page.Sort]; !ok {
+		p.fail("sort", "oneof", "must be one of "+sortFieldList(fields)+", optionally prefixed with -")
+	}
+
+	if limit := p.intRange("limit", 1, maxPageLimit); limit > 0 {
+		page.Limit = limit
+	}
+
+	if token := p.c.Query("cursor"); token != "" {
+		cursor, err := repository.DecodeCursor(token)
+		if err != nil {
+			p.fail("cursor", "cursor", "is not a valid cursor")
+		} else if cursor.Sort != order {
+			p.fail("cursor", "cursor", "was issued for a different sort order")
+		}
+		page.After = cursor
+	}
+	return page
+}
+
+// cursorError reports whether err came from a cursor the repository could not use
+func cursorError(c *gin.Context, err error) bool {
+	if !errors.Is(err, repository.ErrInvalidCursor) {
+		return false
+	}
+	utils.FieldErrorResponse(c, utils.FieldError{Field: "cursor", Rule: "cursor", Message: "is not a valid cursor"})
+	return true
+}
+
+// encodeCursor returns the next_cursor token for a page, empty on the last page
+func encodeCursor(next *repository.Cursor) string {
+	if next == nil {
+		return ""
+	}
+	return next.Encode()
+}
+
+func sortFieldList(fields map[string]repository.SortField) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
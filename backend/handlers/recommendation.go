@@ -3,24 +3,35 @@ package handlers
 import (
 	"net/http"
 
-	"health-tracker/database"
 	"health-tracker/models"
+	"health-tracker/repository"
 	"health-tracker/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+// RecommendationHandler serves personalized food, exercise and emotional recommendations
+type RecommendationHandler struct {
+	users    repository.UserRepository
+	health   repository.HealthRepository
+	symptoms repository.SymptomRepository
+}
+
+// NewRecommendationHandler creates a RecommendationHandler
+func NewRecommendationHandler(users repository.UserRepository, health repository.HealthRepository, symptoms repository.SymptomRepository) *RecommendationHandler {
+	return &RecommendationHandler{users: users, health: health, symptoms: symptoms}
+}
+
 // GetFoodRecommendations returns personalized food recommendations
-func GetFoodRecommendations(c *gin.Context) {
+func (h *RecommendationHandler) GetFoodRecommendations(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
 	// Get latest health data
-	var health models.HealthData
-	database.DB.Where("user_id = ?", userID).Order("record_date desc").First(&health)
+	health := latestHealthData(ctx, h.health, userID)
 
 	// Get recent symptoms
-	var symptoms []models.Symptom
-	database.DB.Where("user_id = ?", userID).Order("logged_at desc").Limit(10).Find(&symptoms)
+	symptoms, _ := h.symptoms.Recent(ctx, userID, "", 10)
 
 	recommendations := generateFoodRecommendations(health, symptoms)
 
@@ -28,20 +39,21 @@ func GetFoodRecommendations(c *gin.Context) {
 }
 
 // GetExerciseRecommendations returns personalized exercise recommendations
-func GetExerciseRecommendations(c *gin.Context) {
+func (h *RecommendationHandler) GetExerciseRecommendations(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
 	// Get user profile
 	var user models.User
-	database.DB.First(&user, userID)
+	if found, err := h.users.FindByID(ctx, userID); err == nil {
+		user = *found
+	}
 
 	// Get latest health data
-	var health models.HealthData
-	database.DB.Where("user_id = ?", userID).Order("record_date desc").First(&health)
+	health := latestHealthData(ctx, h.health, userID)
 
 	// Get recent symptoms
-	var symptoms []models.Symptom
-	database.DB.Where("user_id = ?", userID).Order("logged_at desc").Limit(10).Find(&symptoms)
+	symptoms, _ := h.symptoms.Recent(ctx, userID, "", 10)
 
 	recommendations := generateExerciseRecommendations(user, health, symptoms)
 
@@ -49,17 +61,15 @@ func GetExerciseRecommendations(c *gin.Context) {
 }
 
 // GetEmotionalRecommendations returns emotional activity recommendations
-func GetEmotionalRecommendations(c *gin.Context) {
+func (h *RecommendationHandler) GetEmotionalRecommendations(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
 	// Get latest health data for emotional state
-	var health models.HealthData
-	database.DB.Where("user_id = ?", userID).Order("record_date desc").First(&health)
+	health := latestHealthData(ctx, h.health, userID)
 
 	// Get mental symptoms
-	var mentalSymptoms []models.Symptom
-	database.DB.Where("user_id = ? AND symptom_type = ?", userID, "mental").
-		Order("logged_at desc").Limit(5).Find(&mentalSymptoms)
+	mentalSymptoms, _ := h.symptoms.Recent(ctx, userID, "mental", 5)
 
 	recommendations := generateEmotionalRecommendations(health.EmotionalState, mentalSymptoms)
 
@@ -67,16 +77,15 @@ func GetEmotionalRecommendations(c *gin.Context) {
 }
 
 // GetDailyMenu returns personalized daily menu based on health condition
-func GetDailyMenu(c *gin.Context) {
+func (h *RecommendationHandler) GetDailyMenu(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
 	// Get latest health data
-	var health models.HealthData
-	database.DB.Where("user_id = ?", userID).Order("record_date desc").First(&health)
+	health := latestHealthData(ctx, h.health, userID)
 
 	// Get recent symptoms
-	var symptoms []models.Symptom
-	database.DB.Where("user_id = ?", userID).Order("logged_at desc").Limit(10).Find(&symptoms)
+	symptoms, _ := h.symptoms.Recent(ctx, userID, "", 10)
 
 	menu := generateDailyMenu(health, symptoms)
 
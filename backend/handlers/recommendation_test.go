@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"health-tracker/models"
+)
+
+func TestGetFoodRecommendationsFollowsLatestBMI(t *testing.T) {
+	health := &fakeHealth{records: []models.HealthData{
+		{ID: 1, UserID: 1, WeightKg: 60, HeightCm: 170, BMI: 20.8, RecordDate: time.Now().Add(-30 * 24 * time.Hour)},
+		{ID: 2, UserID: 1, WeightKg: 95, HeightCm: 170, BMI: 32.9, RecordDate: time.Now()},
+	}}
+	h := NewRecommendationHandler(newFakeUsers(), health, &fakeSymptoms{})
+	r := newTestRouter(1, "")
+	r.GET("/recommendations/food", h.GetFoodRecommendations)
+
+	w := serve(r, http.MethodGet, "/recommendations/food", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("food recommendations = %d, want 200", w.Code)
+	}
+	var recommendations []models.FoodRecommendation
+	decodeResponse(t, w, &recommendations)
+	if len(recommendations) == 0 || recommendations[0].Category != "weight_loss" {
+		t.Errorf("recommendations = %+v, want weight_loss first", recommendations)
+	}
+}
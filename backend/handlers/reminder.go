@@ -1,22 +1,32 @@
 package handlers
 
 import (
-	"health-tracker/database"
 	"health-tracker/models"
+	"health-tracker/repository"
+	"health-tracker/utils"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
+// ReminderHandler serves daily reminders
+type ReminderHandler struct {
+	reminders repository.ReminderRepository
+}
+
+// NewReminderHandler creates a ReminderHandler
+func NewReminderHandler(reminders repository.ReminderRepository) *ReminderHandler {
+	return &ReminderHandler{reminders: reminders}
+}
+
 // GetReminders returns all reminders for the authenticated user
-func GetReminders(c *gin.Context) {
-	userID := c.GetUint("user_id")
+func (h *ReminderHandler) GetReminders(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
 
-	var reminders []models.Reminder
-	result := database.DB.Where("user_id = ?", userID).Order("time ASC").Find(&reminders)
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reminders"})
+	reminders, err := h.reminders.ListByUser(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch reminders")
 		return
 	}
 
@@ -25,9 +35,15 @@ func GetReminders(c *gin.Context) {
 		defaults := models.DefaultReminders()
 		for i := range defaults {
 			defaults[i].UserID = userID
-			database.DB.Create(&defaults[i])
 		}
-		database.DB.Where("user_id = ?", userID).Order("time ASC").Find(&reminders)
+		if err := h.reminders.CreateBatch(ctx, defaults); err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create default reminders")
+			return
+		}
+		if reminders, err = h.reminders.ListByUser(ctx, userID); err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch reminders")
+			return
+		}
 	}
 
 	// Convert to response
@@ -36,19 +52,17 @@ func GetReminders(c *gin.Context) {
 		response[i] = r.ToResponse()
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":    response,
-		"message": "Reminders retrieved successfully",
-	})
+	utils.SuccessResponse(c, http.StatusOK, "Reminders retrieved successfully", response)
 }
 
 // CreateReminder creates a new reminder
-func CreateReminder(c *gin.Context) {
-	userID := c.GetUint("user_id")
+func (h *ReminderHandler) CreateReminder(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
 
 	var req models.CreateReminderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -60,35 +74,33 @@ func CreateReminder(c *gin.Context) {
 		IsActive: true,
 	}
 
-	if err := database.DB.Create(&reminder).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create reminder"})
+	if err := h.reminders.Create(ctx, &reminder); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create reminder")
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"data":    reminder.ToResponse(),
-		"message": "Reminder created successfully",
-	})
+	utils.SuccessResponse(c, http.StatusCreated, "Reminder created successfully", reminder.ToResponse())
 }
 
 // UpdateReminder updates an existing reminder
-func UpdateReminder(c *gin.Context) {
-	userID := c.GetUint("user_id")
-	reminderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reminder ID"})
+func (h *ReminderHandler) UpdateReminder(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+	reminderID := paramID(c, "id")
+	if reminderID == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid reminder ID")
 		return
 	}
 
-	var reminder models.Reminder
-	if err := database.DB.Where("id = ? AND user_id = ?", reminderID, userID).First(&reminder).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Reminder not found"})
+	reminder, err := h.reminders.Find(ctx, reminderID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Reminder not found")
 		return
 	}
 
 	var req models.UpdateReminderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -106,66 +118,60 @@ func UpdateReminder(c *gin.Context) {
 		reminder.IsActive = *req.IsActive
 	}
 
-	if err := database.DB.Save(&reminder).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reminder"})
+	if err := h.reminders.Save(ctx, reminder); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update reminder")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":    reminder.ToResponse(),
-		"message": "Reminder updated successfully",
-	})
+	utils.SuccessResponse(c, http.StatusOK, "Reminder updated successfully", reminder.ToResponse())
 }
 
 // DeleteReminder deletes a reminder
-func DeleteReminder(c *gin.Context) {
-	userID := c.GetUint("user_id")
-	reminderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reminder ID"})
+func (h *ReminderHandler) DeleteReminder(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+	reminderID := paramID(c, "id")
+	if reminderID == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid reminder ID")
 		return
 	}
 
-	result := database.DB.Where("id = ? AND user_id = ?", reminderID, userID).Delete(&models.Reminder{})
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete reminder"})
+	deleted, err := h.reminders.Delete(ctx, reminderID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete reminder")
 		return
 	}
 
-	if result.RowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Reminder not found"})
+	if deleted == 0 {
+		utils.ErrorResponse(c, http.StatusNotFound, "Reminder not found")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Reminder deleted successfully",
-	})
+	utils.SuccessResponse(c, http.StatusOK, "Reminder deleted successfully", nil)
 }
 
 // ToggleReminder toggles the active status of a reminder
-func ToggleReminder(c *gin.Context) {
-	userID := c.GetUint("user_id")
-	reminderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reminder ID"})
+func (h *ReminderHandler) ToggleReminder(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+	reminderID := paramID(c, "id")
+	if reminderID == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid reminder ID")
 		return
 	}
 
-	var reminder models.Reminder
-	if err := database.DB.Where("id = ? AND user_id = ?", reminderID, userID).First(&reminder).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Reminder not found"})
+	reminder, err := h.reminders.Find(ctx, reminderID, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Reminder not found")
 		return
 	}
 
 	reminder.IsActive = !reminder.IsActive
 
-	if err := database.DB.Save(&reminder).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to toggle reminder"})
+	if err := h.reminders.Save(ctx, reminder); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to toggle reminder")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":    reminder.ToResponse(),
-		"message": "Reminder toggled successfully",
-	})
+	utils.SuccessResponse(c, http.StatusOK, "Reminder toggled successfully", reminder.ToResponse())
 }
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"health-tracker/models"
+)
+
+func TestDeleteReminderOnlyDeletesOwnReminders(t *testing.T) {
+	reminders := &fakeReminders{reminders: []models.Reminder{
+		{ID: 1, UserID: 1, Type: "water", Time: "09:00"},
+		{ID: 2, UserID: 2, Type: "water", Time: "09:00"},
+	}}
+	h := NewReminderHandler(reminders)
+	r := newTestRouter(1, "")
+	r.DELETE("/reminders/:id", h.DeleteReminder)
+
+	if w := serve(r, http.MethodDelete, "/reminders/2", nil); w.Code != http.StatusNotFound {
+		t.Errorf("delete of another user's reminder = %d, want 404", w.Code)
+	}
+	if w := serve(r, http.MethodDelete, "/reminders/1", nil); w.Code != http.StatusOK {
+		t.Errorf("delete = %d, want 200", w.Code)
+	}
+	if len(reminders.reminders) != 1 || reminders.reminders[0].ID != 2 {
+		t.Errorf("remaining reminders = %+v, want only reminder 2", reminders.reminders)
+	}
+}
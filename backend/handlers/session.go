@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"health-tracker/models"
+	"health-tracker/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// startSession creates a new session for the user and returns a fresh token pair
+func (h *AuthHandler) startSession(c *gin.Context, user models.User) (models.LoginResponse, error) {
+	ctx := c.Request.Context()
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	now := time.Now()
+	session := models.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		UserAgent:        c.Request.UserAgent(),
+		IPAddress:        c.ClientIP(),
+		ExpiresAt:        now.Add(utils.RefreshTokenTTL()),
+		LastUsedAt:       now,
+	}
+
+	if err := h.sessions.Create(ctx, &session); err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	token, err := utils.GenerateToken(user.ID, user.Email, user.Role, session.ID)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	return models.LoginResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(utils.AccessTokenTTL().Seconds()),
+		User:         user,
+	}, nil
+}
+
+// RefreshToken rotates a refresh token and issues a new access token
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	oldHash := utils.HashToken(req.RefreshToken)
+	session, err := h.sessions.FindByTokenHash(ctx, oldHash)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid refresh token")
+		return
+	}
+
+	if !session.IsActive() {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Session expired or revoked")
+		return
+	}
+
+	user, err := h.users.FindByID(ctx, session.UserID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not found")
+		return
+	}
+
+	if user.IsSuspended() {
+		utils.ErrorResponseWithCode(c, http.StatusForbidden, utils.CodeAccountSuspended, "Akun Anda telah ditangguhkan")
+		return
+	}
+
+	// Rotate: the presented refresh token stops working as soon as a new one is issued. The
+	// update only matches while the old token is still active, so of two concurrent refreshes
+	// or a refresh racing a revocation only one can win.
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	session.RefreshTokenHash = utils.HashToken(refreshToken)
+	session.LastUsedAt = time.Now()
+	session.UserAgent = c.Request.UserAgent()
+	session.IPAddress = c.ClientIP()
+	rotated, err := h.sessions.Rotate(ctx, oldHash, session)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to refresh session")
+		return
+	}
+	if rotated == 0 {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Refresh token already used or revoked")
+		return
+	}
+
+	token, err := utils.GenerateToken(user.ID, user.Email, user.Role, session.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token refreshed", models.LoginResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(utils.AccessTokenTTL().Seconds()),
+		User:         *user,
+	})
+}
+
+// Logout revokes the session behind the current access token
+func (h *AuthHandler) Logout(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+	sessionID := c.GetUint("sessionID")
+
+	if err := h.sessions.Revoke(ctx, sessionID, userID); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to revoke session")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Logged out", nil)
+}
+
+// LogoutAll revokes every active session of the current user
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	revoked, err := h.sessions.RevokeAll(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to revoke sessions")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Logged out from all devices", gin.H{
+		"revoked_sessions": revoked,
+	})
+}
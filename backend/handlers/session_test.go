@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"health-tracker/models"
+	"health-tracker/repository"
+	"health-tracker/utils"
+)
+
+func newTestAuthHandler(users *fakeUsers, sessions *fakeSessions) (*AuthHandler, *fakeLoginAttempts) {
+	attempts := &fakeLoginAttempts{}
+	repos := &repository.Repositories{Users: users, Sessions: sessions, LoginAttempts: attempts}
+	return NewAuthHandler(users, sessions, nil, nil, attempts, repos), attempts
+}
+
+func TestRefreshTokenRotatesOnce(t *testing.T) {
+	users := newFakeUsers(models.User{ID: 1, Email: "ana@example.com", Role: models.RoleUser})
+	sessions := &fakeSessions{sessions: []*models.Session{{
+		ID:               1,
+		UserID:           1,
+		RefreshTokenHash: utils.HashToken("old-refresh-token"),
+		ExpiresAt:        time.Now().Add(time.Hour),
+	}}}
+	h, _ := newTestAuthHandler(users, sessions)
+	r := newTestRouter(0, "")
+	r.POST("/auth/refresh", h.RefreshToken)
+
+	body := models.RefreshTokenRequest{RefreshToken: "old-refresh-token"}
+	w := serve(r, http.MethodPost, "/auth/refresh", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("first refresh = %d, want 200: %s", w.Code, w.Body)
+	}
+	var login models.LoginResponse
+	decodeResponse(t, w, &login)
+	if login.RefreshToken == "" || sessions.sessions[0].RefreshTokenHash != utils.HashToken(login.RefreshToken) {
+		t.Errorf("session not moved to the new refresh token")
+	}
+
+	// The old token stopped working with the rotation
+	if w := serve(r, http.MethodPost, "/auth/refresh", body); w.Code != http.StatusUnauthorized {
+		t.Errorf("reused refresh token = %d, want 401", w.Code)
+	}
+}
+
+func TestRefreshTokenRejectsRevokedSession(t *testing.T) {
+	users := newFakeUsers(models.User{ID: 1, Email: "ana@example.com"})
+	revoked := time.Now()
+	sessions := &fakeSessions{sessions: []*models.Session{{
+		ID:               1,
+		UserID:           1,
+		RefreshTokenHash: utils.HashToken("refresh-token"),
+		ExpiresAt:        time.Now().Add(time.Hour),
+		RevokedAt:        &revoked,
+	}}}
+	h, _ := newTestAuthHandler(users, sessions)
+	r := newTestRouter(0, "")
+	r.POST("/auth/refresh", h.RefreshToken)
+
+	w := serve(r, http.MethodPost, "/auth/refresh", models.RefreshTokenRequest{RefreshToken: "refresh-token"})
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("refresh of a revoked session = %d, want 401", w.Code)
+	}
+}
+
+func TestLogoutAllRevokesOnlyOwnSessions(t *testing.T) {
+	users := newFakeUsers(models.User{ID: 1, Email: "ana@example.com"})
+	sessions := &fakeSessions{sessions: []*models.Session{
+		{ID: 1, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)},
+		{ID: 2, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)},
+		{ID: 3, UserID: 2, ExpiresAt: time.Now().Add(time.Hour)},
+	}}
+	h, _ := newTestAuthHandler(users, sessions)
+	r := newTestRouter(1, models.RoleUser)
+	r.POST("/auth/logout-all", h.LogoutAll)
+
+	w := serve(r, http.MethodPost, "/auth/logout-all", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("logout-all = %d, want 200: %s", w.Code, w.Body)
+	}
+	var data struct {
+		RevokedSessions int64 `json:"revoked_sessions"`
+	}
+	decodeResponse(t, w, &data)
+	if data.RevokedSessions != 2 {
+		t.Errorf("revoked_sessions = %d, want 2", data.RevokedSessions)
+	}
+	for _, session := range sessions.sessions {
+		if session.IsActive() != (session.UserID == 2) {
+			t.Errorf("session %d of user %d active = %v", session.ID, session.UserID, session.IsActive())
+		}
+	}
+}
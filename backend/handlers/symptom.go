@@ -1,20 +1,37 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 
-	"health-tracker/database"
+	"health-tracker/config"
+	"health-tracker/metrics"
 	"health-tracker/models"
+	"health-tracker/repository"
 	"health-tracker/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+// SymptomHandler serves symptom logging and statistics
+type SymptomHandler struct {
+	symptoms repository.SymptomRepository
+}
+
+// NewSymptomHandler creates a SymptomHandler
+func NewSymptomHandler(symptoms repository.SymptomRepository) *SymptomHandler {
+	return &SymptomHandler{symptoms: symptoms}
+}
+
 // GetSymptomList returns all available symptom templates
-func GetSymptomList(c *gin.Context) {
-	var symptoms []models.SymptomTemplate
-	database.DB.Find(&symptoms)
+func (h *SymptomHandler) GetSymptomList(c *gin.Context) {
+	ctx := c.Request.Context()
+	symptoms, err := h.symptoms.ListTemplates(ctx)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch symptom list")
+		return
+	}
 
 	// Group by type
 	physical := []models.SymptomTemplate{}
@@ -35,12 +52,13 @@ func GetSymptomList(c *gin.Context) {
 }
 
 // LogSymptom records a new symptom
-func LogSymptom(c *gin.Context) {
+func (h *SymptomHandler) LogSymptom(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
 	var req models.SymptomRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
@@ -50,29 +68,40 @@ func LogSymptom(c *gin.Context) {
 		SymptomName: req.SymptomName,
 		Severity:    req.Severity,
 		Notes:       req.Notes,
-		LoggedAt:    time.Now(),
+		LoggedAt:    time.Now().UTC(),
 	}
 
-	if result := database.DB.Create(&symptom); result.Error != nil {
+	if err := h.symptoms.Create(ctx, &symptom); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to log symptom")
 		return
 	}
+	metrics.SymptomsLogged.Inc()
 
 	utils.SuccessResponse(c, http.StatusCreated, "Symptom logged successfully", symptom)
 }
 
 // LogMultipleSymptoms records multiple symptoms at once
-func LogMultipleSymptoms(c *gin.Context) {
+func (h *SymptomHandler) LogMultipleSymptoms(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
 	var requests []models.SymptomRequest
 	if err := c.ShouldBindJSON(&requests); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+	if len(requests) == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Batch must contain at least one symptom")
+		return
+	}
+	if max := config.AppConfig.SymptomBatchMax; len(requests) > max {
+		utils.ErrorResponseWithCode(c, http.StatusBadRequest, utils.CodeBatchTooLarge,
+			fmt.Sprintf("Batch contains %d symptoms, the limit is %d", len(requests), max))
 		return
 	}
 
 	symptoms := make([]models.Symptom, len(requests))
-	now := time.Now()
+	now := time.Now().UTC()
 
 	for i, req := range requests {
 		symptoms[i] = models.Symptom{
@@ -85,66 +114,75 @@ func LogMultipleSymptoms(c *gin.Context) {
 		}
 	}
 
-	if result := database.DB.Create(&symptoms); result.Error != nil {
+	if err := h.symptoms.CreateBatch(ctx, symptoms); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to log symptoms")
 		return
 	}
+	metrics.SymptomsLogged.Add(float64(len(symptoms)))
 
 	utils.SuccessResponse(c, http.StatusCreated, "Symptoms logged successfully", symptoms)
 }
 
-// GetSymptomHistory returns user's symptom history
-func GetSymptomHistory(c *gin.Context) {
+// GetSymptomHistory returns a page of the user's symptoms, also grouped by day in ?tz=.
+// It accepts ?from=, ?to=, ?symptom_type=, ?symptom_name=, ?severity_gte=, ?severity_lte=,
+// ?sort= and cursor pagination.
+func (h *SymptomHandler) GetSymptomHistory(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
-	var symptoms []models.Symptom
-	database.DB.Where("user_id = ?", userID).Order("logged_at desc").Limit(50).Find(&symptoms)
+	query := newQueryParser(c)
+	loc := query.location()
+	filter := repository.SymptomFilter{
+		SymptomType: c.Query("symptom_type"),
+		SymptomName: c.Query("symptom_name"),
+		MinSeverity: query.intRange("severity_gte", 1, 10),
+		MaxSeverity: query.intRange("severity_lte", 1, 10),
+	}
+	filter.From, filter.To = query.dateRange(loc)
+	if filter.MinSeverity > 0 && filter.MaxSeverity > 0 && filter.MinSeverity > filter.MaxSeverity {
+		query.fail("severity_lte", "gtefield", "must not be lower than severity_gte")
+	}
+	page := query.page(repository.SymptomSortFields, "-logged_at")
+	if !query.ok() {
+		return
+	}
+
+	symptoms, next, err := h.symptoms.List(ctx, userID, filter, page)
+	if cursorError(c, err) {
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch symptom history")
+		return
+	}
 
-	// Group by date
+	// Group by the client's calendar day, not the server's
 	grouped := make(map[string][]models.Symptom)
 	for _, s := range symptoms {
-		date := s.LoggedAt.Format("2006-01-02")
+		date := s.LoggedAt.In(loc).Format("2006-01-02")
 		grouped[date] = append(grouped[date], s)
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Symptom history retrieved", gin.H{
+	utils.PaginatedResponse(c, "Symptom history retrieved", gin.H{
 		"symptoms": symptoms,
 		"grouped":  grouped,
-	})
+	}, utils.NewCursorPagination(page.Limit, encodeCursor(next)))
 }
 
 // GetSymptomStats returns symptom statistics
-func GetSymptomStats(c *gin.Context) {
+func (h *SymptomHandler) GetSymptomStats(c *gin.Context) {
+	ctx := c.Request.Context()
 	userID := c.GetUint("userID")
 
 	// Most frequent symptoms
-	type SymptomCount struct {
-		SymptomName string `json:"symptom_name"`
-		Count       int    `json:"count"`
-	}
-
-	var frequentSymptoms []SymptomCount
-	database.DB.Model(&models.Symptom{}).
-		Select("symptom_name, COUNT(*) as count").
-		Where("user_id = ?", userID).
-		Group("symptom_name").
-		Order("count desc").
-		Limit(5).
-		Scan(&frequentSymptoms)
+	frequentSymptoms, _ := h.symptoms.MostFrequent(ctx, userID, 5)
 
 	// Symptoms this week
 	weekAgo := time.Now().AddDate(0, 0, -7)
-	var weekCount int64
-	database.DB.Model(&models.Symptom{}).
-		Where("user_id = ? AND logged_at > ?", userID, weekAgo).
-		Count(&weekCount)
+	weekCount, _ := h.symptoms.CountSince(ctx, userID, weekAgo)
 
 	// Average severity
-	var avgSeverity float64
-	database.DB.Model(&models.Symptom{}).
-		Select("COALESCE(AVG(severity), 0)").
-		Where("user_id = ?", userID).
-		Scan(&avgSeverity)
+	avgSeverity, _ := h.symptoms.AverageSeverity(ctx, userID)
 
 	utils.SuccessResponse(c, http.StatusOK, "Symptom stats retrieved", gin.H{
 		"frequent_symptoms":  frequentSymptoms,
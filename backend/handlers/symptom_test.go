@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"health-tracker/models"
+)
+
+func TestLogSymptomValidatesSeverity(t *testing.T) {
+	symptoms := &fakeSymptoms{}
+	h := NewSymptomHandler(symptoms)
+	r := newTestRouter(1, "")
+	r.POST("/symptoms", h.LogSymptom)
+
+	w := serve(r, http.MethodPost, "/symptoms", models.SymptomRequest{SymptomType: "physical", SymptomName: "Pusing", Severity: 11})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("severity 11 = %d, want 400", w.Code)
+	}
+	if resp := decodeResponse(t, w, nil); len(resp.Details) != 1 || resp.Details[0].Field != "severity" {
+		t.Errorf("details = %+v, want a severity error", resp.Details)
+	}
+
+	w = serve(r, http.MethodPost, "/symptoms", models.SymptomRequest{SymptomType: "physical", SymptomName: "Pusing", Severity: 4})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("log = %d, want 201: %s", w.Code, w.Body)
+	}
+	if len(symptoms.symptoms) != 1 || symptoms.symptoms[0].UserID != 1 || symptoms.symptoms[0].LoggedAt.IsZero() {
+		t.Errorf("stored symptoms = %+v", symptoms.symptoms)
+	}
+}
@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"health-tracker/models"
+	"health-tracker/repository"
+	"health-tracker/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	totpIssuer        = "Health Tracker"
+	recoveryCodeCount = 10
+)
+
+// SetupTwoFactor generates a new TOTP secret; 2FA stays disabled until confirmed
+func (h *AuthHandler) SetupTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	user, err := h.users.FindByID(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if user.TwoFactorEnabled {
+		utils.ErrorResponse(c, http.StatusConflict, "Two-factor authentication is already enabled")
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate secret")
+		return
+	}
+
+	if err := h.users.Update(ctx, user.ID, map[string]interface{}{
+		"two_factor_secret":    secret,
+		"two_factor_last_step": 0,
+	}); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to store secret")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Scan the QR code with your authenticator app", models.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(secret, user.Email, totpIssuer),
+	})
+}
+
+// ConfirmTwoFactor enables 2FA once the user proves their app produces valid codes
+func (h *AuthHandler) ConfirmTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, err := h.users.FindByID(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if user.TwoFactorEnabled {
+		utils.ErrorResponse(c, http.StatusConflict, "Two-factor authentication is already enabled")
+		return
+	}
+	if user.TwoFactorSecret == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Start two-factor setup first")
+		return
+	}
+
+	if h.rejectLockedAccount(c, user) {
+		return
+	}
+
+	step, ok := utils.ValidateTOTP(user.TwoFactorSecret, req.Code, user.TwoFactorLastStep)
+	if !ok {
+		h.registerFailedLogin(c, user, models.LoginReasonInvalid2FACode)
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid verification code")
+		return
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to enable two-factor authentication")
+		return
+	}
+
+	err = h.tx.Transaction(ctx, func(tx *repository.Repositories) error {
+		if err := tx.Users.Update(ctx, user.ID, map[string]interface{}{
+			"two_factor_enabled":   true,
+			"two_factor_last_step": step,
+		}); err != nil {
+			return err
+		}
+		return tx.RecoveryCodes.Replace(ctx, user.ID, hashes)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to enable two-factor authentication")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication enabled", models.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// RegenerateRecoveryCodes invalidates old recovery codes and issues a new set
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, err := h.users.FindByID(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if !user.TwoFactorEnabled {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Two-factor authentication is not enabled")
+		return
+	}
+
+	if h.rejectLockedAccount(c, user) {
+		return
+	}
+
+	step, ok := utils.ValidateTOTP(user.TwoFactorSecret, req.Code, user.TwoFactorLastStep)
+	if !ok {
+		h.registerFailedLogin(c, user, models.LoginReasonInvalid2FACode)
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid verification code")
+		return
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate recovery codes")
+		return
+	}
+
+	err = h.tx.Transaction(ctx, func(tx *repository.Repositories) error {
+		if err := tx.Users.Update(ctx, user.ID, map[string]interface{}{"two_factor_last_step": step}); err != nil {
+			return err
+		}
+		return tx.RecoveryCodes.Replace(ctx, user.ID, hashes)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate recovery codes")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recovery codes regenerated", models.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// DisableTwoFactor turns 2FA off after checking the password and a current code
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	var req models.TwoFactorDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, err := h.users.FindByID(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if !user.TwoFactorEnabled {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Two-factor authentication is not enabled")
+		return
+	}
+
+	if h.rejectLockedAccount(c, user) {
+		return
+	}
+
+	if !utils.CheckPassword(req.Password, user.Password) {
+		h.registerFailedLogin(c, user, models.LoginReasonInvalidPassword)
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid password")
+		return
+	}
+
+	if !verifySecondFactor(ctx, h.users, h.recoveryCodes, user, req.Code) {
+		h.registerFailedLogin(c, user, models.LoginReasonInvalid2FACode)
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid verification code")
+		return
+	}
+
+	err = h.tx.Transaction(ctx, func(tx *repository.Repositories) error {
+		if err := tx.Users.Update(ctx, user.ID, map[string]interface{}{
+			"two_factor_enabled":   false,
+			"two_factor_secret":    "",
+			"two_factor_last_step": 0,
+		}); err != nil {
+			return err
+		}
+		return tx.RecoveryCodes.DeleteAll(ctx, user.ID)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to disable two-factor authentication")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+// VerifyTwoFactor exchanges a login challenge plus a second factor for a full session
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req models.TwoFactorVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	claims, err := utils.ValidateChallengeToken(req.ChallengeToken)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid or expired challenge")
+		return
+	}
+
+	challengeHash := utils.HashToken(claims.ID)
+	user, err := h.users.FindByID(ctx, claims.UserID)
+	if err != nil || !user.TwoFactorEnabled || user.LoginChallenge != challengeHash {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid or expired challenge")
+		return
+	}
+
+	if h.rejectLockedAccount(c, user) {
+		return
+	}
+
+	if user.IsSuspended() {
+		utils.ErrorResponseWithCode(c, http.StatusForbidden, utils.CodeAccountSuspended, "Akun Anda telah ditangguhkan")
+		return
+	}
+
+	if !verifySecondFactor(ctx, h.users, h.recoveryCodes, user, req.Code) {
+		h.registerFailedLogin(c, user, models.LoginReasonInvalid2FACode)
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid verification code")
+		return
+	}
+
+	// A concurrent request may have exchanged the same challenge first
+	consumed, err := h.users.ConsumeLoginChallenge(ctx, user.ID, challengeHash)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to verify challenge")
+		return
+	}
+	if !consumed {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid or expired challenge")
+		return
+	}
+
+	h.registerSuccessfulLogin(c, user)
+
+	response, err := h.startSession(c, *user)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", response)
+}
+
+// verifySecondFactor accepts either a TOTP code or an unused recovery code
+func verifySecondFactor(ctx context.Context, users repository.UserRepository, recoveryCodes repository.RecoveryCodeRepository, user *models.User, code string) bool {
+	if step, ok := utils.ValidateTOTP(user.TwoFactorSecret, code, user.TwoFactorLastStep); ok {
+		advanced, err := users.AdvanceTwoFactorStep(ctx, user.ID, step)
+		return err == nil && advanced
+	}
+
+	used, err := recoveryCodes.Use(ctx, user.ID, utils.HashToken(normalizeRecoveryCode(code)))
+	return err == nil && used
+}
+
+// generateRecoveryCodes returns a new set of codes for the user and the hashes to store
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		code := hex.EncodeToString(b)
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = utils.HashToken(code)
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode makes codes comparable regardless of case and separators
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+}
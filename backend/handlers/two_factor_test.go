@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"health-tracker/config"
+	"health-tracker/models"
+	"health-tracker/repository"
+	"health-tracker/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	testTOTPSecret   = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	testRecoveryCode = "abcde-12345"
+)
+
+// newTwoFactorTest returns a router serving the 2FA login and disable endpoints for a
+// user with 2FA enabled, password "correct horse" and one unused recovery code
+func newTwoFactorTest(t *testing.T) (*gin.Engine, *fakeUsers) {
+	t.Helper()
+	hash, err := utils.HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	users := newFakeUsers(models.User{ID: 1, Email: "ana@example.com", Password: hash, TwoFactorEnabled: true, TwoFactorSecret: testTOTPSecret})
+	sessions := &fakeSessions{}
+	recoveryCodes := &fakeRecoveryCodes{unused: map[string]bool{utils.HashToken(normalizeRecoveryCode(testRecoveryCode)): true}}
+	attempts := &fakeLoginAttempts{}
+	repos := &repository.Repositories{Users: users, Sessions: sessions, RecoveryCodes: recoveryCodes, LoginAttempts: attempts}
+	h := NewAuthHandler(users, sessions, nil, recoveryCodes, attempts, repos)
+
+	r := newTestRouter(0, "")
+	r.POST("/auth/login", h.Login)
+	r.POST("/auth/2fa/verify", h.VerifyTwoFactor)
+	r.POST("/auth/2fa/disable", func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		h.DisableTwoFactor(c)
+	})
+	return r, users
+}
+
+// challenge logs in with the password and returns the 2FA challenge token
+func challenge(t *testing.T, r *gin.Engine) string {
+	t.Helper()
+	w := serve(r, http.MethodPost, "/auth/login", models.LoginRequest{Email: "ana@example.com", Password: "correct horse"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("login = %d, want 200: %s", w.Code, w.Body)
+	}
+	var resp models.TwoFactorChallengeResponse
+	decodeResponse(t, w, &resp)
+	if !resp.TwoFactorRequired || resp.ChallengeToken == "" {
+		t.Fatalf("login response = %+v, want a challenge", resp)
+	}
+	return resp.ChallengeToken
+}
+
+func currentCode(t *testing.T) string {
+	t.Helper()
+	code, err := utils.TOTPCode(testTOTPSecret, utils.TOTPStep(time.Now()))
+	if err != nil {
+		t.Fatalf("TOTPCode: %v", err)
+	}
+	return code
+}
+
+func verify(r *gin.Engine, token, code string) int {
+	return serve(r, http.MethodPost, "/auth/2fa/verify", models.TwoFactorVerifyRequest{ChallengeToken: token, Code: code}).Code
+}
+
+func TestVerifyTwoFactorRejectsUsedCode(t *testing.T) {
+	r, users := newTwoFactorTest(t)
+
+	code := currentCode(t)
+	if got := verify(r, challenge(t, r), code); got != http.StatusOK {
+		t.Fatalf("first verify = %d, want 200", got)
+	}
+	// The same code on a fresh challenge is a replay
+	if got := verify(r, challenge(t, r), code); got != http.StatusUnauthorized {
+		t.Errorf("reused code = %d, want 401", got)
+	}
+	if users.users[1].FailedLogins != 1 {
+		t.Errorf("failed_logins = %d, want 1", users.users[1].FailedLogins)
+	}
+}
+
+func TestVerifyTwoFactorRecoveryCodeIsSingleUse(t *testing.T) {
+	r, _ := newTwoFactorTest(t)
+
+	// Recovery codes are accepted in any case and with or without the dash
+	if got := verify(r, challenge(t, r), "ABCDE12345"); got != http.StatusOK {
+		t.Fatalf("recovery code = %d, want 200", got)
+	}
+	if got := verify(r, challenge(t, r), testRecoveryCode); got != http.StatusUnauthorized {
+		t.Errorf("used recovery code = %d, want 401", got)
+	}
+}
+
+func TestVerifyTwoFactorChallengeIsSingleUse(t *testing.T) {
+	r, _ := newTwoFactorTest(t)
+
+	token := challenge(t, r)
+	if got := verify(r, token, currentCode(t)); got != http.StatusOK {
+		t.Fatalf("first verify = %d, want 200", got)
+	}
+	if got := verify(r, token, testRecoveryCode); got != http.StatusUnauthorized {
+		t.Errorf("reused challenge = %d, want 401", got)
+	}
+
+	// A newer login replaces the pending challenge
+	old := challenge(t, r)
+	challenge(t, r)
+	if got := verify(r, old, testRecoveryCode); got != http.StatusUnauthorized {
+		t.Errorf("superseded challenge = %d, want 401", got)
+	}
+}
+
+func TestFailedTwoFactorCodesLockAccount(t *testing.T) {
+	r, users := newTwoFactorTest(t)
+
+	token := challenge(t, r)
+	for i := 0; i < config.AppConfig.LoginMaxAttempts; i++ {
+		if got := verify(r, token, "000000"); got != http.StatusUnauthorized {
+			t.Fatalf("wrong code %d = %d, want 401", i+1, got)
+		}
+	}
+	if !users.users[1].IsLocked() {
+		t.Fatalf("account not locked after %d wrong codes", config.AppConfig.LoginMaxAttempts)
+	}
+	if got := verify(r, token, currentCode(t)); got != http.StatusTooManyRequests {
+		t.Errorf("verify while locked = %d, want 429", got)
+	}
+	disable := models.TwoFactorDisableRequest{Password: "correct horse", Code: currentCode(t)}
+	if w := serve(r, http.MethodPost, "/auth/2fa/disable", disable); w.Code != http.StatusTooManyRequests {
+		t.Errorf("disable while locked = %d, want 429", w.Code)
+	}
+}
+
+func TestDisableTwoFactorCountsFailedCodes(t *testing.T) {
+	r, users := newTwoFactorTest(t)
+
+	disable := models.TwoFactorDisableRequest{Password: "correct horse", Code: "000000"}
+	if w := serve(r, http.MethodPost, "/auth/2fa/disable", disable); w.Code != http.StatusUnauthorized {
+		t.Fatalf("disable with a wrong code = %d, want 401", w.Code)
+	}
+	if users.users[1].FailedLogins != 1 || !users.users[1].TwoFactorEnabled {
+		t.Errorf("user = %+v, want one failure and 2FA still enabled", users.users[1])
+	}
+}
@@ -1,192 +1,168 @@
 package handlers
 
 import (
-	"health-tracker/database"
+	"context"
+	"errors"
+	"health-tracker/metrics"
 	"health-tracker/models"
+	"health-tracker/repository"
+	"health-tracker/utils"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// WaterHandler serves the daily water tracker
+type WaterHandler struct {
+	water repository.WaterRepository
+}
+
+// NewWaterHandler creates a WaterHandler
+func NewWaterHandler(water repository.WaterRepository) *WaterHandler {
+	return &WaterHandler{water: water}
+}
+
 // GetWaterIntake returns today's water intake for the user
-func GetWaterIntake(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
+func (h *WaterHandler) GetWaterIntake(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
 
-	today := time.Now().Format("2006-01-02")
-	var water models.WaterIntake
-
-	if err := database.DB.Where("user_id = ? AND date = ?", userID, today).First(&water).Error; err != nil {
-		// Create new record for today
-		water = models.WaterIntake{
-			UserID:    userID.(uint),
-			Glasses:   0,
-			Goal:      8,
-			Date:      today,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		}
-		database.DB.Create(&water)
+	water, err := h.today(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch water intake")
+		return
 	}
 
-	c.JSON(http.StatusOK, models.WaterIntakeResponse{
-		ID:         water.ID,
-		Glasses:    water.Glasses,
-		Goal:       water.Goal,
-		Date:       water.Date,
-		Percentage: water.GetPercentage(),
-		Remaining:  water.GetRemaining(),
-	})
+	utils.SuccessResponse(c, http.StatusOK, "Water intake retrieved", toWaterIntakeResponse(*water))
 }
 
 // AddWaterGlass adds a glass of water
-func AddWaterGlass(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+func (h *WaterHandler) AddWaterGlass(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
+
+	water, err := h.today(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update water intake")
 		return
 	}
 
-	today := time.Now().Format("2006-01-02")
-	var water models.WaterIntake
-
-	if err := database.DB.Where("user_id = ? AND date = ?", userID, today).First(&water).Error; err != nil {
-		// Create new record for today
-		water = models.WaterIntake{
-			UserID:    userID.(uint),
-			Glasses:   1,
-			Goal:      8,
-			Date:      today,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		}
-		database.DB.Create(&water)
-	} else {
-		// Update existing record
-		water.Glasses++
-		water.UpdatedAt = time.Now()
-		database.DB.Save(&water)
+	water.Glasses++
+	water.UpdatedAt = time.Now()
+	if err := h.water.Save(ctx, water); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update water intake")
+		return
 	}
+	metrics.WaterGlassesAdded.Inc()
 
-	c.JSON(http.StatusOK, models.WaterIntakeResponse{
-		ID:         water.ID,
-		Glasses:    water.Glasses,
-		Goal:       water.Goal,
-		Date:       water.Date,
-		Percentage: water.GetPercentage(),
-		Remaining:  water.GetRemaining(),
-	})
+	utils.SuccessResponse(c, http.StatusOK, "Glass added", toWaterIntakeResponse(*water))
 }
 
 // RemoveWaterGlass removes a glass of water
-func RemoveWaterGlass(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
+func (h *WaterHandler) RemoveWaterGlass(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
 
-	today := time.Now().Format("2006-01-02")
-	var water models.WaterIntake
-
-	if err := database.DB.Where("user_id = ? AND date = ?", userID, today).First(&water).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "No water intake record for today"})
+	water, err := h.water.FindByDate(ctx, userID, time.Now().Format("2006-01-02"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "No water intake record for today")
 		return
 	}
 
 	if water.Glasses > 0 {
 		water.Glasses--
 		water.UpdatedAt = time.Now()
-		database.DB.Save(&water)
+		if err := h.water.Save(ctx, water); err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update water intake")
+			return
+		}
 	}
 
-	c.JSON(http.StatusOK, models.WaterIntakeResponse{
-		ID:         water.ID,
-		Glasses:    water.Glasses,
-		Goal:       water.Goal,
-		Date:       water.Date,
-		Percentage: water.GetPercentage(),
-		Remaining:  water.GetRemaining(),
-	})
+	utils.SuccessResponse(c, http.StatusOK, "Glass removed", toWaterIntakeResponse(*water))
 }
 
 // UpdateWaterGoal updates the daily water goal
-func UpdateWaterGoal(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
+func (h *WaterHandler) UpdateWaterGoal(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
 
-	var input struct {
-		Goal int `json:"goal" binding:"required,min=1,max=20"`
-	}
+	var input models.UpdateWaterGoalRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
-	today := time.Now().Format("2006-01-02")
-	var water models.WaterIntake
-
-	if err := database.DB.Where("user_id = ? AND date = ?", userID, today).First(&water).Error; err != nil {
-		water = models.WaterIntake{
-			UserID:    userID.(uint),
-			Glasses:   0,
-			Goal:      input.Goal,
-			Date:      today,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		}
-		database.DB.Create(&water)
-	} else {
-		water.Goal = input.Goal
-		water.UpdatedAt = time.Now()
-		database.DB.Save(&water)
+	water, err := h.today(ctx, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update water goal")
+		return
 	}
 
-	c.JSON(http.StatusOK, models.WaterIntakeResponse{
-		ID:         water.ID,
-		Glasses:    water.Glasses,
-		Goal:       water.Goal,
-		Date:       water.Date,
-		Percentage: water.GetPercentage(),
-		Remaining:  water.GetRemaining(),
-	})
+	water.Goal = input.Goal
+	water.UpdatedAt = time.Now()
+	if err := h.water.Save(ctx, water); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update water goal")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Water goal updated", toWaterIntakeResponse(*water))
 }
 
 // GetWaterHistory returns water intake history for past days
-func GetWaterHistory(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
+func (h *WaterHandler) GetWaterHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetUint("userID")
 
-	var history []models.WaterIntake
-	
 	// Get last 7 days
-	database.DB.Where("user_id = ?", userID).
-		Order("date DESC").
-		Limit(7).
-		Find(&history)
+	history, err := h.water.Recent(ctx, userID, 7)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch water history")
+		return
+	}
 
 	var response []models.WaterIntakeResponse
 	for _, water := range history {
-		response = append(response, models.WaterIntakeResponse{
-			ID:         water.ID,
-			Glasses:    water.Glasses,
-			Goal:       water.Goal,
-			Date:       water.Date,
-			Percentage: water.GetPercentage(),
-			Remaining:  water.GetRemaining(),
-		})
+		response = append(response, toWaterIntakeResponse(water))
 	}
 
-	c.JSON(http.StatusOK, response)
+	utils.SuccessResponse(c, http.StatusOK, "Water history retrieved", response)
+}
+
+// today returns today's record, creating an empty one with the default goal if needed
+func (h *WaterHandler) today(ctx context.Context, userID uint) (*models.WaterIntake, error) {
+	today := time.Now().Format("2006-01-02")
+
+	water, err := h.water.FindByDate(ctx, userID, today)
+	if err == nil {
+		return water, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	water = &models.WaterIntake{
+		UserID:    userID,
+		Glasses:   0,
+		Goal:      8,
+		Date:      today,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := h.water.Create(ctx, water); err != nil {
+		return nil, err
+	}
+	return water, nil
+}
+
+func toWaterIntakeResponse(water models.WaterIntake) models.WaterIntakeResponse {
+	return models.WaterIntakeResponse{
+		ID:         water.ID,
+		Glasses:    water.Glasses,
+		Goal:       water.Goal,
+		Date:       water.Date,
+		Percentage: water.GetPercentage(),
+		Remaining:  water.GetRemaining(),
+	}
 }
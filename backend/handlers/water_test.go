@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"health-tracker/models"
+)
+
+func TestAddWaterGlassStartsTodaysRecord(t *testing.T) {
+	water := &fakeWater{}
+	h := NewWaterHandler(water)
+	r := newTestRouter(1, "")
+	r.POST("/water/add", h.AddWaterGlass)
+
+	for want := 1; want <= 2; want++ {
+		w := serve(r, http.MethodPost, "/water/add", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("add glass = %d, want 200", w.Code)
+		}
+		var intake models.WaterIntakeResponse
+		decodeResponse(t, w, &intake)
+		if intake.Glasses != want || intake.Goal != 8 {
+			t.Errorf("after %d glasses: %+v", want, intake)
+		}
+	}
+	if len(water.intakes) != 1 || water.intakes[0].Glasses != 2 {
+		t.Errorf("stored intakes = %+v, want one record with 2 glasses", water.intakes)
+	}
+}
@@ -0,0 +1,101 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"health-tracker/metrics"
+	"health-tracker/models"
+	"health-tracker/repository"
+)
+
+// historyWindow is how much history an analysis loads
+const historyWindow = 180 * day
+
+// Analyzer loads a user's readings, analyses them and stores the resulting insights
+type Analyzer struct {
+	health       repository.HealthRepository
+	measurements repository.MeasurementRepository
+	goals        repository.GoalRepository
+	store        repository.InsightRepository
+}
+
+// NewAnalyzer creates an Analyzer
+func NewAnalyzer(health repository.HealthRepository, measurements repository.MeasurementRepository, goals repository.GoalRepository, store repository.InsightRepository) *Analyzer {
+	return &Analyzer{health: health, measurements: measurements, goals: goals, store: store}
+}
+
+// Refresh analyses the last 180 days, syncs the stored insights with what it found and
+// returns the trends. Call it after any write that changes the readings or weight goal.
+func (a *Analyzer) Refresh(ctx context.Context, userID uint, now time.Time) ([]models.MetricTrend, error) {
+	trends, found, err := a.analyze(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	created, err := a.store.Sync(ctx, userID, isoWeek(now), found)
+	if err != nil {
+		return nil, err
+	}
+	metrics.InsightsGenerated.Add(float64(created))
+	return trends, nil
+}
+
+// Trends analyses the last 180 days like Refresh but stores nothing
+func (a *Analyzer) Trends(ctx context.Context, userID uint, now time.Time) ([]models.MetricTrend, error) {
+	trends, _, err := a.analyze(ctx, userID, now)
+	return trends, err
+}
+
+func (a *Analyzer) analyze(ctx context.Context, userID uint, now time.Time) ([]models.MetricTrend, []models.Insight, error) {
+	since := now.Add(-historyWindow)
+
+	records, err := a.health.ListSince(ctx, userID, since)
+	if err != nil {
+		return nil, nil, err
+	}
+	weight := Series{Metric: models.MetricWeight, Unit: "kg"}
+	for _, r := range records {
+		weight.Points = append(weight.Points, Point{At: r.RecordDate, Value: r.WeightKg, Ref: fmt.Sprintf("health:%d", r.ID)})
+	}
+
+	readings, err := a.measurements.ListSince(ctx, userID, since)
+	if err != nil {
+		return nil, nil, err
+	}
+	var vitals []Series
+	byType := map[string]int{}
+	for _, m := range readings {
+		i, ok := byType[m.Type]
+		if !ok {
+			i = len(vitals)
+			byType[m.Type] = i
+			vitals = append(vitals, Series{Metric: m.Type, Unit: m.Unit})
+		}
+		// Blood pressure trends follow the systolic value
+		vitals[i].Points = append(vitals[i].Points, Point{At: m.MeasuredAt, Value: m.Value, Ref: fmt.Sprintf("measurement:%d", m.ID)})
+	}
+
+	target, err := a.weightTarget(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	trends, found := Analyze(userID, weight, vitals, target, now)
+	return trends, found, nil
+}
+
+// weightTarget returns the target of the newest open weight goal
+func (a *Analyzer) weightTarget(ctx context.Context, userID uint) (*float64, error) {
+	goals, err := a.goals.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range goals {
+		if g.Type == models.GoalTypeWeight && !g.IsCompleted && g.Target > 0 {
+			target := g.Target
+			return &target, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,147 @@
+package insights
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"health-tracker/models"
+	"health-tracker/repository"
+)
+
+// The fakes embed their repository interface and implement only what the analyzer calls
+
+type fakeHealth struct {
+	repository.HealthRepository
+	records []models.HealthData
+	since   time.Time
+}
+
+func (f *fakeHealth) ListSince(ctx context.Context, userID uint, since time.Time) ([]models.HealthData, error) {
+	f.since = since
+	var records []models.HealthData
+	for _, r := range f.records {
+		if r.UserID == userID && !r.RecordDate.Before(since) {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+type fakeMeasurements struct {
+	repository.MeasurementRepository
+	measurements []models.Measurement
+	err          error
+}
+
+func (f *fakeMeasurements) ListSince(ctx context.Context, userID uint, since time.Time) ([]models.Measurement, error) {
+	var rows []models.Measurement
+	for _, m := range f.measurements {
+		if m.UserID == userID && !m.MeasuredAt.Before(since) {
+			rows = append(rows, m)
+		}
+	}
+	return rows, f.err
+}
+
+type fakeGoals struct {
+	repository.GoalRepository
+	goals []models.Goal
+}
+
+func (f *fakeGoals) ListByUser(ctx context.Context, userID uint) ([]models.Goal, error) {
+	return f.goals, nil
+}
+
+type fakeInsights struct {
+	repository.InsightRepository
+	weeks  []string
+	synced []models.Insight
+}
+
+func (f *fakeInsights) Sync(ctx context.Context, userID uint, week string, insights []models.Insight) (int, error) {
+	f.weeks = append(f.weeks, week)
+	f.synced = insights
+	return len(insights), nil
+}
+
+func TestAnalyzer(t *testing.T) {
+	now := start.Add(20 * day)
+	health := &fakeHealth{}
+	measurements := &fakeMeasurements{}
+	for i := 0; i < 10; i++ {
+		at := start.Add(time.Duration(i) * 2 * day)
+		health.records = append(health.records, models.HealthData{ID: uint(i + 1), UserID: 1, WeightKg: 80 - 0.2*float64(i), RecordDate: at})
+		measurements.measurements = append(measurements.measurements, models.Measurement{ID: uint(i + 1), UserID: 1, Type: models.MeasurementHeartRate, Unit: "bpm", Value: []float64{60, 62}[i%2], MeasuredAt: at})
+	}
+	// Outside the history window and someone else's reading
+	health.records = append(health.records,
+		models.HealthData{ID: 90, UserID: 1, WeightKg: 120, RecordDate: now.Add(-200 * day)},
+		models.HealthData{ID: 91, UserID: 2, WeightKg: 50, RecordDate: start},
+	)
+	// A resting heart rate far off the baseline
+	measurements.measurements = append(measurements.measurements, models.Measurement{ID: 11, UserID: 1, Type: models.MeasurementHeartRate, Unit: "bpm", Value: 90, MeasuredAt: start.Add(19 * day)})
+	goals := &fakeGoals{goals: []models.Goal{
+		{UserID: 1, Type: models.GoalTypeWeight, Target: 60, IsCompleted: true},
+		{UserID: 1, Type: models.GoalTypeWeight, Target: 75},
+	}}
+	store := &fakeInsights{}
+	analyzer := NewAnalyzer(health, measurements, goals, store)
+
+	trends, err := analyzer.Trends(context.Background(), 1, now)
+	if err != nil {
+		t.Fatalf("Trends: %v", err)
+	}
+	if len(store.weeks) != 0 {
+		t.Errorf("Trends synced insights for weeks %v", store.weeks)
+	}
+	if !health.since.Equal(now.Add(-historyWindow)) {
+		t.Errorf("loaded since %v, want %v", health.since, now.Add(-historyWindow))
+	}
+	if len(trends) != 2 || trends[0].Metric != models.MetricWeight || trends[1].Metric != models.MeasurementHeartRate {
+		t.Fatalf("trends = %+v, want weight and heart rate", trends)
+	}
+	if trends[0].Readings != 10 {
+		t.Errorf("weight readings = %d, want 10", trends[0].Readings)
+	}
+	// The completed goal is ignored in favour of the open one
+	if p := trends[0].Projection; p == nil || p.Target == nil || *p.Target != 75 {
+		t.Errorf("projection = %+v, want target 75", p)
+	}
+
+	if _, err := analyzer.Refresh(context.Background(), 1, now); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if len(store.weeks) != 1 || store.weeks[0] != isoWeek(now) {
+		t.Errorf("synced weeks %v, want [%s]", store.weeks, isoWeek(now))
+	}
+	fingerprints := map[string]bool{}
+	for _, insight := range store.synced {
+		fingerprints[insight.Fingerprint] = true
+	}
+	for _, want := range []string{
+		"trend:weight:" + isoWeek(now),
+		"projection:weight:75:" + isoWeek(now),
+		"zscore:heart_rate:measurement:11",
+	} {
+		if !fingerprints[want] {
+			t.Errorf("synced insights %v are missing %s", fingerprints, want)
+		}
+	}
+	for fingerprint := range fingerprints {
+		if strings.Contains(fingerprint, "health:90") || strings.Contains(fingerprint, "health:91") {
+			t.Errorf("insight %s comes from a reading outside the analysis", fingerprint)
+		}
+	}
+
+	// A failing repository stops the refresh before anything is stored
+	measurements.err = errors.New("boom")
+	if _, err := analyzer.Refresh(context.Background(), 1, now); err == nil {
+		t.Error("Refresh succeeded with a failing repository")
+	}
+	if len(store.weeks) != 1 {
+		t.Errorf("synced %d times, want 1", len(store.weeks))
+	}
+}
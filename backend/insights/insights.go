@@ -0,0 +1,340 @@
+// Package insights finds trends and anomalies in weight and vital sign readings. It only
+// computes; handlers load the readings and store the insights it returns.
+package insights
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"health-tracker/models"
+)
+
+const (
+	day = 24 * time.Hour
+
+	// trendWindow is the span the weekly change and projection are fitted over
+	trendWindow = 30 * day
+	// baselineWindow is how far back readings count as normal for the z-score check
+	baselineWindow = 90 * day
+	// minBaseline is the fewest earlier readings a z-score is computed from
+	minBaseline = 5
+	// zScoreWarning and zScoreAlert are the bands outside which a reading is unusual
+	zScoreWarning = 2.5
+	zScoreAlert   = 3.5
+
+	// weightJumpKg within weightJumpWindow between two weigh-ins is flagged
+	weightJumpKg     = 3.0
+	weightJumpWindow = 7 * day
+
+	// stableRate is the weekly change, relative to the latest value, below which a metric is stable
+	stableRate = 0.0025
+	// maxProjection is how far ahead a goal date is still projected
+	maxProjection = 2 * 365 * day
+)
+
+// Point is one reading; Ref identifies its source row, e.g. "health:12"
+type Point struct {
+	At    time.Time
+	Value float64
+	Ref   string
+}
+
+// Series is the readings of one metric, oldest first
+type Series struct {
+	Metric string
+	Unit   string
+	Points []Point
+}
+
+// Analyze returns the trend of every series with readings and the insights they give
+// rise to. weightTarget is the user's open weight goal, if any.
+func Analyze(userID uint, weight Series, vitals []Series, weightTarget *float64, now time.Time) ([]models.MetricTrend, []models.Insight) {
+	var trends []models.MetricTrend
+	var found []models.Insight
+
+	for _, s := range append([]Series{weight}, vitals...) {
+		if len(s.Points) == 0 {
+			continue
+		}
+		trend := Trend(s)
+		jumped := map[string]bool{}
+		if s.Metric == models.MetricWeight {
+			trend.Projection = Project(trend, weightTarget)
+			if insight := projectionInsight(trend, now); insight != nil {
+				found = append(found, *insight)
+			}
+			var jumps []models.Insight
+			jumps, jumped = weightJumps(s)
+			found = append(found, jumps...)
+		}
+		if insight := trendInsight(trend, now); insight != nil {
+			found = append(found, *insight)
+		}
+		found = append(found, outliers(s, jumped)...)
+		trends = append(trends, trend)
+	}
+
+	for i := range found {
+		found[i].UserID = userID
+	}
+	return trends, found
+}
+
+// Trend computes rolling averages and the weekly rate of change, ending at the latest reading
+func Trend(s Series) models.MetricTrend {
+	latest := s.Points[len(s.Points)-1]
+	trend := models.MetricTrend{
+		Metric:    s.Metric,
+		Unit:      s.Unit,
+		Readings:  len(s.Points),
+		Latest:    latest.Value,
+		LatestAt:  latest.At,
+		Direction: "unknown",
+	}
+
+	trend.Avg7d = average(since(s.Points, latest.At.Add(-7*day)))
+	trend.Avg30d = average(since(s.Points, latest.At.Add(-trendWindow)))
+
+	window := since(s.Points, latest.At.Add(-trendWindow))
+	if slope, ok := regression(window); ok {
+		weekly := round2(slope * 7)
+		trend.WeeklyChange = &weekly
+		switch {
+		case math.Abs(weekly) < math.Abs(latest.Value)*stableRate:
+			trend.Direction = "stable"
+		case weekly > 0:
+			trend.Direction = "rising"
+		default:
+			trend.Direction = "falling"
+		}
+	}
+	return trend
+}
+
+// Project extrapolates the fitted line 4 weeks ahead and, given a target, to the day the
+// target is reached
+func Project(trend models.MetricTrend, target *float64) *models.Projection {
+	if trend.WeeklyChange == nil {
+		return nil
+	}
+	perDay := *trend.WeeklyChange / 7
+	projection := &models.Projection{In4Weeks: round2(trend.Latest + perDay*28), Target: target}
+	if target == nil || perDay == 0 {
+		return projection
+	}
+
+	// Goals don't say whether to lose or gain, so the target only counts as reached when the
+	// readings crossed it: the latest is on the other side of it from the 30-day average
+	if trend.Avg30d != nil && (*trend.Avg30d-*target)*(trend.Latest-*target) <= 0 {
+		projection.Reached = true
+		projection.GoalReachable = true
+		projection.TargetDate = trend.LatestAt.Format("2006-01-02")
+		return projection
+	}
+
+	days := (*target - trend.Latest) / perDay
+	if days >= 0 && time.Duration(days*float64(day)) <= maxProjection {
+		projection.GoalReachable = true
+		projection.TargetDate = trend.LatestAt.Add(time.Duration(days * float64(day))).Format("2006-01-02")
+	}
+	return projection
+}
+
+func trendInsight(trend models.MetricTrend, now time.Time) *models.Insight {
+	if trend.WeeklyChange == nil || trend.Direction == "stable" {
+		return nil
+	}
+	direction := "naik"
+	if trend.Direction == "falling" {
+		direction = "turun"
+	}
+	return &models.Insight{
+		Kind:        models.InsightTrend,
+		Metric:      trend.Metric,
+		Severity:    models.InsightInfo,
+		Title:       fmt.Sprintf("%s cenderung %s", metricLabel(trend.Metric), direction),
+		Message:     fmt.Sprintf("%s %s rata-rata %.2f %s per minggu dalam 30 hari terakhir.", metricLabel(trend.Metric), direction, math.Abs(*trend.WeeklyChange), trend.Unit),
+		Value:       *trend.WeeklyChange,
+		Fingerprint: fmt.Sprintf("trend:%s:%s", trend.Metric, isoWeek(now)),
+		ObservedAt:  trend.LatestAt,
+	}
+}
+
+func projectionInsight(trend models.MetricTrend, now time.Time) *models.Insight {
+	p := trend.Projection
+	if p == nil || p.Target == nil {
+		return nil
+	}
+	insight := &models.Insight{
+		Kind:        models.InsightProjection,
+		Metric:      trend.Metric,
+		Severity:    models.InsightInfo,
+		Value:       *p.Target,
+		Fingerprint: fmt.Sprintf("projection:%s:%g:%s", trend.Metric, *p.Target, isoWeek(now)),
+		ObservedAt:  trend.LatestAt,
+	}
+	switch {
+	case p.Reached:
+		insight.Title = "Target berat badan tercapai"
+		insight.Message = fmt.Sprintf("Selamat, berat badan Anda sudah mencapai target %g kg.", *p.Target)
+	case p.GoalReachable:
+		insight.Title = "Proyeksi target berat badan"
+		insight.Message = fmt.Sprintf("Dengan laju saat ini, berat badan Anda mencapai %g kg pada %s.", *p.Target, p.TargetDate)
+	default:
+		insight.Severity = models.InsightWarning
+		insight.Title = "Target berat badan belum terjangkau"
+		insight.Message = fmt.Sprintf("Dengan laju saat ini, target %g kg tidak tercapai dalam 2 tahun. Perkiraan 4 minggu lagi: %.1f kg.", *p.Target, p.In4Weeks)
+	}
+	return insight
+}
+
+// weightJumps flags consecutive weigh-ins that differ by weightJumpKg or more within a
+// week. It also returns the refs of the flagged readings.
+func weightJumps(s Series) ([]models.Insight, map[string]bool) {
+	var found []models.Insight
+	refs := map[string]bool{}
+	for i := 1; i < len(s.Points); i++ {
+		prev, cur := s.Points[i-1], s.Points[i]
+		change := cur.Value - prev.Value
+		if math.Abs(change) < weightJumpKg || cur.At.Sub(prev.At) > weightJumpWindow {
+			continue
+		}
+		found = append(found, models.Insight{
+			Kind:        models.InsightAnomaly,
+			Metric:      s.Metric,
+			Severity:    models.InsightWarning,
+			Title:       "Perubahan berat badan mendadak",
+			Message:     fmt.Sprintf("Berat badan berubah %+.1f kg dalam %s. Pastikan data benar atau konsultasikan dengan dokter.", change, elapsed(cur.At.Sub(prev.At))),
+			Value:       round2(change),
+			Fingerprint: "jump:" + s.Metric + ":" + cur.Ref,
+			ObservedAt:  cur.At,
+		})
+		refs[cur.Ref] = true
+	}
+	return found, refs
+}
+
+// outliers flags readings whose z-score against the previous 90 days falls outside the
+// band, except those in skip that were already reported
+func outliers(s Series, skip map[string]bool) []models.Insight {
+	var found []models.Insight
+	for i, cur := range s.Points {
+		if skip[cur.Ref] {
+			continue
+		}
+		baseline := since(s.Points[:i], cur.At.Add(-baselineWindow))
+		if len(baseline) < minBaseline {
+			continue
+		}
+		mean, sd := meanStdDev(baseline)
+		if sd == 0 {
+			continue
+		}
+		z := (cur.Value - mean) / sd
+		if math.Abs(z) < zScoreWarning {
+			continue
+		}
+
+		severity := models.InsightWarning
+		if math.Abs(z) >= zScoreAlert {
+			severity = models.InsightAlert
+		}
+		side := "di atas"
+		if z < 0 {
+			side = "di bawah"
+		}
+		found = append(found, models.Insight{
+			Kind:     models.InsightAnomaly,
+			Metric:   s.Metric,
+			Severity: severity,
+			Title:    fmt.Sprintf("%s tidak biasa", metricLabel(s.Metric)),
+			Message: fmt.Sprintf("Nilai %g %s jauh %s rata-rata 90 hari Anda (%.1f %s, z = %.1f).",
+				cur.Value, s.Unit, side, mean, s.Unit, z),
+			Value:       round2(z),
+			Fingerprint: "zscore:" + s.Metric + ":" + cur.Ref,
+			ObservedAt:  cur.At,
+		})
+	}
+	return found
+}
+
+// regression returns the least-squares slope in units per day, if the points span at least 3 days
+func regression(points []Point) (float64, bool) {
+	if len(points) < 3 || points[len(points)-1].At.Sub(points[0].At) < 3*day {
+		return 0, false
+	}
+	origin := points[0].At
+	var sumX, sumY, sumXY, sumXX float64
+	for _, p := range points {
+		x := p.At.Sub(origin).Hours() / 24
+		sumX += x
+		sumY += p.Value
+		sumXY += x * p.Value
+		sumXX += x * x
+	}
+	n := float64(len(points))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, false
+	}
+	return (n*sumXY - sumX*sumY) / denominator, true
+}
+
+func since(points []Point, from time.Time) []Point {
+	for i, p := range points {
+		if !p.At.Before(from) {
+			return points[i:]
+		}
+	}
+	return nil
+}
+
+func average(points []Point) *float64 {
+	if len(points) == 0 {
+		return nil
+	}
+	mean, _ := meanStdDev(points)
+	mean = round2(mean)
+	return &mean
+}
+
+func meanStdDev(points []Point) (float64, float64) {
+	var sum float64
+	for _, p := range points {
+		sum += p.Value
+	}
+	mean := sum / float64(len(points))
+
+	var squares float64
+	for _, p := range points {
+		squares += (p.Value - mean) * (p.Value - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(points)))
+}
+
+func metricLabel(metric string) string {
+	if metric == models.MetricWeight {
+		return "Berat badan"
+	}
+	if spec, ok := models.FindMeasurementSpec(metric); ok {
+		return spec.Label
+	}
+	return metric
+}
+
+func elapsed(d time.Duration) string {
+	if d < day {
+		return fmt.Sprintf("%.0f jam", d.Hours())
+	}
+	return fmt.Sprintf("%.0f hari", d.Hours()/24)
+}
+
+func isoWeek(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
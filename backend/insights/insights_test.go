@@ -0,0 +1,313 @@
+package insights
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"health-tracker/models"
+)
+
+var start = time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC)
+
+// series returns a weight series with one reading per day starting at start
+func series(values ...float64) Series {
+	s := Series{Metric: models.MetricWeight, Unit: "kg"}
+	for i, v := range values {
+		s.Points = append(s.Points, Point{At: start.Add(time.Duration(i) * day), Value: v, Ref: fmt.Sprintf("health:%d", i+1)})
+	}
+	return s
+}
+
+func ptr(v float64) *float64 { return &v }
+
+func TestTrend(t *testing.T) {
+	tests := []struct {
+		name      string
+		series    Series
+		weekly    *float64
+		direction string
+		avg7d     float64
+	}{
+		{"single reading", series(70), nil, "unknown", 70},
+		{"two readings", series(70, 71), nil, "unknown", 70.5},
+		{"readings within 3 days", series(70, 71, 72), nil, "unknown", 71},
+		{"flat", series(70, 70, 70, 70, 70, 70), ptr(0), "stable", 70},
+		{"falling", series(71, 70.9, 70.8, 70.7, 70.6, 70.5, 70.4, 70.3), ptr(-0.7), "falling", 70.65},
+		{"rising", series(70, 70.5, 71, 71.5, 72), ptr(3.5), "rising", 71},
+		{"below the stable rate", series(70, 70.01, 70.02, 70.03), ptr(0.07), "stable", 70.01},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trend := Trend(tt.series)
+			if trend.Direction != tt.direction {
+				t.Errorf("direction = %q, want %q", trend.Direction, tt.direction)
+			}
+			switch {
+			case tt.weekly == nil && trend.WeeklyChange != nil:
+				t.Errorf("weekly change = %v, want none", *trend.WeeklyChange)
+			case tt.weekly != nil && (trend.WeeklyChange == nil || *trend.WeeklyChange != *tt.weekly):
+				t.Errorf("weekly change = %v, want %v", trend.WeeklyChange, *tt.weekly)
+			}
+			if trend.Avg7d == nil || *trend.Avg7d != tt.avg7d {
+				t.Errorf("avg 7d = %v, want %v", *trend.Avg7d, tt.avg7d)
+			}
+			if trend.Readings != len(tt.series.Points) {
+				t.Errorf("readings = %d, want %d", trend.Readings, len(tt.series.Points))
+			}
+		})
+	}
+}
+
+func TestTrendAveragesOnlyRecentReadings(t *testing.T) {
+	s := Series{Metric: models.MetricWeight, Points: []Point{
+		{At: start, Value: 90},
+		{At: start.Add(20 * day), Value: 80},
+		{At: start.Add(40 * day), Value: 70},
+	}}
+	trend := Trend(s)
+	if *trend.Avg7d != 70 || *trend.Avg30d != 75 {
+		t.Errorf("avg 7d = %v, avg 30d = %v; want 70 and 75", *trend.Avg7d, *trend.Avg30d)
+	}
+}
+
+func TestRegression(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []Point
+		slope  float64
+		ok     bool
+	}{
+		{"no points", nil, 0, false},
+		{"one point", series(70).Points, 0, false},
+		{"two points", series(70, 72).Points, 0, false},
+		{"three points within 3 days", series(70, 71, 72).Points, 0, false},
+		{"flat", series(70, 70, 70, 70).Points, 0, true},
+		{"line", series(70, 70.5, 71, 71.5).Points, 0.5, true},
+		// All readings at the same moment leave no spread to fit a slope to
+		{"same time", []Point{{At: start, Value: 1}, {At: start, Value: 2}, {At: start, Value: 3}}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slope, ok := regression(tt.points)
+			if ok != tt.ok || math.Abs(slope-tt.slope) > 1e-9 {
+				t.Errorf("regression = %v, %v; want %v, %v", slope, ok, tt.slope, tt.ok)
+			}
+		})
+	}
+}
+
+func TestProject(t *testing.T) {
+	latestAt := start
+	trend := func(latest, avg30d, weekly float64) models.MetricTrend {
+		return models.MetricTrend{Latest: latest, LatestAt: latestAt, Avg30d: &avg30d, WeeklyChange: &weekly}
+	}
+
+	tests := []struct {
+		name       string
+		trend      models.MetricTrend
+		target     *float64
+		want       *models.Projection
+		wantTarget bool
+	}{
+		{
+			name:  "no weekly change",
+			trend: models.MetricTrend{Latest: 80, LatestAt: latestAt},
+			want:  nil,
+		},
+		{
+			name:  "no target",
+			trend: trend(80, 81, -0.7),
+			want:  &models.Projection{In4Weeks: 77.2},
+		},
+		{
+			name:   "flat",
+			trend:  trend(80, 80, 0),
+			target: ptr(75),
+			want:   &models.Projection{In4Weeks: 80},
+		},
+		{
+			name:   "moving towards the target",
+			trend:  trend(80, 81, -0.7),
+			target: ptr(75),
+			want:   &models.Projection{In4Weeks: 77.2, GoalReachable: true, TargetDate: "2026-02-24"},
+		},
+		{
+			name:   "moving away from the target",
+			trend:  trend(80, 79, 0.7),
+			target: ptr(75),
+			want:   &models.Projection{In4Weeks: 82.8},
+		},
+		{
+			name:   "target more than 2 years away",
+			trend:  trend(80, 80.1, -0.007),
+			target: ptr(70),
+			want:   &models.Projection{In4Weeks: 79.97},
+		},
+		{
+			name:   "target passed while losing",
+			trend:  trend(74, 76, -0.7),
+			target: ptr(75),
+			want:   &models.Projection{In4Weeks: 71.2, GoalReachable: true, Reached: true, TargetDate: "2026-01-05"},
+		},
+		{
+			name:   "target passed while gaining",
+			trend:  trend(61, 58, 0.7),
+			target: ptr(60),
+			want:   &models.Projection{In4Weeks: 63.8, GoalReachable: true, Reached: true, TargetDate: "2026-01-05"},
+		},
+		{
+			// Above a lower target and still rising: the readings never crossed it
+			name:   "above the target and rising",
+			trend:  trend(82, 80, 0.7),
+			target: ptr(75),
+			want:   &models.Projection{In4Weeks: 84.8},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Project(tt.trend, tt.target)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("projection = %+v, want none", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("projection = nil")
+			}
+			if got.Target != tt.target {
+				t.Errorf("target = %v, want %v", got.Target, tt.target)
+			}
+			got.Target = nil
+			if *got != *tt.want {
+				t.Errorf("projection = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestWeightJumps(t *testing.T) {
+	at := func(days float64) time.Time { return start.Add(time.Duration(days * float64(day))) }
+	tests := []struct {
+		name   string
+		points []Point
+		want   []float64 // flagged changes
+	}{
+		{"below the threshold", []Point{{At: at(0), Value: 70}, {At: at(1), Value: 72.99}}, nil},
+		{"at the threshold", []Point{{At: at(0), Value: 70}, {At: at(1), Value: 73}}, []float64{3}},
+		{"drop at the threshold", []Point{{At: at(0), Value: 73}, {At: at(1), Value: 70}}, []float64{-3}},
+		{"exactly a week apart", []Point{{At: at(0), Value: 70}, {At: at(7), Value: 74}}, []float64{4}},
+		{"more than a week apart", []Point{{At: at(0), Value: 70}, {At: at(7.01), Value: 74}}, nil},
+		{"only consecutive readings count", []Point{{At: at(0), Value: 70}, {At: at(1), Value: 71.5}, {At: at(2), Value: 73}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Series{Metric: models.MetricWeight, Unit: "kg", Points: tt.points}
+			for i := range s.Points {
+				s.Points[i].Ref = fmt.Sprintf("health:%d", i+1)
+			}
+			found, refs := weightJumps(s)
+			if len(found) != len(tt.want) {
+				t.Fatalf("found %d jumps, want %d: %+v", len(found), len(tt.want), found)
+			}
+			for i, insight := range found {
+				if insight.Value != tt.want[i] || insight.Kind != models.InsightAnomaly {
+					t.Errorf("jump %d = %+v, want change %v", i, insight, tt.want[i])
+				}
+				if !refs[insight.Fingerprint[len("jump:weight:"):]] {
+					t.Errorf("jump %d ref missing from %v", i, refs)
+				}
+			}
+		})
+	}
+}
+
+func TestOutliers(t *testing.T) {
+	// The baseline has mean 10 and standard deviation 1, so the last value is its z-score
+	baseline := []float64{9, 11, 9, 11, 9, 11}
+	tests := []struct {
+		name     string
+		last     float64
+		severity string // empty when the reading is not flagged
+	}{
+		{"inside the band", 12.49, ""},
+		{"at the warning cutoff", 12.5, models.InsightWarning},
+		{"below at the warning cutoff", 7.5, models.InsightWarning},
+		{"just below the alert cutoff", 13.49, models.InsightWarning},
+		{"at the alert cutoff", 13.5, models.InsightAlert},
+		{"below at the alert cutoff", 6.5, models.InsightAlert},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := series(append(append([]float64{}, baseline...), tt.last)...)
+			s.Metric, s.Unit = models.MeasurementHeartRate, "bpm"
+
+			found := outliers(s, nil)
+			if tt.severity == "" {
+				if len(found) != 0 {
+					t.Errorf("flagged %+v, want nothing", found)
+				}
+				return
+			}
+			if len(found) != 1 {
+				t.Fatalf("flagged %d readings, want 1: %+v", len(found), found)
+			}
+			if found[0].Severity != tt.severity || found[0].Fingerprint != "zscore:heart_rate:health:7" {
+				t.Errorf("insight = %+v, want %s on the last reading", found[0], tt.severity)
+			}
+		})
+	}
+}
+
+func TestOutliersSkipsFlatAndShortBaselines(t *testing.T) {
+	// A flat baseline has no deviation to divide by
+	if found := outliers(series(70, 70, 70, 70, 70, 70, 90), nil); len(found) != 0 {
+		t.Errorf("flat baseline flagged %+v", found)
+	}
+	// Fewer than minBaseline earlier readings
+	if found := outliers(series(9, 11, 9, 11, 20), nil); len(found) != 0 {
+		t.Errorf("short baseline flagged %+v", found)
+	}
+	// Readings already reported as a jump are not reported twice
+	s := series(9, 11, 9, 11, 9, 11, 20)
+	if found := outliers(s, map[string]bool{"health:7": true}); len(found) != 0 {
+		t.Errorf("skipped reading flagged %+v", found)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	now := start.Add(10 * day)
+
+	if trends, found := Analyze(1, Series{Metric: models.MetricWeight}, nil, nil, now); trends != nil || found != nil {
+		t.Errorf("no readings: trends %+v, insights %+v", trends, found)
+	}
+
+	trends, found := Analyze(1, series(70), nil, ptr(65), now)
+	if len(trends) != 1 || trends[0].Direction != "unknown" || trends[0].Projection != nil || len(found) != 0 {
+		t.Errorf("single reading: trends %+v, insights %+v", trends, found)
+	}
+
+	flat := series(70, 70, 70, 70, 70, 70, 70, 70)
+	trends, found = Analyze(1, flat, nil, nil, now)
+	if len(trends) != 1 || trends[0].Direction != "stable" || len(found) != 0 {
+		t.Errorf("flat series: trends %+v, insights %+v", trends, found)
+	}
+
+	losing := series(80, 79.5, 79, 78.5, 78, 77.5)
+	trends, found = Analyze(7, losing, nil, ptr(75), now)
+	kinds := map[string]bool{}
+	for _, insight := range found {
+		kinds[insight.Kind] = true
+		if insight.UserID != 7 {
+			t.Errorf("insight %q belongs to user %d, want 7", insight.Fingerprint, insight.UserID)
+		}
+	}
+	if !kinds[models.InsightTrend] || !kinds[models.InsightProjection] || kinds[models.InsightAnomaly] {
+		t.Errorf("losing towards a goal: insights %+v, want a trend and a projection", found)
+	}
+	if p := trends[0].Projection; p == nil || !p.GoalReachable || p.Reached {
+		t.Errorf("projection = %+v, want reachable but not reached", p)
+	}
+}
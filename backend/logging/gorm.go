@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// slowQueryThreshold matches GORM's own default
+const slowQueryThreshold = 200 * time.Millisecond
+
+// GormLogger sends GORM output to slog so SQL logs carry the request ID and user ID.
+// Failed statements are logged at error level and slow ones at warn; every other
+// statement is logged at debug level and only when the GORM level is Info.
+type GormLogger struct {
+	level logger.LogLevel
+}
+
+// NewGormLogger returns a GORM logger at the given GORM log level
+func NewGormLogger(level logger.LogLevel) *GormLogger {
+	return &GormLogger{level: level}
+}
+
+// LogMode implements logger.Interface
+func (l *GormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &GormLogger{level: level}
+}
+
+// Info implements logger.Interface
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Info {
+		slog.InfoContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Warn implements logger.Interface
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Warn {
+		slog.WarnContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Error implements logger.Interface
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Error {
+		slog.ErrorContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace implements logger.Interface and is called once per statement
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	attrs := func() []any {
+		sql, rows := fc()
+		return []any{
+			slog.String("sql", sql),
+			slog.Int64("rows", rows),
+			slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+		}
+	}
+
+	switch {
+	case err != nil && l.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		slog.ErrorContext(ctx, "database query failed", append(attrs(), slog.String("error", err.Error()))...)
+	case elapsed > slowQueryThreshold && l.level >= logger.Warn:
+		slog.WarnContext(ctx, "slow database query", attrs()...)
+	case l.level >= logger.Info:
+		slog.DebugContext(ctx, "database query", attrs()...)
+	}
+}
@@ -0,0 +1,102 @@
+// Package logging configures the structured slog logger and carries per-request
+// fields such as the request ID through context.Context.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Init installs the process-wide slog logger. Format is "json" (default) or "text".
+// The standard library log package is routed through the same handler.
+func Init(level, format string) {
+	slog.SetDefault(slog.New(NewHandler(os.Stdout, ParseLevel(level), format)))
+}
+
+// NewHandler builds a handler that adds request fields from the context to every record
+func NewHandler(w io.Writer, level slog.Level, format string) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var base slog.Handler
+	if strings.ToLower(format) == "text" {
+		base = slog.NewTextHandler(w, opts)
+	} else {
+		base = slog.NewJSONHandler(w, opts)
+	}
+	return &contextHandler{Handler: base}
+}
+
+// ParseLevel maps debug, info, warn and error to slog levels, defaulting to info
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Fatal logs at error level and exits, replacing log.Fatal
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// RequestInfo holds the fields attached to every log line written for a request.
+// UserID is filled in by the auth middleware once the token has been validated.
+type RequestInfo struct {
+	ID     string
+	UserID uint
+}
+
+type requestInfoKey struct{}
+
+// NewContext returns a copy of ctx carrying info
+func NewContext(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// FromContext returns the request fields stored in ctx, or nil outside a request
+func FromContext(ctx context.Context) *RequestInfo {
+	if ctx == nil {
+		return nil
+	}
+	info, _ := ctx.Value(requestInfoKey{}).(*RequestInfo)
+	return info
+}
+
+// contextHandler decorates records logged with a request context with request_id,
+// user_id and, when tracing is enabled, the trace and span IDs
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if info := FromContext(ctx); info != nil {
+		r.AddAttrs(slog.String("request_id", info.ID))
+		if info.UserID != 0 {
+			r.AddAttrs(slog.Uint64("user_id", uint64(info.UserID)))
+		}
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
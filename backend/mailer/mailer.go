@@ -0,0 +1,121 @@
+package mailer
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"health-tracker/config"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string    `json:"to"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// Mailer delivers outgoing email
+type Mailer interface {
+	Send(msg Message) error
+}
+
+var Default Mailer
+
+// InitMailer selects the mailer implementation from configuration
+func InitMailer() {
+	switch config.AppConfig.MailDriver {
+	case "smtp":
+		Default = &SMTPMailer{
+			Host:     config.AppConfig.SMTPHost,
+			Port:     config.AppConfig.SMTPPort,
+			Username: config.AppConfig.SMTPUsername,
+			Password: config.AppConfig.SMTPPassword,
+			From:     config.AppConfig.MailFrom,
+		}
+	case "file":
+		Default = &FileMailer{Dir: config.AppConfig.MailFileDir}
+	default:
+		Default = &LogMailer{}
+	}
+	slog.Info("mailer initialized", "driver", config.AppConfig.MailDriver)
+}
+
+// Send delivers a message through the default mailer
+func Send(msg Message) error {
+	if Default == nil {
+		return fmt.Errorf("mailer not initialized")
+	}
+	msg.SentAt = time.Now()
+	return Default.Send(msg)
+}
+
+// LogMailer records that a message was sent without delivering it. Only the recipient and
+// subject are logged: bodies carry password reset links and other secrets.
+type LogMailer struct{}
+
+func (m *LogMailer) Send(msg Message) error {
+	slog.Info("mail", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
+
+// FileMailer stores each message as a JSON file, useful as a local stand-in for SMTP
+type FileMailer struct {
+	Dir string
+}
+
+func (m *FileMailer) Send(msg Message) error {
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(msg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%s.json", msg.SentAt.UnixNano(), sanitizeFileName(msg.To))
+	return os.WriteFile(filepath.Join(m.Dir, name), data, 0o600)
+}
+
+// SMTPMailer sends messages through an SMTP server
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (m *SMTPMailer) Send(msg Message) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	body := "From: " + m.From + "\r\n" +
+		"To: " + msg.To + "\r\n" +
+		"Subject: " + msg.Subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\n" +
+		msg.Body
+
+	return smtp.SendMail(m.Host+":"+m.Port, auth, m.From, []string{msg.To}, []byte(body))
+}
+
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
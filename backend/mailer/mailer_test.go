@@ -0,0 +1,115 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileMailerWritesMessage(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m := &FileMailer{Dir: dir}
+	msg := Message{To: "ana+test@example.com", Subject: "Reset", Body: "token abc", SentAt: time.Unix(1700000000, 0)}
+
+	if err := m.Send(msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d files, want 1", len(entries))
+	}
+	name := entries[0].Name()
+	if want := "1700000000000000000-ana_test_example.com.json"; name != want {
+		t.Errorf("file name = %q, want %q", name, want)
+	}
+
+	info, err := entries[0].Info()
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("file mode = %o, want 600", perm)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	var got Message
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.To != msg.To || got.Subject != msg.Subject || got.Body != msg.Body || !got.SentAt.Equal(msg.SentAt) {
+		t.Errorf("stored message = %+v, want %+v", got, msg)
+	}
+}
+
+func TestLogMailerOmitsBody(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	err := (&LogMailer{}).Send(Message{
+		To:      "ana@example.com",
+		Subject: "Reset password",
+		Body:    "https://app.example.com/reset-password?token=secret-token",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"ana@example.com", "Reset password"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log %q does not contain %q", out, want)
+		}
+	}
+	if strings.Contains(out, "secret-token") {
+		t.Errorf("log leaks the message body: %q", out)
+	}
+}
+
+func TestSendRequiresInit(t *testing.T) {
+	previous := Default
+	Default = nil
+	t.Cleanup(func() { Default = previous })
+
+	if err := Send(Message{To: "ana@example.com"}); err == nil {
+		t.Fatal("Send without a mailer succeeded")
+	}
+}
+
+func TestSendStampsSentAt(t *testing.T) {
+	dir := t.TempDir()
+	previous := Default
+	Default = &FileMailer{Dir: dir}
+	t.Cleanup(func() { Default = previous })
+
+	before := time.Now()
+	if err := Send(Message{To: "ana@example.com", Subject: "Hi"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("got %d files, want 1", len(entries))
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	var got Message
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.SentAt.Before(before) {
+		t.Errorf("SentAt = %v, want at or after %v", got.SentAt, before)
+	}
+}
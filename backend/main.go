@@ -1,30 +1,95 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"health-tracker/config"
 	"health-tracker/database"
+	"health-tracker/handlers"
+	"health-tracker/logging"
+	"health-tracker/mailer"
+	"health-tracker/middleware"
+	"health-tracker/ratelimit"
+	"health-tracker/repository"
 	"health-tracker/routes"
-	"log"
+	"health-tracker/tracing"
+	"health-tracker/utils"
+	"log/slog"
+	"net/http"
 	"os" // <--- INI TAMBAHAN PENTING
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 func main() {
 	// Load configuration
 	config.LoadConfig()
+	logging.Init(config.AppConfig.LogLevel, config.AppConfig.LogFormat)
+
+	// `./main migrate up|down [n]|status|to <version>` manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunMigrateCommand(os.Args[2:]); err != nil {
+			logging.Fatal("migration failed", "error", err)
+		}
+		return
+	}
+
+	// `./main openapi [--check]` prints the API document, or fails when a route is undocumented
+	if len(os.Args) > 1 && os.Args[1] == "openapi" {
+		// The document goes to stdout, so keep log lines off it
+		slog.SetDefault(slog.New(logging.NewHandler(os.Stderr, logging.ParseLevel(config.AppConfig.LogLevel), config.AppConfig.LogFormat)))
+		gin.SetMode(gin.ReleaseMode)
+		if err := routes.RunOpenAPICommand(os.Args[2:]); err != nil {
+			logging.Fatal("openapi check failed", "error", err)
+		}
+		return
+	}
+
+	// Tracing; spans are flushed after the server has drained
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Options{
+		Exporter:    config.AppConfig.TracingExporter,
+		Endpoint:    config.AppConfig.OTLPEndpoint,
+		ServiceName: config.AppConfig.TracingServiceName,
+		SampleRatio: config.AppConfig.TracingSampleRatio,
+	})
+	if err != nil {
+		logging.Fatal("failed to initialize tracing", "error", err)
+	}
 
 	// Set Gin mode
 	gin.SetMode(config.AppConfig.GinMode)
+	utils.ConfigureValidator()
+	// Reject JSON fields an endpoint doesn't know instead of silently dropping them
+	binding.EnableDecoderDisallowUnknownFields = config.AppConfig.StrictJSON
 
-	// Initialize database (Sekarang pakai Neon Postgres)
+	// Initialize database: SQLite or PostgreSQL, chosen by DATABASE_DRIVER
 	database.InitDatabase()
 
-	// Create Gin router
-	r := gin.Default()
+	// Initialize mailer (log, file or smtp)
+	mailer.InitMailer()
+
+	// Create Gin router; logging and recovery middleware are added in SetupRoutes
+	r := gin.New()
+	if err := middleware.ConfigureProxies(r, middleware.ProxyOptions{
+		TrustedProxies:  config.AppConfig.TrustedProxies,
+		TrustedPlatform: config.AppConfig.TrustedPlatform,
+	}); err != nil {
+		logging.Fatal("invalid TRUSTED_PROXIES", "error", err)
+	}
+
+	// Rate limiter state: in memory, or Redis when several instances share limits
+	limiter, err := ratelimit.NewStore(context.Background(), config.AppConfig.RateLimitStore, config.AppConfig.RedisURL)
+	if err != nil {
+		logging.Fatal("failed to initialize rate limiter", "error", err)
+	}
 
 	// Setup routes
-	routes.SetupRoutes(r)
+	repos := repository.New(database.DB)
+	routes.SetupRoutes(r, repos, limiter)
 
 	// ========================================================
 	// PENYESUAIAN UNTUK RENDER.COM
@@ -39,10 +104,53 @@ func main() {
 		}
 	}
 
-	log.Printf("🚀 Health Tracker API starting on port %s", port)
+	srv := &http.Server{
+		Addr:              "0.0.0.0:" + port, // Wajib 0.0.0.0 untuk Render
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Render sends SIGTERM before replacing the container
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Background export archives are deleted once their download links expire
+	go handlers.RunExportCleanup(ctx, repos.Exports)
+
+	go func() {
+		slog.Info("server starting", "port", port, "gin_mode", config.AppConfig.GinMode)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Fatal("failed to start server", "error", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	// Stop accepting connections and wait for in-flight requests up to the drain timeout
+	timeout := time.Duration(config.AppConfig.ShutdownTimeoutSeconds) * time.Second
+	slog.Info("shutting down, draining in-flight requests", "timeout", timeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("forced shutdown before requests drained", "timeout", timeout.String(), "error", err)
+	}
+
+	// Emails and export jobs started by requests finish within the same drain timeout
+	if err := handlers.WaitBackground(shutdownCtx); err != nil {
+		slog.Error("background work still running at shutdown", "timeout", timeout.String(), "error", err)
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
+
+	if err := limiter.Close(); err != nil {
+		slog.Error("failed to close rate limiter store", "error", err)
+	}
 
-	// Jalankan server di 0.0.0.0 (Wajib untuk Render)
-	if err := r.Run("0.0.0.0:" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	if err := database.Close(); err != nil {
+		slog.Error("failed to close database", "error", err)
 	}
+	slog.Info("server stopped")
 }
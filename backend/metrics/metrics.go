@@ -0,0 +1,119 @@
+// Package metrics holds the Prometheus collectors exposed on /metrics
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "health_tracker"
+
+// Registry holds every collector served on /metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts finished requests by Gin route template
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes request latency by Gin route template
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route template.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// RateLimitRejections counts requests refused by RateLimitMiddleware
+	RateLimitRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Requests rejected by the rate limiter, by limit group and route template.",
+	}, []string{"group", "route"})
+
+	// DBQueryDuration observes GORM statement latency
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database statement latency by operation and table.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"operation", "table"})
+
+	// DBQueryErrors counts failed GORM statements, not counting record-not-found
+	DBQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "Failed database statements by operation and table.",
+	}, []string{"operation", "table"})
+
+	// UserRegistrations counts new accounts
+	UserRegistrations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "user_registrations_total",
+		Help:      "Accounts registered.",
+	})
+
+	// SymptomsLogged counts symptom entries, including each entry of a batch
+	SymptomsLogged = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "symptoms_logged_total",
+		Help:      "Symptom entries logged.",
+	})
+
+	// MeasurementsRecorded counts vital sign readings by type
+	MeasurementsRecorded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "measurements_recorded_total",
+		Help:      "Vital sign readings recorded.",
+	}, []string{"type"})
+
+	// InsightsGenerated counts new trend, projection and anomaly insights
+	InsightsGenerated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "insights_generated_total",
+		Help:      "Insights stored for users.",
+	})
+
+	// WaterGlassesAdded counts glasses added in the water tracker
+	WaterGlassesAdded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "water_glasses_added_total",
+		Help:      "Glasses of water added.",
+	})
+
+	// GoalsCompleted counts goals that moved to completed
+	GoalsCompleted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "goals_completed_total",
+		Help:      "Goals marked as completed.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		RateLimitRejections,
+		DBQueryDuration,
+		DBQueryErrors,
+		UserRegistrations,
+		SymptomsLogged,
+		MeasurementsRecorded,
+		InsightsGenerated,
+		WaterGlassesAdded,
+		GoalsCompleted,
+	)
+}
+
+// Handler serves the registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
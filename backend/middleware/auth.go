@@ -4,12 +4,16 @@ import (
 	"net/http"
 	"strings"
 
+	"health-tracker/logging"
+	"health-tracker/models"
+	"health-tracker/repository"
 	"health-tracker/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware validates the bearer token and checks that its session is still active
+func AuthMiddleware(sessions repository.SessionRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 
@@ -36,14 +40,46 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Reject tokens whose session has been revoked or has expired
+		session, err := sessions.FindByID(c.Request.Context(), claims.SessionID, claims.UserID)
+		if err != nil || !session.IsActive() {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Session has been revoked")
+			c.Abort()
+			return
+		}
+
+		// Tag the request's log lines with the user
+		if info := logging.FromContext(c.Request.Context()); info != nil {
+			info.UserID = claims.UserID
+		}
+
 		// Set user info in context
 		c.Set("userID", claims.UserID)
 		c.Set("userEmail", claims.Email)
+		c.Set("sessionID", claims.SessionID)
+		c.Set("userRole", claims.Role)
 
 		c.Next()
 	}
 }
 
+// RequireRole allows the request through only when the authenticated user has one of the roles.
+// It must run after AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := GetUserRole(c)
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		utils.ErrorResponse(c, http.StatusForbidden, "You don't have permission to access this resource")
+		c.Abort()
+	}
+}
+
 func GetUserID(c *gin.Context) uint {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -59,3 +95,11 @@ func GetUserEmail(c *gin.Context) string {
 	}
 	return email.(string)
 }
+
+func GetUserRole(c *gin.Context) string {
+	role := c.GetString("userRole")
+	if role == "" {
+		return models.RoleUser
+	}
+	return role
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"health-tracker/config"
+	"health-tracker/models"
+	"health-tracker/repository"
+	"health-tracker/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeSessions struct {
+	repository.SessionRepository
+	sessions map[uint]models.Session
+}
+
+func (f *fakeSessions) FindByID(ctx context.Context, id, userID uint) (*models.Session, error) {
+	session, ok := f.sessions[id]
+	if !ok || session.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &session, nil
+}
+
+func TestAuthMiddlewareChecksSession(t *testing.T) {
+	config.LoadConfig()
+	gin.SetMode(gin.TestMode)
+
+	revoked := time.Now()
+	sessions := &fakeSessions{sessions: map[uint]models.Session{
+		1: {ID: 1, UserID: 7, ExpiresAt: time.Now().Add(time.Hour)},
+		2: {ID: 2, UserID: 7, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revoked},
+		3: {ID: 3, UserID: 7, ExpiresAt: time.Now().Add(-time.Minute)},
+	}}
+	r := gin.New()
+	r.GET("/me", AuthMiddleware(sessions), func(c *gin.Context) {
+		if c.GetUint("userID") != 7 || c.GetUint("sessionID") != 1 || GetUserRole(c) != models.RoleAdmin {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	token := func(userID, sessionID uint) string {
+		t.Helper()
+		token, err := utils.GenerateToken(userID, "ana@example.com", models.RoleAdmin, sessionID)
+		if err != nil {
+			t.Fatalf("GenerateToken: %v", err)
+		}
+		return "Bearer " + token
+	}
+	challenge, err := utils.GenerateChallengeToken(7, "ana@example.com", "challenge-id")
+	if err != nil {
+		t.Fatalf("GenerateChallengeToken: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"active session", token(7, 1), http.StatusNoContent},
+		{"revoked session", token(7, 2), http.StatusUnauthorized},
+		{"expired session", token(7, 3), http.StatusUnauthorized},
+		{"unknown session", token(7, 9), http.StatusUnauthorized},
+		{"session of another user", token(8, 1), http.StatusUnauthorized},
+		{"2FA challenge token", "Bearer " + challenge, http.StatusUnauthorized},
+		{"no header", "", http.StatusUnauthorized},
+		{"not a bearer token", "Basic abc", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("GET /me = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"health-tracker/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimitMiddleware caps the request body at limit bytes. Requests announcing a larger
+// Content-Length are rejected with 413 before the handler runs; chunked bodies fail while
+// being read. Nested limits can only tighten an outer one, so routes that need more room
+// than their group must be registered outside it.
+func BodyLimitMiddleware(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > limit {
+			utils.PayloadTooLargeResponse(c, limit)
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
@@ -1,34 +1,94 @@
 package middleware
 
 import (
-	"os"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
-func CORSMiddleware() gin.HandlerFunc {
-	// Get allowed origins from environment or use defaults
-	allowedOrigins := []string{
-		"http://localhost:5173",
-		"http://localhost:5174",
-		"http://localhost:3000",
-		"http://127.0.0.1:5173",
-		"https://sleeps-amendments-upgrades-fits.trycloudflare.com",
-		"https://satisfied-kent-hat-alignment.trycloudflare.com",
-	}
+// CORSOptions controls which browser origins may call the API
+type CORSOptions struct {
+	// AllowedOrigins holds exact origins ("https://app.example.com"), wildcard
+	// subdomains ("https://*.example.com") or "*" for any origin
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
 
-	// Add production frontend URL if set
-	if frontendURL := os.Getenv("FRONTEND_URL"); frontendURL != "" {
-		allowedOrigins = append(allowedOrigins, frontendURL)
+// CORSMiddleware answers preflight requests and sets CORS headers for allowed origins only
+func CORSMiddleware(opts CORSOptions) gin.HandlerFunc {
+	config := cors.Config{
+		AllowMethods:     opts.AllowedMethods,
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", RequestIDHeader, "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After", "Deprecation", "Sunset", "Link"},
+		AllowCredentials: opts.AllowCredentials,
+		MaxAge:           opts.MaxAge,
 	}
 
-	config := cors.Config{
-		AllowAllOrigins:  true,
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: false,
+	origins := newOriginMatcher(opts.AllowedOrigins)
+	if origins.any {
+		// Browsers refuse credentials with a wildcard origin, so "*" means a public API
+		if opts.AllowCredentials {
+			slog.Warn("CORS_ALLOW_CREDENTIALS is ignored because CORS_ALLOWED_ORIGINS contains *")
+			config.AllowCredentials = false
+		}
+		config.AllowAllOrigins = true
+	} else {
+		config.AllowOriginFunc = origins.allowed
 	}
+
 	return cors.New(config)
 }
+
+// originMatcher matches request origins against exact and wildcard subdomain patterns
+type originMatcher struct {
+	any      bool
+	exact    map[string]bool
+	wildcard []wildcardOrigin
+}
+
+// wildcardOrigin is "https://*.example.com" split around the asterisk
+type wildcardOrigin struct {
+	scheme string // "https://"
+	suffix string // ".example.com", optionally followed by ":port"
+}
+
+func newOriginMatcher(patterns []string) originMatcher {
+	m := originMatcher{exact: make(map[string]bool)}
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimRight(strings.TrimSpace(pattern), "/"))
+		switch {
+		case pattern == "*":
+			m.any = true
+		case strings.Contains(pattern, "://*."):
+			scheme, suffix, _ := strings.Cut(pattern, "*")
+			m.wildcard = append(m.wildcard, wildcardOrigin{scheme: scheme, suffix: suffix})
+		case pattern != "":
+			m.exact[pattern] = true
+		}
+	}
+	return m
+}
+
+func (m originMatcher) allowed(origin string) bool {
+	origin = strings.ToLower(origin)
+	if m.exact[origin] {
+		return true
+	}
+
+	for _, w := range m.wildcard {
+		if !strings.HasPrefix(origin, w.scheme) || !strings.HasSuffix(origin, w.suffix) {
+			continue
+		}
+		// The wildcard covers one or more subdomain labels, never the bare domain or a path
+		sub := strings.TrimSuffix(strings.TrimPrefix(origin, w.scheme), w.suffix)
+		if sub != "" && !strings.ContainsAny(sub, "/:@") && !strings.HasPrefix(sub, ".") && !strings.HasSuffix(sub, ".") {
+			return true
+		}
+	}
+	return false
+}
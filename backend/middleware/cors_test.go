@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestOriginMatcher(t *testing.T) {
+	m := newOriginMatcher([]string{"https://app.example.com/", " https://*.example.org ", "http://*.local.test:3000", ""})
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://app.example.com", true},
+		{"HTTPS://APP.EXAMPLE.COM", true},
+		{"http://app.example.com", false},
+		{"https://evil.example.com", false},
+		{"https://a.example.org", true},
+		{"https://a.b.example.org", true},
+		{"https://example.org", false},
+		{"https://.example.org", false},
+		{"https://evilexample.org", false},
+		{"https://a.example.org.evil.com", false},
+		{"https://user@a.example.org", false},
+		{"http://a.example.org", false},
+		{"http://web.local.test:3000", true},
+		{"http://web.local.test", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := m.allowed(tt.origin); got != tt.want {
+			t.Errorf("allowed(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	preflight := func(opts CORSOptions, origin string) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.Use(CORSMiddleware(opts))
+		r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+		req := httptest.NewRequest(http.MethodOptions, "/ping", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	opts := CORSOptions{
+		AllowedOrigins:   []string{"https://*.example.com"},
+		AllowedMethods:   []string{http.MethodGet},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	}
+
+	w := preflight(opts, "https://app.example.com")
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q for an allowed origin", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Access-Control-Allow-Credentials = %q, want true", got)
+	}
+
+	w = preflight(opts, "https://evil.test")
+	if w.Code != http.StatusForbidden || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("preflight from a foreign origin = %d with %q", w.Code, w.Header().Get("Access-Control-Allow-Origin"))
+	}
+
+	// "*" turns the API public, which browsers only accept without credentials
+	opts.AllowedOrigins = []string{"*"}
+	w = preflight(opts, "https://evil.test")
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" || w.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Errorf("wildcard origin headers = %v", w.Header())
+	}
+}
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(SecurityHeadersMiddleware(SecurityHeaderOptions{HSTSMaxAge: 600, HSTSIncludeSubdomains: true, ContentSecurityPolicy: "default-src 'none'"}))
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	for _, tt := range []struct {
+		proto string
+		hsts  string
+	}{
+		{"", ""},
+		{"https", "max-age=600; includeSubDomains"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		if tt.proto != "" {
+			req.Header.Set("X-Forwarded-Proto", tt.proto)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		want := map[string]string{
+			"X-Content-Type-Options":    "nosniff",
+			"X-Frame-Options":           "DENY",
+			"Referrer-Policy":           "no-referrer",
+			"Content-Security-Policy":   "default-src 'none'",
+			"Strict-Transport-Security": tt.hsts,
+		}
+		for name, value := range want {
+			if got := w.Header().Get(name); got != value {
+				t.Errorf("proto %q: %s = %q, want %q", tt.proto, name, got, value)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecationOptions describes a deprecated set of routes and where clients should move to
+type DeprecationOptions struct {
+	DeprecatedAt time.Time
+	// Sunset is when the routes stop working; zero leaves the Sunset header out
+	Sunset time.Time
+	// SuccessorPrefix is prepended to the request path to link the replacement, e.g. "/v1"
+	SuccessorPrefix string
+}
+
+// DeprecationMiddleware announces deprecation on every response with the Deprecation (RFC 9745),
+// Sunset (RFC 8594) and Link rel="successor-version" headers
+func DeprecationMiddleware(opts DeprecationOptions) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(opts.DeprecatedAt.Unix(), 10)
+	sunset := ""
+	if !opts.Sunset.IsZero() {
+		sunset = opts.Sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		if sunset != "" {
+			c.Header("Sunset", sunset)
+		}
+		if opts.SuccessorPrefix != "" {
+			successor := opts.SuccessorPrefix + "/" + strings.TrimPrefix(c.Request.URL.Path, "/")
+			c.Header("Link", "<"+successor+`>; rel="successor-version"`)
+		}
+		c.Next()
+	}
+}
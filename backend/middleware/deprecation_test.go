@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDeprecationMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deprecatedAt := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		opts   DeprecationOptions
+		sunset string
+		link   string
+	}{
+		{
+			name:   "sunset and successor",
+			opts:   DeprecationOptions{DeprecatedAt: deprecatedAt, Sunset: time.Date(2027, 4, 30, 0, 0, 0, 0, time.FixedZone("WIB", 7*60*60)), SuccessorPrefix: "/v1"},
+			sunset: "Thu, 29 Apr 2027 17:00:00 GMT",
+			link:   `</v1/health/3>; rel="successor-version"`,
+		},
+		{
+			name: "no sunset or successor",
+			opts: DeprecationOptions{DeprecatedAt: deprecatedAt},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/health/:id", DeprecationMiddleware(tt.opts), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/3", nil))
+
+			want := map[string]string{"Deprecation": "@1792281600", "Sunset": tt.sunset, "Link": tt.link}
+			for name, value := range want {
+				if got := w.Header().Get(name); got != value {
+					t.Errorf("%s = %q, want %q", name, got, value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"health-tracker/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// captureLogs routes slog through the request-aware handler into a buffer for the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(logging.NewHandler(&buf, slog.LevelDebug, "json")))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestIDMiddleware())
+	r.GET("/ping", func(c *gin.Context) {
+		info := logging.FromContext(c.Request.Context())
+		if info == nil || info.ID != c.GetString("requestID") {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		reused bool
+	}{
+		{"generated when missing", "", false},
+		{"client ID reused", "abc-123.def_4", true},
+		{"unsafe ID replaced", "abc\n123", false},
+		{"overlong ID replaced", strings.Repeat("a", 129), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("status = %d, request ID not stored in the context", w.Code)
+			}
+			got := w.Header().Get(RequestIDHeader)
+			if tt.reused && got != tt.header {
+				t.Errorf("%s = %q, want %q", RequestIDHeader, got, tt.header)
+			}
+			if !tt.reused && (got == tt.header || !validRequestID.MatchString(got)) {
+				t.Errorf("%s = %q, want a new ID", RequestIDHeader, got)
+			}
+		})
+	}
+}
+
+func TestLoggerMiddlewareWritesAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logs := captureLogs(t)
+	r := gin.New()
+	r.Use(RequestIDMiddleware(), LoggerMiddleware(), RecoveryMiddleware())
+	r.GET("/items/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	r.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+	tests := []struct {
+		path   string
+		status int
+		level  string
+		route  string
+	}{
+		{"/items/7", http.StatusNotFound, "WARN", "/items/:id"},
+		{"/panic", http.StatusInternalServerError, "ERROR", "/panic"},
+	}
+	for _, tt := range tests {
+		logs.Reset()
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set(RequestIDHeader, "req-1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("GET %s = %d, want %d", tt.path, w.Code, tt.status)
+		}
+
+		// The access log is the last line; a recovered panic logs its own line first
+		lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[len(lines)-1]), &entry); err != nil {
+			t.Fatalf("access log is not JSON: %v\n%s", err, logs)
+		}
+		if entry["msg"] != "request" || entry["level"] != tt.level || entry["request_id"] != "req-1" ||
+			entry["route"] != tt.route || entry["status"] != float64(tt.status) {
+			t.Errorf("GET %s logged %v", tt.path, entry)
+		}
+	}
+}
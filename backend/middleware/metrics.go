@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"health-tracker/metrics"
+	"health-tracker/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware records request counts and latency labeled by the route template
+// (e.g. /forum/posts/:id) so path parameters don't explode label cardinality.
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := routeLabel(c)
+		metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// routeLabel returns the matched route template, grouping unknown paths together
+func routeLabel(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return "unmatched"
+}
+
+// MetricsAuthMiddleware protects /metrics with a static bearer token when one is configured
+func MetricsAuthMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		given := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid metrics token")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
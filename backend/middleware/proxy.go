@@ -0,0 +1,34 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// ProxyOptions names the reverse proxies whose client IP headers are believed
+type ProxyOptions struct {
+	TrustedProxies  []string // IPs or CIDRs allowed to set X-Forwarded-For and X-Real-IP
+	TrustedPlatform string   // header the hosting platform always overwrites, e.g. CF-Connecting-IP
+}
+
+// proxiesTrusted records whether ConfigureProxies trusted anything; until it does, callers
+// are identified by the connection's peer address
+var proxiesTrusted bool
+
+// ConfigureProxies sets which proxies c.ClientIP() believes. Gin trusts every proxy by
+// default, which lets any client pick its own IP with X-Forwarded-For; with empty options
+// nothing is trusted and the client IP is the peer address.
+func ConfigureProxies(r *gin.Engine, opts ProxyOptions) error {
+	if err := r.SetTrustedProxies(opts.TrustedProxies); err != nil {
+		return err
+	}
+	r.TrustedPlatform = opts.TrustedPlatform
+	proxiesTrusted = len(opts.TrustedProxies) > 0 || opts.TrustedPlatform != ""
+	return nil
+}
+
+// clientIP is c.ClientIP() when a proxy is trusted and the peer address otherwise, so an
+// engine left with gin's trust-everything default cannot be fooled by forwarded headers
+func clientIP(c *gin.Context) string {
+	if proxiesTrusted {
+		return c.ClientIP()
+	}
+	return c.RemoteIP()
+}
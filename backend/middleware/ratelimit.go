@@ -1,98 +1,64 @@
 package middleware
 
 import (
+	"log/slog"
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
 	"time"
 
+	"health-tracker/metrics"
+	"health-tracker/ratelimit"
+	"health-tracker/utils"
+
 	"github.com/gin-gonic/gin"
 )
 
-// RateLimiter implements a simple token bucket rate limiter
-type RateLimiter struct {
-	visitors map[string]*visitor
-	mu       sync.Mutex
-	rate     int           // requests per interval
-	interval time.Duration // time interval
-}
-
-type visitor struct {
-	tokens    int
-	lastSeen  time.Time
-}
-
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(rate int, interval time.Duration) *RateLimiter {
-	rl := &RateLimiter{
-		visitors: make(map[string]*visitor),
-		rate:     rate,
-		interval: interval,
-	}
-
-	// Cleanup old visitors every minute
-	go rl.cleanupVisitors()
+// RateLimitMiddleware limits requests per caller within a named group. Callers are
+// keyed by user ID once AuthMiddleware has run and by client IP otherwise (see
+// ConfigureProxies), so each group keeps its own budget. Responses carry the RateLimit-*
+// headers, and rejected requests also get Retry-After. If the store fails the request
+// is let through.
+func RateLimitMiddleware(store ratelimit.Store, group string, limit ratelimit.Limit) gin.HandlerFunc {
+	policy := strconv.Itoa(limit.Burst) + ";w=" + strconv.Itoa(int(limit.Period.Seconds()))
 
-	return rl
-}
+	return func(c *gin.Context) {
+		key := "ratelimit:" + group + ":" + rateLimitSubject(c)
 
-func (rl *RateLimiter) cleanupVisitors() {
-	for {
-		time.Sleep(time.Minute)
-		rl.mu.Lock()
-		for ip, v := range rl.visitors {
-			if time.Since(v.lastSeen) > 3*time.Minute {
-				delete(rl.visitors, ip)
-			}
+		result, err := store.Allow(c.Request.Context(), key, limit)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "rate limiter unavailable, allowing request", "group", group, "error", err)
+			c.Next()
+			return
 		}
-		rl.mu.Unlock()
-	}
-}
 
-func (rl *RateLimiter) getVisitor(ip string) *visitor {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
+		c.Header("RateLimit-Policy", policy)
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(result.ResetAfter))
 
-	v, exists := rl.visitors[ip]
-	if !exists {
-		rl.visitors[ip] = &visitor{tokens: rl.rate, lastSeen: time.Now()}
-		return rl.visitors[ip]
-	}
-
-	// Refill tokens based on time elapsed
-	elapsed := time.Since(v.lastSeen)
-	tokensToAdd := int(elapsed / rl.interval) * rl.rate
-	v.tokens += tokensToAdd
-	if v.tokens > rl.rate {
-		v.tokens = rl.rate
-	}
-	v.lastSeen = time.Now()
-
-	return v
-}
-
-// RateLimitMiddleware returns a Gin middleware for rate limiting
-func RateLimitMiddleware(rate int, interval time.Duration) gin.HandlerFunc {
-	limiter := NewRateLimiter(rate, interval)
-
-	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		visitor := limiter.getVisitor(ip)
-
-		if visitor.tokens <= 0 {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"success": false,
-				"message": "Rate limit exceeded. Please try again later.",
-			})
+		if !result.Allowed {
+			metrics.RateLimitRejections.WithLabelValues(group, routeLabel(c)).Inc()
+			c.Header("Retry-After", ceilSeconds(result.RetryAfter))
+			utils.ErrorResponse(c, http.StatusTooManyRequests, "Rate limit exceeded. Please try again later.")
 			c.Abort()
 			return
 		}
 
-		visitor.tokens--
 		c.Next()
 	}
 }
 
-// StrictRateLimitMiddleware for sensitive endpoints like login
-func StrictRateLimitMiddleware() gin.HandlerFunc {
-	return RateLimitMiddleware(10, time.Minute) // 10 requests per minute
+// rateLimitSubject identifies the caller: the authenticated user, or the client IP as
+// resolved through the trusted proxies
+func rateLimitSubject(c *gin.Context) string {
+	if userID := c.GetUint("userID"); userID != 0 {
+		return "user:" + strconv.FormatUint(uint64(userID), 10)
+	}
+	return "ip:" + clientIP(c)
+}
+
+// ceilSeconds rounds up so clients never retry too early
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
 }
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"health-tracker/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newRateLimitedRouter(t *testing.T, opts ProxyOptions) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	if err := ConfigureProxies(r, opts); err != nil {
+		t.Fatalf("ConfigureProxies: %v", err)
+	}
+	t.Cleanup(func() { proxiesTrusted = false })
+
+	store := ratelimit.NewMemoryStore()
+	t.Cleanup(func() { store.Close() })
+	r.Use(RateLimitMiddleware(store, "test", ratelimit.Limit{Rate: 1, Period: time.Minute, Burst: 1}))
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return r
+}
+
+func get(r *gin.Engine, remoteAddr, forwardedFor string) int {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRateLimitIgnoresForwardedForByDefault(t *testing.T) {
+	r := newRateLimitedRouter(t, ProxyOptions{})
+
+	if code := get(r, "198.51.100.7:1234", "203.0.113.1"); code != http.StatusNoContent {
+		t.Fatalf("first request = %d, want 204", code)
+	}
+	// A new X-Forwarded-For from the same connection address must not buy a new budget
+	if code := get(r, "198.51.100.7:1234", "203.0.113.2"); code != http.StatusTooManyRequests {
+		t.Errorf("spoofed request = %d, want 429", code)
+	}
+	if code := get(r, "198.51.100.8:1234", ""); code != http.StatusNoContent {
+		t.Errorf("other client = %d, want 204", code)
+	}
+}
+
+func TestRateLimitUsesForwardedForFromTrustedProxy(t *testing.T) {
+	r := newRateLimitedRouter(t, ProxyOptions{TrustedProxies: []string{"10.0.0.0/8"}})
+
+	if code := get(r, "10.1.2.3:1234", "203.0.113.1"); code != http.StatusNoContent {
+		t.Fatalf("first client = %d, want 204", code)
+	}
+	if code := get(r, "10.1.2.3:1234", "203.0.113.2"); code != http.StatusNoContent {
+		t.Errorf("second client behind the proxy = %d, want 204", code)
+	}
+	if code := get(r, "10.1.2.3:1234", "203.0.113.1"); code != http.StatusTooManyRequests {
+		t.Errorf("first client again = %d, want 429", code)
+	}
+	// An untrusted peer still cannot choose its IP
+	if code := get(r, "198.51.100.7:1234", "203.0.113.3"); code != http.StatusNoContent {
+		t.Fatalf("untrusted peer = %d, want 204", code)
+	}
+	if code := get(r, "198.51.100.7:1234", "203.0.113.4"); code != http.StatusTooManyRequests {
+		t.Errorf("untrusted peer spoofing = %d, want 429", code)
+	}
+}
+
+func TestConfigureProxiesRejectsInvalidAddress(t *testing.T) {
+	r := gin.New()
+	if err := ConfigureProxies(r, ProxyOptions{TrustedProxies: []string{"not-an-ip"}}); err == nil {
+		t.Fatal("ConfigureProxies accepted an invalid proxy")
+	}
+}
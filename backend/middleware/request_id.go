@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"health-tracker/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader is read from incoming requests and echoed on every response
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID keeps client-supplied IDs short and safe to put in logs
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestIDMiddleware reuses the caller's X-Request-ID or generates one, returns it in the
+// response header and stores it in the request context for logging.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+
+		c.Header(RequestIDHeader, id)
+		c.Set("requestID", id)
+		c.Request = c.Request.WithContext(logging.NewContext(c.Request.Context(), &logging.RequestInfo{ID: id}))
+
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// ExportJob tracks a background personal data export
+type ExportJob struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	UserID      uint       `json:"user_id" gorm:"not null;index"`
+	Status      string     `json:"status" gorm:"size:20;not null"` // pending, running, completed, failed
+	FilePath    string     `json:"-" gorm:"size:500"`
+	Error       string     `json:"error,omitempty" gorm:"size:500"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ExportJob status constants
+const (
+	ExportStatusPending   = "pending"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+)
+
+// ExportJobResponse is the response structure for an export job
+type ExportJobResponse struct {
+	ID          uint       `json:"id"`
+	Status      string     `json:"status"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
@@ -18,7 +18,7 @@ type FamilyMember struct {
 
 type FamilyInviteRequest struct {
 	MemberEmail  string `json:"member_email" binding:"required,email"`
-	Relationship string `json:"relationship" binding:"required"`
+	Relationship string `json:"relationship" binding:"required,max=50"`
 }
 
 type FamilyMemberResponse struct {
@@ -35,6 +35,17 @@ type Like struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// CreatePostRequest is the request structure for creating a post
+type CreatePostRequest struct {
+	Title   string `json:"title" binding:"required,max=200"`
+	Content string `json:"content" binding:"required,max=10000"`
+}
+
+// CreateCommentRequest is the request structure for commenting on a post
+type CreateCommentRequest struct {
+	Content string `json:"content" binding:"required,max=2000"`
+}
+
 // PostResponse is the response structure for a post
 type PostResponse struct {
 	ID            uint      `json:"id"`
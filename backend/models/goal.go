@@ -18,6 +18,21 @@ type Goal struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// CreateGoalRequest is the request structure for creating a goal
+type CreateGoalRequest struct {
+	Title       string  `json:"title" binding:"required,max=200"`
+	Description string  `json:"description" binding:"max=1000"`
+	Type        string  `json:"type" binding:"required,max=50"`
+	Target      float64 `json:"target" binding:"required"`
+	Unit        string  `json:"unit" binding:"max=20"`
+	Deadline    string  `json:"deadline"`
+}
+
+// UpdateGoalProgressRequest is the request structure for updating a goal's progress
+type UpdateGoalProgressRequest struct {
+	Current float64 `json:"current" binding:"required"`
+}
+
 // GoalResponse is the response structure for a goal
 type GoalResponse struct {
 	ID          uint    `json:"id"`
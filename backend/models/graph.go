@@ -0,0 +1,132 @@
+package models
+
+import "time"
+
+// Graph bucket sizes
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week" // ISO weeks, starting on Monday
+	BucketMonth = "month"
+)
+
+// Graph fill modes for buckets without health records
+const (
+	FillNull     = "null"     // keep the bucket, series values are null
+	FillNone     = "none"     // drop the bucket
+	FillPrevious = "previous" // carry the previous bucket's values forward
+)
+
+// HealthGraph is health data aggregated into time buckets, with overlay series aligned to
+// the same buckets
+type HealthGraph struct {
+	Period   string        `json:"period"`
+	Bucket   string        `json:"bucket"`
+	From     time.Time     `json:"from"`
+	To       time.Time     `json:"to"`
+	Timezone string        `json:"timezone"`
+	Fill     string        `json:"fill"`
+	Buckets  []GraphBucket `json:"buckets"`
+}
+
+// GraphBucket is one point of the graph; Start is the bucket's first day in the graph's timezone
+type GraphBucket struct {
+	Start           string         `json:"start"`
+	Count           int            `json:"count"` // health records in the bucket
+	Weight          *SeriesStats   `json:"weight"`
+	BMI             *SeriesStats   `json:"bmi"`
+	EmotionalStates map[string]int `json:"emotional_states"`
+	Symptoms        SymptomOverlay `json:"symptoms"`
+	Water           WaterOverlay   `json:"water"`
+	Filled          bool           `json:"filled,omitempty"` // values were carried forward
+}
+
+// SeriesStats summarises one series within a bucket; Last is the latest reading
+type SeriesStats struct {
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Avg  float64 `json:"avg"`
+	Last float64 `json:"last"`
+}
+
+// SymptomOverlay counts the symptoms logged in a bucket
+type SymptomOverlay struct {
+	Count       int     `json:"count"`
+	AvgSeverity float64 `json:"avg_severity"`
+	MaxSeverity int     `json:"max_severity"`
+}
+
+// WaterOverlay sums the water intake recorded in a bucket
+type WaterOverlay struct {
+	Glasses     int `json:"glasses"`
+	Days        int `json:"days"` // days with a water record
+	DaysGoalMet int `json:"days_goal_met"`
+}
+
+// HealthBucketRow is the SQL aggregate of the health records in one bucket
+type HealthBucketRow struct {
+	Bucket     string
+	Count      int
+	MinWeight  float64
+	MaxWeight  float64
+	AvgWeight  float64
+	LastWeight float64
+	MinBMI     float64
+	MaxBMI     float64
+	AvgBMI     float64
+	LastBMI    float64
+}
+
+// BucketCount counts the rows with a given label in one bucket
+type BucketCount struct {
+	Bucket string
+	Label  string
+	Count  int
+}
+
+// SymptomBucketRow is the SQL aggregate of the symptoms in one bucket
+type SymptomBucketRow struct {
+	Bucket      string
+	Count       int
+	AvgSeverity float64
+	MaxSeverity int
+}
+
+// WaterBucketRow is the SQL aggregate of the water intake in one bucket
+type WaterBucketRow struct {
+	Bucket      string
+	Glasses     int
+	Days        int
+	DaysGoalMet int
+}
+
+// BucketStart returns the start of the bucket containing t, in t's location
+func BucketStart(t time.Time, bucket string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch bucket {
+	case BucketWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case BucketMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+// NextBucket returns the start of the bucket after the one starting at start
+func NextBucket(start time.Time, bucket string) time.Time {
+	switch bucket {
+	case BucketWeek:
+		return start.AddDate(0, 0, 7)
+	case BucketMonth:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// BucketStarts lists the buckets overlapping [from, to) as YYYY-MM-DD dates in loc
+func BucketStarts(from, to time.Time, bucket string, loc *time.Location) []string {
+	var starts []string
+	for t := BucketStart(from.In(loc), bucket); t.Before(to); t = NextBucket(t, bucket) {
+		starts = append(starts, t.Format("2006-01-02"))
+	}
+	return starts
+}
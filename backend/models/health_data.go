@@ -6,7 +6,7 @@ import (
 
 type HealthData struct {
 	ID             uint      `gorm:"primaryKey" json:"id"`
-	UserID         uint      `gorm:"not null" json:"user_id"`
+	UserID         uint      `gorm:"not null;index:idx_health_data_user_record_date,priority:1" json:"user_id"`
 	WeightKg       float64   `json:"weight_kg"`
 	HeightCm       float64   `json:"height_cm"`
 	BMI            float64   `json:"bmi"`
@@ -14,26 +14,34 @@ type HealthData struct {
 	EmotionalState string    `json:"emotional_state"`
 	DailySchedule  string    `gorm:"type:text" json:"daily_schedule"`
 	Notes          string    `json:"notes"`
-	RecordDate     time.Time `json:"record_date"`
+	RecordDate     time.Time `gorm:"index:idx_health_data_user_record_date,priority:2" json:"record_date"`
 	CreatedAt      time.Time `json:"created_at"`
 }
 
+// HealthDataRequest creates or replaces a health record
 type HealthDataRequest struct {
-	WeightKg       float64 `json:"weight_kg" binding:"required"`
-	HeightCm       float64 `json:"height_cm" binding:"required"`
-	ActivityLevel  string  `json:"activity_level"`
-	EmotionalState string  `json:"emotional_state"`
-	DailySchedule  string  `json:"daily_schedule"`
-	Notes          string  `json:"notes"`
+	WeightKg       float64 `json:"weight_kg" binding:"required,min=2,max=500"`
+	HeightCm       float64 `json:"height_cm" binding:"required,min=30,max=300"`
+	ActivityLevel  string  `json:"activity_level" binding:"max=50"`
+	EmotionalState string  `json:"emotional_state" binding:"max=50"`
+	DailySchedule  string  `json:"daily_schedule" binding:"max=500"`
+	Notes          string  `json:"notes" binding:"max=1000"`
+	// RecordDate backdates the record; it defaults to now on create and is kept on update
+	RecordDate *time.Time `json:"record_date"`
 }
 
+// MaxClockSkew is how far in the future a client-supplied timestamp may lie
+const MaxClockSkew = 5 * time.Minute
+
 type DashboardData struct {
 	LatestHealth    *HealthData          `json:"latest_health"`
 	BMICategory     string               `json:"bmi_category"`
 	HealthScore     int                  `json:"health_score"`
 	TotalRecords    int64                `json:"total_records"`
 	RecentSymptoms  []Symptom            `json:"recent_symptoms"`
+	LatestVitals    []Measurement        `json:"latest_vitals"`
 	WeeklyProgress  []HealthData         `json:"weekly_progress"`
+	Insights        []Insight            `json:"insights"` // unread, newest first
 	Recommendations []RecommendationItem `json:"recommendations"`
 }
 
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// Insight kinds
+const (
+	InsightTrend      = "trend"
+	InsightProjection = "projection"
+	InsightAnomaly    = "anomaly"
+)
+
+// Insight severities
+const (
+	InsightInfo    = "info"
+	InsightWarning = "warning"
+	InsightAlert   = "alert"
+)
+
+// MetricWeight is the insight metric for body weight; vitals use their measurement type
+const MetricWeight = "weight"
+
+// Insight is a stored observation about a user's data, shown on the dashboard until read.
+// Fingerprint identifies what the insight is about, so re-running the analysis updates an
+// insight instead of repeating it.
+type Insight struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"not null;uniqueIndex:idx_insights_user_fingerprint,priority:1" json:"user_id"`
+	Kind        string     `gorm:"size:20;not null" json:"kind"`
+	Metric      string     `gorm:"size:30;not null" json:"metric"`
+	Severity    string     `gorm:"size:10;not null" json:"severity"`
+	Title       string     `gorm:"size:200" json:"title"`
+	Message     string     `gorm:"size:500" json:"message"`
+	Value       float64    `json:"value"`
+	Fingerprint string     `gorm:"size:100;not null;uniqueIndex:idx_insights_user_fingerprint,priority:2" json:"-"`
+	ObservedAt  time.Time  `json:"observed_at"` // when the underlying reading was taken
+	ReadAt      *time.Time `json:"read_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// MetricTrend describes the direction of one metric. Pointer fields are null when there
+// are too few readings to compute them.
+type MetricTrend struct {
+	Metric       string    `json:"metric"`
+	Unit         string    `json:"unit"`
+	Readings     int       `json:"readings"`
+	Latest       float64   `json:"latest"`
+	LatestAt     time.Time `json:"latest_at"`
+	Avg7d        *float64  `json:"avg_7d"`
+	Avg30d       *float64  `json:"avg_30d"`
+	WeeklyChange *float64  `json:"weekly_change"` // least-squares slope over the last 30 days, per week
+	Direction    string    `json:"direction"`     // rising, falling, stable or unknown
+	// Projection is where the metric heads at the current pace; weight only
+	Projection *Projection `json:"projection,omitempty"`
+}
+
+// Projection extrapolates the current trend, towards the user's weight goal when there is one
+type Projection struct {
+	Target        *float64 `json:"target,omitempty"`
+	TargetDate    string   `json:"target_date,omitempty"` // empty when the trend moves away from the target
+	In4Weeks      float64  `json:"in_4_weeks"`
+	GoalReachable bool     `json:"goal_reachable"`
+	Reached       bool     `json:"reached"` // the readings crossed the target within the last 30 days
+}
+
+// InsightsReport is the response of the insights endpoint
+type InsightsReport struct {
+	Trends   []MetricTrend `json:"trends"`
+	Insights []Insight     `json:"insights"`
+	Unread   int64         `json:"unread"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// LoginAttempt records a sign-in attempt for auditing and brute-force protection
+type LoginAttempt struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    *uint     `json:"user_id" gorm:"index"` // nil when the email is unknown
+	Email     string    `json:"email" gorm:"size:255;index"`
+	IPAddress string    `json:"ip_address" gorm:"size:64"`
+	UserAgent string    `json:"user_agent" gorm:"size:255"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason" gorm:"size:50"` // invalid_password, invalid_2fa_code, locked, unknown_email, suspended
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Login attempt failure reasons
+const (
+	LoginReasonInvalidPassword = "invalid_password"
+	LoginReasonInvalid2FACode  = "invalid_2fa_code"
+	LoginReasonLocked          = "locked"
+	LoginReasonUnknownEmail    = "unknown_email"
+	LoginReasonSuspended       = "suspended"
+)
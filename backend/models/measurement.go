@@ -0,0 +1,295 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Measurement types
+const (
+	MeasurementBloodPressure   = "blood_pressure"
+	MeasurementHeartRate       = "heart_rate"
+	MeasurementBloodGlucose    = "blood_glucose"
+	MeasurementSpO2            = "spo2"
+	MeasurementBodyTemperature = "body_temperature"
+	MeasurementCholesterol     = "cholesterol"
+)
+
+// Blood glucose contexts; the thresholds differ before and after eating
+const (
+	GlucoseFasting  = "fasting"
+	GlucosePostMeal = "post_meal"
+)
+
+// Risk levels attached to a measurement's category
+const (
+	RiskNormal   = 0
+	RiskWatch    = 1 // outside the normal range, worth keeping an eye on
+	RiskElevated = 2
+	RiskHigh     = 3 // see a doctor; for some categories urgently
+)
+
+// Measurement is a single vital sign reading, stored in the type's canonical unit
+type Measurement struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"not null;index:idx_measurements_user_type_measured_at,priority:1" json:"user_id"`
+	Type       string    `gorm:"size:30;not null;index:idx_measurements_user_type_measured_at,priority:2" json:"type"`
+	Value      float64   `json:"value"`               // systolic pressure for blood_pressure
+	Diastolic  *float64  `json:"diastolic,omitempty"` // blood_pressure only
+	Context    string    `gorm:"size:20" json:"context,omitempty"`
+	Unit       string    `gorm:"size:10" json:"unit"`
+	Category   string    `gorm:"size:30" json:"category"`
+	RiskLevel  int       `json:"risk_level"`
+	Notes      string    `json:"notes"`
+	MeasuredAt time.Time `gorm:"index:idx_measurements_user_type_measured_at,priority:3" json:"measured_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// MeasurementRequest is the request structure for recording a measurement
+type MeasurementRequest struct {
+	Type      string   `json:"type" binding:"required,oneof=blood_pressure heart_rate blood_glucose spo2 body_temperature cholesterol"`
+	Value     float64  `json:"value" binding:"required"`
+	Diastolic *float64 `json:"diastolic"`
+	Context   string   `json:"context" binding:"omitempty,oneof=fasting post_meal"`
+	// Unit defaults to the type's canonical unit; mmol/L and °F are converted
+	Unit       string     `json:"unit" binding:"max=10"`
+	MeasuredAt *time.Time `json:"measured_at"`
+	Notes      string     `json:"notes" binding:"max=500"`
+}
+
+// MeasurementSpec describes a measurement type: its unit, accepted range and categories
+type MeasurementSpec struct {
+	Type         string   `json:"type"`
+	Label        string   `json:"label"`
+	Unit         string   `json:"unit"`
+	OtherUnits   []string `json:"other_units,omitempty"`
+	Min          float64  `json:"min"`
+	Max          float64  `json:"max"`
+	DiastolicMin float64  `json:"diastolic_min,omitempty"`
+	DiastolicMax float64  `json:"diastolic_max,omitempty"`
+	Contexts     []string `json:"contexts,omitempty"`
+	Categories   []string `json:"categories"`
+
+	convert map[string]func(float64) float64
+}
+
+// MeasurementSpecs lists the supported types. Ranges reject readings no device would
+// produce, not merely unhealthy ones.
+var MeasurementSpecs = []MeasurementSpec{
+	{
+		Type: MeasurementBloodPressure, Label: "Tekanan Darah", Unit: "mmHg",
+		Min: 50, Max: 300, DiastolicMin: 30, DiastolicMax: 200,
+		Categories: []string{"Low", "Normal", "Elevated", "Hypertension Stage 1", "Hypertension Stage 2", "Hypertensive Crisis"},
+	},
+	{
+		Type: MeasurementHeartRate, Label: "Detak Jantung Istirahat", Unit: "bpm",
+		Min: 20, Max: 250,
+		Categories: []string{"Low", "Normal", "High"},
+	},
+	{
+		Type: MeasurementBloodGlucose, Label: "Gula Darah", Unit: "mg/dL", OtherUnits: []string{"mmol/L"},
+		Min: 20, Max: 800, Contexts: []string{GlucoseFasting, GlucosePostMeal},
+		Categories: []string{"Low", "Normal", "Prediabetes", "Diabetes"},
+		convert:    map[string]func(float64) float64{"mmol/L": func(v float64) float64 { return v * 18.016 }},
+	},
+	{
+		Type: MeasurementSpO2, Label: "Saturasi Oksigen", Unit: "%",
+		Min: 50, Max: 100,
+		Categories: []string{"Normal", "Low", "Very Low"},
+	},
+	{
+		Type: MeasurementBodyTemperature, Label: "Suhu Tubuh", Unit: "°C", OtherUnits: []string{"°F"},
+		Min: 25, Max: 45,
+		Categories: []string{"Hypothermia", "Normal", "Low-grade Fever", "Fever", "High Fever"},
+		convert:    map[string]func(float64) float64{"°F": func(v float64) float64 { return (v - 32) * 5 / 9 }},
+	},
+	{
+		Type: MeasurementCholesterol, Label: "Kolesterol Total", Unit: "mg/dL", OtherUnits: []string{"mmol/L"},
+		Min: 50, Max: 600,
+		Categories: []string{"Desirable", "Borderline High", "High"},
+		convert:    map[string]func(float64) float64{"mmol/L": func(v float64) float64 { return v * 38.67 }},
+	},
+}
+
+// FindMeasurementSpec returns the spec for a measurement type
+func FindMeasurementSpec(measurementType string) (MeasurementSpec, bool) {
+	for _, spec := range MeasurementSpecs {
+		if spec.Type == measurementType {
+			return spec, true
+		}
+	}
+	return MeasurementSpec{}, false
+}
+
+// MeasurementFieldError reports which request field failed which rule, and why
+type MeasurementFieldError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+func (e *MeasurementFieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// NewMeasurement validates a request against the type's spec and returns the reading in
+// canonical units, classified. Errors are *MeasurementFieldError.
+func NewMeasurement(userID uint, req MeasurementRequest, now time.Time) (*Measurement, error) {
+	spec, ok := FindMeasurementSpec(req.Type)
+	if !ok {
+		return nil, &MeasurementFieldError{"type", "oneof", "is not a known measurement type"}
+	}
+
+	toCanonical := func(v float64) float64 { return v }
+	if req.Unit != "" && req.Unit != spec.Unit {
+		convert, ok := spec.convert[req.Unit]
+		if !ok {
+			return nil, &MeasurementFieldError{"unit", "oneof", fmt.Sprintf("must be %s for %s", spec.unitList(), spec.Type)}
+		}
+		toCanonical = convert
+	}
+
+	m := &Measurement{
+		UserID:     userID,
+		Type:       spec.Type,
+		Value:      round1(toCanonical(req.Value)),
+		Unit:       spec.Unit,
+		Notes:      req.Notes,
+		MeasuredAt: now.UTC(),
+	}
+	if m.Value < spec.Min || m.Value > spec.Max {
+		return nil, &MeasurementFieldError{"value", "range", fmt.Sprintf("must be between %g and %g %s", spec.Min, spec.Max, spec.Unit)}
+	}
+
+	switch spec.Type {
+	case MeasurementBloodPressure:
+		if req.Diastolic == nil {
+			return nil, &MeasurementFieldError{"diastolic", "required", "is required for blood_pressure"}
+		}
+		diastolic := round1(*req.Diastolic)
+		if diastolic < spec.DiastolicMin || diastolic > spec.DiastolicMax {
+			return nil, &MeasurementFieldError{"diastolic", "range", fmt.Sprintf("must be between %g and %g mmHg", spec.DiastolicMin, spec.DiastolicMax)}
+		}
+		if diastolic >= m.Value {
+			return nil, &MeasurementFieldError{"diastolic", "ltfield", "must be lower than the systolic value"}
+		}
+		m.Diastolic = &diastolic
+	case MeasurementBloodGlucose:
+		if req.Context == "" {
+			return nil, &MeasurementFieldError{"context", "required", "is required for blood_glucose (fasting or post_meal)"}
+		}
+		m.Context = req.Context
+	}
+	if req.Diastolic != nil && spec.Type != MeasurementBloodPressure {
+		return nil, &MeasurementFieldError{"diastolic", "excluded", "is only accepted for blood_pressure"}
+	}
+	if req.Context != "" && spec.Type != MeasurementBloodGlucose {
+		return nil, &MeasurementFieldError{"context", "excluded", "is only accepted for blood_glucose"}
+	}
+
+	if req.MeasuredAt != nil {
+		if req.MeasuredAt.After(now.Add(MaxClockSkew)) {
+			return nil, &MeasurementFieldError{"measured_at", "past", "cannot be in the future"}
+		}
+		// Stored in UTC, see the repository package doc
+		m.MeasuredAt = req.MeasuredAt.UTC()
+	}
+
+	m.Category, m.RiskLevel = ClassifyMeasurement(m)
+	return m, nil
+}
+
+// ClassifyMeasurement returns the category and risk level of a reading in canonical units:
+// AHA stages for blood pressure, ADA ranges for glucose and NCEP ranges for total cholesterol
+func ClassifyMeasurement(m *Measurement) (string, int) {
+	v := m.Value
+	switch m.Type {
+	case MeasurementBloodPressure:
+		diastolic := 0.0
+		if m.Diastolic != nil {
+			diastolic = *m.Diastolic
+		}
+		switch {
+		case v > 180 || diastolic > 120:
+			return "Hypertensive Crisis", RiskHigh
+		case v >= 140 || diastolic >= 90:
+			return "Hypertension Stage 2", RiskHigh
+		case v >= 130 || diastolic >= 80:
+			return "Hypertension Stage 1", RiskElevated
+		case v >= 120:
+			return "Elevated", RiskWatch
+		case v < 90 || diastolic < 60:
+			return "Low", RiskWatch
+		}
+		return "Normal", RiskNormal
+
+	case MeasurementHeartRate:
+		switch {
+		case v < 60:
+			return "Low", RiskWatch
+		case v > 100:
+			return "High", RiskElevated
+		}
+		return "Normal", RiskNormal
+
+	case MeasurementBloodGlucose:
+		normal, diabetes := 100.0, 126.0
+		if m.Context == GlucosePostMeal {
+			normal, diabetes = 140, 200
+		}
+		switch {
+		case v < 70:
+			return "Low", RiskElevated
+		case v < normal:
+			return "Normal", RiskNormal
+		case v < diabetes:
+			return "Prediabetes", RiskElevated
+		}
+		return "Diabetes", RiskHigh
+
+	case MeasurementSpO2:
+		switch {
+		case v >= 95:
+			return "Normal", RiskNormal
+		case v >= 90:
+			return "Low", RiskElevated
+		}
+		return "Very Low", RiskHigh
+
+	case MeasurementBodyTemperature:
+		switch {
+		case v < 35:
+			return "Hypothermia", RiskHigh
+		case v < 37.5:
+			return "Normal", RiskNormal
+		case v < 38:
+			return "Low-grade Fever", RiskWatch
+		case v < 39.5:
+			return "Fever", RiskElevated
+		}
+		return "High Fever", RiskHigh
+
+	case MeasurementCholesterol:
+		switch {
+		case v < 200:
+			return "Desirable", RiskNormal
+		case v < 240:
+			return "Borderline High", RiskWatch
+		}
+		return "High", RiskElevated
+	}
+	return "", RiskNormal
+}
+
+func (s MeasurementSpec) unitList() string {
+	units := s.Unit
+	for _, u := range s.OtherUnits {
+		units += " or " + u
+	}
+	return units
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
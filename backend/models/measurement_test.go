@@ -0,0 +1,30 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewMeasurementStoresUTC(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, jakarta)
+	takenAt := time.Date(2026, 10, 18, 6, 30, 0, 0, jakarta)
+
+	for name, req := range map[string]MeasurementRequest{
+		"default time": {Type: MeasurementHeartRate, Value: 72},
+		"measured_at":  {Type: MeasurementHeartRate, Value: 72, MeasuredAt: &takenAt},
+	} {
+		m, err := NewMeasurement(1, req, now)
+		if err != nil {
+			t.Fatalf("%s: NewMeasurement: %v", name, err)
+		}
+		if m.MeasuredAt.Location() != time.UTC {
+			t.Errorf("%s: measured_at location = %v, want UTC", name, m.MeasuredAt.Location())
+		}
+	}
+
+	m, _ := NewMeasurement(1, MeasurementRequest{Type: MeasurementHeartRate, Value: 72, MeasuredAt: &takenAt}, now)
+	if !m.MeasuredAt.Equal(takenAt) {
+		t.Errorf("measured_at = %v, want the same instant as %v", m.MeasuredAt, takenAt)
+	}
+}
@@ -27,14 +27,14 @@ const (
 // CreateReminderRequest is the request structure for creating a reminder
 type CreateReminderRequest struct {
 	Type  string `json:"type" binding:"required"`
-	Label string `json:"label" binding:"required"`
+	Label string `json:"label" binding:"required,max=100"`
 	Time  string `json:"time" binding:"required"`
 }
 
 // UpdateReminderRequest is the request structure for updating a reminder
 type UpdateReminderRequest struct {
 	Type     string `json:"type"`
-	Label    string `json:"label"`
+	Label    string `json:"label" binding:"max=100"`
 	Time     string `json:"time"`
 	IsActive *bool  `json:"is_active"`
 }
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Session represents a refresh-token backed login session
+type Session struct {
+	ID               uint       `json:"id" gorm:"primaryKey"`
+	UserID           uint       `json:"user_id" gorm:"not null;index"`
+	RefreshTokenHash string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	UserAgent        string     `json:"user_agent" gorm:"size:255"`
+	IPAddress        string     `json:"ip_address" gorm:"size:64"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+	LastUsedAt       time.Time  `json:"last_used_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the session can still be used
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// RefreshTokenRequest is the request structure for refreshing or revoking a session
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
@@ -6,19 +6,25 @@ import (
 
 type Symptom struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
-	UserID      uint      `gorm:"not null" json:"user_id"`
+	UserID      uint      `gorm:"not null;index:idx_symptoms_user_logged_at,priority:1" json:"user_id"`
 	SymptomType string    `gorm:"not null" json:"symptom_type"` // physical, mental
 	SymptomName string    `gorm:"not null" json:"symptom_name"`
 	Severity    int       `json:"severity"` // 1-10
 	Notes       string    `json:"notes"`
-	LoggedAt    time.Time `json:"logged_at"`
+	LoggedAt    time.Time `gorm:"index:idx_symptoms_user_logged_at,priority:2" json:"logged_at"`
 }
 
 type SymptomRequest struct {
-	SymptomType string `json:"symptom_type" binding:"required"`
-	SymptomName string `json:"symptom_name" binding:"required"`
+	SymptomType string `json:"symptom_type" binding:"required,max=50"`
+	SymptomName string `json:"symptom_name" binding:"required,max=100"`
 	Severity    int    `json:"severity" binding:"required,min=1,max=10"`
-	Notes       string `json:"notes"`
+	Notes       string `json:"notes" binding:"max=1000"`
+}
+
+// SymptomCount is how often a symptom was logged
+type SymptomCount struct {
+	SymptomName string `json:"symptom_name"`
+	Count       int    `json:"count"`
 }
 
 type SymptomTemplate struct {
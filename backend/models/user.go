@@ -5,22 +5,54 @@ import (
 )
 
 type User struct {
-	ID            uint      `gorm:"primaryKey" json:"id"`
-	Email         string    `gorm:"unique;not null" json:"email"`
-	Password      string    `gorm:"not null" json:"-"`
-	Name          string    `gorm:"not null" json:"name"`
-	BirthDate     time.Time `json:"birth_date"`
-	HeightCm      float64   `json:"height_cm"`
-	WeightKg      float64   `json:"weight_kg"`
-	ActivityLevel string    `gorm:"default:'sedentary'" json:"activity_level"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	Email             string     `gorm:"unique;not null" json:"email"`
+	Password          string     `gorm:"not null" json:"-"`
+	Name              string     `gorm:"not null" json:"name"`
+	BirthDate         time.Time  `json:"birth_date"`
+	HeightCm          float64    `json:"height_cm"`
+	WeightKg          float64    `json:"weight_kg"`
+	ActivityLevel     string     `gorm:"default:'sedentary'" json:"activity_level"`
+	Role              string     `gorm:"size:20;default:'user'" json:"role"` // user, moderator, admin
+	SuspendedAt       *time.Time `json:"suspended_at,omitempty"`
+	SuspendedReason   string     `gorm:"size:500" json:"suspended_reason,omitempty"`
+	TwoFactorEnabled  bool       `gorm:"default:false" json:"two_factor_enabled"`
+	TwoFactorSecret   string     `json:"-"`
+	TwoFactorLastStep int64      `json:"-"` // last accepted TOTP step, prevents code replay
+	LoginChallenge    string     `json:"-"` // hash of the pending 2FA login challenge, empty once used
+	FailedLogins      int        `gorm:"default:0" json:"-"`
+	LastFailedLoginAt *time.Time `json:"-"`
+	LockedUntil       *time.Time `json:"-"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// IsLocked reports whether the account is temporarily locked after failed logins
+func (u *User) IsLocked() bool {
+	return u.LockedUntil != nil && time.Now().Before(*u.LockedUntil)
+}
+
+// IsSuspended reports whether an administrator has suspended the account
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
+
+// User roles, from least to most privileged
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// IsValidRole reports whether role is one of the known roles
+func IsValidRole(role string) bool {
+	return role == RoleUser || role == RoleModerator || role == RoleAdmin
 }
 
 type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
-	Name     string `json:"name" binding:"required"`
+	Name     string `json:"name" binding:"required,max=100"`
 }
 
 type LoginRequest struct {
@@ -29,14 +61,45 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // access token lifetime in seconds
+	User         User   `json:"user"`
+}
+
+// TwoFactorChallengeResponse is returned by Login when a second factor is required
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int64  `json:"expires_in"`
 }
 
 type UpdateProfileRequest struct {
-	Name          string    `json:"name"`
+	Name          string    `json:"name" binding:"max=100"`
 	BirthDate     time.Time `json:"birth_date"`
 	HeightCm      float64   `json:"height_cm"`
 	WeightKg      float64   `json:"weight_kg"`
 	ActivityLevel string    `json:"activity_level"`
 }
+
+// DeleteAccountRequest confirms account deletion
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code"` // required when 2FA is enabled
+}
+
+// Placeholder account that anonymized forum content is reassigned to
+const (
+	DeletedUserEmail = "deleted-user@health-tracker.local"
+	DeletedUserName  = "Pengguna Terhapus"
+)
+
+// SuspendUserRequest is the request structure for suspending a user
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// UpdateRoleRequest is the request structure for assigning a role
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user moderator admin"`
+}
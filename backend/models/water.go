@@ -13,6 +13,11 @@ type WaterIntake struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// UpdateWaterGoalRequest is the request structure for changing the daily goal
+type UpdateWaterGoalRequest struct {
+	Goal int `json:"goal" binding:"required,min=1,max=20"`
+}
+
 // WaterIntakeResponse is the response structure for water intake
 type WaterIntakeResponse struct {
 	ID         uint    `json:"id"`
@@ -0,0 +1,220 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"health-tracker/utils"
+)
+
+// Route documents one operation. Request and Response are zero values of the Go types
+// (e.g. models.LoginRequest{}) the schemas are generated from.
+type Route struct {
+	Summary     string
+	Description string
+	Tag         string
+	Auth        bool // requires a bearer access token
+	Request     any  // JSON request body; nil when the route takes none
+	Response    any  // the envelope's "data"; nil when the response carries no data
+	Status      int  // success status, 200 when zero
+	Paginated   bool // the response carries meta.pagination
+	Query       []Parameter
+	// Params overrides the generated path parameters by name (":id" is an integer, others strings)
+	Params []Parameter
+	// ContentType is set for non-JSON success bodies such as application/zip
+	ContentType string
+	// Bare marks responses written without the standard envelope, such as probes
+	Bare bool
+}
+
+// QueryParam describes an optional query string parameter of a simple JSON type
+func QueryParam(name, typ, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: typ}}
+}
+
+// EnumParam describes a string parameter limited to the given values
+func EnumParam(name, in, description string, values ...string) Parameter {
+	return Parameter{Name: name, In: in, Description: description, Required: in == "path", Schema: &Schema{Type: "string", Enum: values}}
+}
+
+// Builder collects documented routes into a Document
+type Builder struct {
+	doc     *Document
+	schemas *schemaGenerator
+	tags    map[string]bool
+}
+
+// NewBuilder starts a document with the shared envelope, error and security definitions
+func NewBuilder(info Info, servers ...Server) *Builder {
+	b := &Builder{
+		doc: &Document{
+			OpenAPI: "3.0.3",
+			Info:    info,
+			Servers: servers,
+			Paths:   make(map[string]*PathItem),
+		},
+		schemas: newSchemaGenerator(),
+		tags:    make(map[string]bool),
+	}
+
+	b.schemas.components["Error"] = &Schema{
+		Type:     "object",
+		Required: []string{"success", "error", "code"},
+		Properties: map[string]*Schema{
+			"success":    {Type: "boolean"},
+			"error":      {Type: "string", Description: "Human-readable message"},
+			"code":       {Type: "string", Enum: errorCodes},
+			"details":    {Type: "array", Items: b.schemas.schemaOf(utils.FieldError{}), Description: "Rejected fields, for VALIDATION_FAILED"},
+			"request_id": {Type: "string"},
+		},
+	}
+	b.doc.Components.Responses = map[string]*Response{
+		"Error": {
+			Description: "Error",
+			Content:     map[string]MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/Error"}}},
+		},
+	}
+	b.doc.Components.SecuritySchemes = map[string]SecurityScheme{
+		"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+	}
+	return b
+}
+
+var errorCodes = []string{
+	utils.CodeBadRequest, utils.CodeValidationFailed, utils.CodeUnknownField, utils.CodeBatchTooLarge,
+	utils.CodeUnauthorized, utils.CodeForbidden, utils.CodeNotFound, utils.CodeMethodNotAllowed,
+	utils.CodeConflict, utils.CodeGone, utils.CodePayloadTooLarge, utils.CodeRateLimited, utils.CodeAccountLocked, utils.CodeAccountSuspended, utils.CodeInternal,
+	utils.CodeServiceUnavailable,
+}
+
+// Add documents the operation for a Gin route such as "GET /v1/health/:id"
+func (b *Builder) Add(method, ginPath string, route Route) {
+	path, params := convertPath(ginPath, route.Params)
+
+	op := &Operation{
+		Summary:     route.Summary,
+		Description: route.Description,
+		OperationID: operationID(method, ginPath),
+		Parameters:  append(params, route.Query...),
+		Responses:   map[string]*Response{},
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+		b.tags[route.Tag] = true
+	}
+	if route.Auth {
+		op.Security = []map[string][]string{{"bearerAuth": {}}}
+	}
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: b.schemas.schemaOf(route.Request)}},
+		}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	op.Responses[strconv.Itoa(status)] = b.successResponse(route)
+	op.Responses["default"] = &Response{Ref: "#/components/responses/Error"}
+
+	item, ok := b.doc.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		b.doc.Paths[path] = item
+	}
+	(*item)[strings.ToLower(method)] = op
+}
+
+func (b *Builder) successResponse(route Route) *Response {
+	if route.ContentType != "" {
+		return &Response{
+			Description: "Success",
+			Content:     map[string]MediaType{route.ContentType: {Schema: &Schema{Type: "string", Format: "binary"}}},
+		}
+	}
+
+	data := b.schemas.schemaOf(route.Response)
+	if route.Bare {
+		if data == nil {
+			return &Response{Description: "Success"}
+		}
+		return &Response{Description: "Success", Content: map[string]MediaType{"application/json": {Schema: data}}}
+	}
+
+	envelope := &Schema{
+		Type:     "object",
+		Required: []string{"success"},
+		Properties: map[string]*Schema{
+			"success": {Type: "boolean"},
+			"message": {Type: "string"},
+		},
+	}
+	if data != nil {
+		envelope.Properties["data"] = data
+	}
+	if route.Paginated {
+		envelope.Properties["meta"] = b.schemas.schemaOf(utils.Meta{})
+	}
+	return &Response{Description: "Success", Content: map[string]MediaType{"application/json": {Schema: envelope}}}
+}
+
+// Document returns the finished document
+func (b *Builder) Document() *Document {
+	b.doc.Components.Schemas = b.schemas.components
+
+	b.doc.Tags = nil
+	for name := range b.tags {
+		b.doc.Tags = append(b.doc.Tags, Tag{Name: name})
+	}
+	sort.Slice(b.doc.Tags, func(i, j int) bool { return b.doc.Tags[i].Name < b.doc.Tags[j].Name })
+	return b.doc
+}
+
+// convertPath turns "/goals/:id/progress" into "/goals/{id}/progress" plus its path parameters
+func convertPath(ginPath string, overrides []Parameter) (string, []Parameter) {
+	segments := strings.Split(ginPath, "/")
+	var params []Parameter
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") && !strings.HasPrefix(segment, "*") {
+			continue
+		}
+		name := segment[1:]
+		segments[i] = "{" + name + "}"
+		params = append(params, pathParam(name, overrides))
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func pathParam(name string, overrides []Parameter) Parameter {
+	for _, p := range overrides {
+		if p.Name == name {
+			p.In = "path"
+			p.Required = true
+			return p
+		}
+	}
+	if name == "id" {
+		return Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "integer", Minimum: float(1)}}
+	}
+	return Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}}
+}
+
+// operationID derives a stable camelCase ID such as "getV1GoalsByIdProgress"
+func operationID(method, ginPath string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.FieldsFunc(ginPath, func(r rune) bool { return r == '/' || r == '-' }) {
+		if strings.HasPrefix(segment, ":") {
+			b.WriteString("By")
+			segment = segment[1:]
+		}
+		if segment != "" {
+			b.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,209 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaGenerator derives schemas from Go types the way encoding/json serializes them.
+// Named structs become components and are referenced with $ref.
+type schemaGenerator struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// schemaOf returns the schema for the type of v, or nil when v is nil
+func (g *schemaGenerator) schemaOf(v any) *Schema {
+	if v == nil {
+		return nil
+	}
+	return g.schemaFor(reflect.TypeOf(v))
+}
+
+func (g *schemaGenerator) schemaFor(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := g.schemaFor(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: float(0)}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		s := &Schema{Type: "object"}
+		if t.Elem().Kind() != reflect.Interface {
+			s.AdditionalProperties = g.schemaFor(t.Elem())
+		}
+		return s
+	case reflect.Struct:
+		if t == timeType {
+			return &Schema{Type: "string", Format: "date-time"}
+		}
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + g.component(t)}
+	}
+	// interface{} and anything else accept any JSON value
+	return &Schema{}
+}
+
+// component registers a named struct once and returns its component name
+func (g *schemaGenerator) component(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := g.components[name]; taken {
+		// Same type name in two packages, e.g. models.Result and export.Result
+		name = strings.ReplaceAll(t.PkgPath(), "/", "_") + "_" + name
+	}
+
+	// Register before generating fields so self-referencing types terminate
+	g.names[t] = name
+	g.components[name] = &Schema{}
+	*g.components[name] = *g.structSchema(t)
+	return name
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(s, t)
+	return s
+}
+
+func (g *schemaGenerator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		// Embedded structs without a JSON name are flattened, as encoding/json does
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(s, embedded)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := g.schemaFor(field.Type)
+		if applyBinding(prop, field.Tag.Get("binding"), field.Type) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = prop
+	}
+}
+
+// applyBinding copies validator rules onto the schema and reports whether the field is required
+func applyBinding(s *Schema, tag string, t reflect.Type) bool {
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		key, param, _ := strings.Cut(rule, "=")
+		switch key {
+		case "dive":
+			// Rules after dive apply to elements, not the field itself
+			return required
+		case "required":
+			required = true
+		case "email":
+			s.Format = "email"
+		case "oneof":
+			s.Enum = strings.Fields(param)
+		case "min", "gte":
+			setBound(s, t, param, true)
+		case "max", "lte":
+			setBound(s, t, param, false)
+		case "len":
+			setBound(s, t, param, true)
+			setBound(s, t, param, false)
+		}
+	}
+	return required
+}
+
+// setBound maps min/max onto the keyword matching the field kind: value, length or item count
+func setBound(s *Schema, t reflect.Type, param string, lower bool) {
+	if s.Ref != "" {
+		return
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return
+		}
+		if lower {
+			s.MinLength = &n
+		} else {
+			s.MaxLength = &n
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return
+		}
+		if lower {
+			s.MinItems = &n
+		} else {
+			s.MaxItems = &n
+		}
+	default:
+		f, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+		if lower {
+			s.Minimum = &f
+		} else {
+			s.Maximum = &f
+		}
+	}
+}
+
+func float(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,45 @@
+package openapi
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed ui
+var uiFiles embed.FS
+
+// uiContentSecurityPolicy relaxes the API's CSP just enough for the docs page's own files
+const uiContentSecurityPolicy = "default-src 'none'; script-src 'self'; style-src 'self'; connect-src 'self'; img-src 'self' data:; frame-ancestors 'none'; base-uri 'none'"
+
+// SpecHandler serves the document as JSON. The document is fetched and encoded on the
+// first request, so the handler can be registered before every route is known.
+func SpecHandler(document func() *Document) gin.HandlerFunc {
+	var (
+		once sync.Once
+		body []byte
+		err  error
+	)
+	return func(c *gin.Context) {
+		once.Do(func() { body, err = json.MarshalIndent(document(), "", "  ") })
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	}
+}
+
+// RegisterUI serves the embedded docs viewer under prefix; it loads ../openapi.json
+func RegisterUI(r gin.IRouter, prefix string) {
+	files, _ := fs.Sub(uiFiles, "ui")
+	group := r.Group(prefix, func(c *gin.Context) {
+		c.Header("Content-Security-Policy", uiContentSecurityPolicy)
+		c.Next()
+	})
+	group.StaticFS("/", http.FS(files))
+}
@@ -0,0 +1,117 @@
+'use strict';
+
+const methods = ['get', 'post', 'put', 'patch', 'delete'];
+let spec;
+
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  Object.entries(attrs || {}).forEach(([key, value]) => node.setAttribute(key, value));
+  children.flat().forEach((child) => node.append(child));
+  return node;
+}
+
+function resolve(schema) {
+  if (schema && schema.$ref) {
+    return spec.components.schemas[schema.$ref.split('/').pop()] || {};
+  }
+  return schema || {};
+}
+
+// example renders a schema as an indented JSON-like sketch, expanding $refs once per path
+function example(schema, seen = new Set(), depth = 0) {
+  const pad = '  '.repeat(depth);
+  if (schema && schema.$ref) {
+    const name = schema.$ref.split('/').pop();
+    if (seen.has(name)) return name;
+    schema = resolve(schema);
+    seen = new Set(seen).add(name);
+  }
+  if (!schema || !schema.type) return 'any';
+  if (schema.type === 'object' && schema.properties) {
+    const required = new Set(schema.required || []);
+    const lines = Object.entries(schema.properties).map(([key, value]) =>
+      `${pad}  "${key}"${required.has(key) ? '*' : ''}: ${example(value, seen, depth + 1)}`);
+    return `{\n${lines.join(',\n')}\n${pad}}`;
+  }
+  if (schema.type === 'object') return 'object';
+  if (schema.type === 'array') return `[${example(schema.items, seen, depth)}]`;
+  let type = schema.format ? `${schema.type} (${schema.format})` : schema.type;
+  if (schema.enum) type += ` ${schema.enum.join(' | ')}`;
+  if (schema.nullable) type += ' | null';
+  return type;
+}
+
+function parametersTable(parameters) {
+  return el('table', {},
+    el('tr', {}, el('th', {}, 'Name'), el('th', {}, 'In'), el('th', {}, 'Type'), el('th', {}, 'Description')),
+    parameters.map((p) => el('tr', {},
+      el('td', {}, p.name + (p.required ? ' *' : '')),
+      el('td', {}, p.in),
+      el('td', {}, example(p.schema)),
+      el('td', {}, p.description || ''))));
+}
+
+function operation(path, method, op) {
+  const body = el('div', { class: 'body' });
+  if (op.description) body.append(el('p', {}, op.description));
+  if (op.parameters && op.parameters.length) body.append(el('h4', {}, 'Parameters'), parametersTable(op.parameters));
+  if (op.requestBody) {
+    const [type, media] = Object.entries(op.requestBody.content)[0];
+    body.append(el('h4', {}, `Request body (${type})`), el('pre', {}, example(media.schema)));
+  }
+  Object.entries(op.responses).forEach(([status, response]) => {
+    if (response.$ref) {
+      body.append(el('h4', {}, `${status}: error`), el('pre', {}, example({ $ref: '#/components/schemas/Error' })));
+      return;
+    }
+    body.append(el('h4', {}, `${status}: ${response.description}`));
+    if (response.content) {
+      const [type, media] = Object.entries(response.content)[0];
+      body.append(el('pre', {}, type === 'application/json' ? example(media.schema) : type));
+    }
+  });
+
+  return el('details', { class: 'op', 'data-search': `${path} ${op.summary || ''}`.toLowerCase() },
+    el('summary', {},
+      el('span', { class: `method ${method}` }, method),
+      el('span', { class: 'path' }, path),
+      el('span', { class: 'muted' }, op.summary || ''),
+      op.security ? el('span', { class: 'lock' }, '🔒 bearer') : ''),
+    body);
+}
+
+function render() {
+  document.title = spec.info.title;
+  document.getElementById('title').textContent = `${spec.info.title} ${spec.info.version}`;
+
+  const groups = {};
+  Object.entries(spec.paths).sort(([a], [b]) => a.localeCompare(b)).forEach(([path, item]) => {
+    methods.filter((m) => item[m]).forEach((method) => {
+      const tag = (item[method].tags || ['Other'])[0];
+      (groups[tag] = groups[tag] || []).push(operation(path, method, item[method]));
+    });
+  });
+
+  const nav = document.getElementById('tags');
+  const main = document.getElementById('operations');
+  main.replaceChildren();
+  Object.keys(groups).sort().forEach((tag) => {
+    const id = `tag-${tag.replace(/\W+/g, '-')}`;
+    nav.append(el('a', { href: `#${id}` }, tag));
+    main.append(el('section', { id }, el('h2', {}, tag), groups[tag]));
+  });
+}
+
+document.getElementById('filter').addEventListener('input', (event) => {
+  const query = event.target.value.toLowerCase();
+  document.querySelectorAll('details.op').forEach((node) => {
+    node.hidden = !node.dataset.search.includes(query);
+  });
+});
+
+fetch('../openapi.json')
+  .then((response) => response.json())
+  .then((json) => { spec = json; render(); })
+  .catch((error) => {
+    document.getElementById('operations').textContent = `Failed to load the API description: ${error}`;
+  });
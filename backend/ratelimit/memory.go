@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval controls how often expired keys are dropped from a MemoryStore
+const sweepInterval = time.Minute
+
+// MemoryStore keeps limiter state in process memory. Expired keys are swept
+// during Allow, so it needs no background goroutine.
+type MemoryStore struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tats: make(map[string]time.Time), lastSweep: time.Now()}
+}
+
+// Allow implements Store
+func (s *MemoryStore) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	result, tat := gcra(now, s.tats[key], limit)
+	if result.Allowed {
+		s.tats[key] = tat
+	}
+	return result, nil
+}
+
+// Close implements Store
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// sweep removes keys whose bucket has fully refilled; a missing key behaves the same
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, tat := range s.tats {
+		if !tat.After(now) {
+			delete(s.tats, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,127 @@
+// Package ratelimit implements GCRA (generic cell rate algorithm) rate limiting,
+// a token bucket that stores a single timestamp per key, over a pluggable Store.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Rate requests per Period, with up to Burst requests at once
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+// ParseLimit reads limits written as "100/1m" or, with an explicit burst, "100/1m:20"
+func ParseLimit(value string) (Limit, error) {
+	spec, burstSpec, hasBurst := strings.Cut(strings.TrimSpace(value), ":")
+	rateSpec, periodSpec, ok := strings.Cut(spec, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected e.g. 100/1m", value)
+	}
+
+	rate, err := strconv.Atoi(rateSpec)
+	if err != nil || rate <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate in %q", value)
+	}
+	period, err := time.ParseDuration(periodSpec)
+	if err != nil || period <= 0 {
+		return Limit{}, fmt.Errorf("invalid period in %q", value)
+	}
+
+	burst := rate
+	if hasBurst {
+		burst, err = strconv.Atoi(burstSpec)
+		if err != nil || burst <= 0 {
+			return Limit{}, fmt.Errorf("invalid burst in %q", value)
+		}
+	}
+	return Limit{Rate: rate, Period: period, Burst: burst}, nil
+}
+
+// String formats the limit the way ParseLimit reads it
+func (l Limit) String() string {
+	s := strconv.Itoa(l.Rate) + "/" + l.Period.String()
+	if l.Burst != l.Rate {
+		s += ":" + strconv.Itoa(l.Burst)
+	}
+	return s
+}
+
+// interval is the time it takes to earn back one request
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Rate)
+}
+
+// Result is the outcome of one Allow call
+type Result struct {
+	Allowed   bool
+	Limit     Limit
+	Remaining int
+	// RetryAfter is how long a rejected caller must wait; zero when allowed
+	RetryAfter time.Duration
+	// ResetAfter is how long until the full burst is available again
+	ResetAfter time.Duration
+}
+
+// Store keeps limiter state. Allow must check and consume atomically for a key,
+// including across processes for shared stores.
+type Store interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+	Close() error
+}
+
+// gcra applies one request at now to a key whose theoretical arrival time is tat.
+// It returns the result and the new tat to store when the request is allowed.
+func gcra(now, tat time.Time, limit Limit) (Result, time.Time) {
+	interval := limit.interval()
+	if tat.Before(now) {
+		tat = now
+	}
+
+	newTAT := tat.Add(interval)
+	allowAt := newTAT.Add(-time.Duration(limit.Burst) * interval)
+	if now.Before(allowAt) {
+		return Result{
+			Limit:      limit,
+			RetryAfter: allowAt.Sub(now),
+			ResetAfter: tat.Sub(now),
+		}, tat
+	}
+
+	return Result{
+		Allowed:    true,
+		Limit:      limit,
+		Remaining:  int(now.Sub(allowAt) / interval),
+		ResetAfter: newTAT.Sub(now),
+	}, newTAT
+}
+
+// NewStore builds the store named by driver: "memory" (default) or "redis".
+// Memory limits are per process; use redis when running more than one instance.
+func NewStore(ctx context.Context, driver, redisURL string) (Store, error) {
+	switch driver {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	case "redis":
+		if redisURL == "" {
+			return nil, fmt.Errorf("REDIS_URL is required for the redis rate limit store")
+		}
+		store, err := NewRedisStoreFromURL(redisURL)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.client.Ping(ctx).Err(); err != nil {
+			store.Close()
+			return nil, fmt.Errorf("cannot reach redis: %w", err)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unsupported RATE_LIMIT_STORE %q (use memory or redis)", driver)
+	}
+}
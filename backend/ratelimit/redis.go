@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// gcraScript is gcra() in Lua so the check and update run atomically inside Redis.
+// Times are Unix milliseconds; the key expires once its bucket has fully refilled.
+var gcraScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local burst = tonumber(ARGV[3])
+
+local tat = tonumber(redis.call("GET", KEYS[1]))
+if not tat or tat < now then
+	tat = now
+end
+
+local new_tat = tat + interval
+local allow_at = new_tat - burst * interval
+if now < allow_at then
+	return {0, 0, allow_at - now, tat - now}
+end
+
+redis.call("SET", KEYS[1], new_tat, "PX", new_tat - now)
+return {1, math.floor((now - allow_at) / interval), 0, new_tat - now}
+`)
+
+// RedisStore shares limiter state between instances through Redis or any server
+// that speaks the Redis protocol and supports EVAL (Valkey, KeyDB, miniredis).
+type RedisStore struct {
+	client redis.UniversalClient
+}
+
+// NewRedisStore wraps an existing client
+func NewRedisStore(client redis.UniversalClient) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// NewRedisStoreFromURL connects using a redis:// or rediss:// URL
+func NewRedisStoreFromURL(url string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	return NewRedisStore(redis.NewClient(opts)), nil
+}
+
+// Allow implements Store
+func (s *RedisStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now().UnixMilli()
+	interval := limit.interval().Milliseconds()
+	if interval < 1 {
+		interval = 1
+	}
+
+	values, err := gcraScript.Run(ctx, s.client, []string{key}, now, interval, limit.Burst).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
+
+// Close implements Store
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedisStore(t *testing.T) (*RedisStore, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	store := NewRedisStore(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	t.Cleanup(func() { store.Close() })
+	return store, server
+}
+
+func TestRedisStoreAllowsBurstThenRejects(t *testing.T) {
+	store, _ := newTestRedisStore(t)
+	ctx := context.Background()
+	limit := Limit{Rate: 3, Period: time.Minute, Burst: 3}
+
+	for i := 0; i < limit.Burst; i++ {
+		result, err := store.Allow(ctx, "k", limit)
+		if err != nil {
+			t.Fatalf("Allow #%d: %v", i+1, err)
+		}
+		if !result.Allowed {
+			t.Fatalf("request %d rejected within the burst", i+1)
+		}
+		if want := limit.Burst - i - 1; result.Remaining != want {
+			t.Errorf("request %d: remaining = %d, want %d", i+1, result.Remaining, want)
+		}
+	}
+
+	result, err := store.Allow(ctx, "k", limit)
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if result.Allowed {
+		t.Fatal("request beyond the burst was allowed")
+	}
+	// One request is earned back every 20s; the first of the burst was spent moments ago
+	if result.RetryAfter <= 19*time.Second || result.RetryAfter > 20*time.Second {
+		t.Errorf("retry after = %v, want just under 20s", result.RetryAfter)
+	}
+	if result.ResetAfter <= 59*time.Second || result.ResetAfter > time.Minute {
+		t.Errorf("reset after = %v, want just under 1m", result.ResetAfter)
+	}
+}
+
+func TestRedisStoreKeysAreIndependent(t *testing.T) {
+	store, _ := newTestRedisStore(t)
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Period: time.Minute, Burst: 1}
+
+	for _, key := range []string{"a", "b"} {
+		result, err := store.Allow(ctx, key, limit)
+		if err != nil {
+			t.Fatalf("Allow(%s): %v", key, err)
+		}
+		if !result.Allowed {
+			t.Errorf("first request for %s rejected", key)
+		}
+	}
+}
+
+func TestRedisStoreExpiresRefilledKeys(t *testing.T) {
+	store, server := newTestRedisStore(t)
+	ctx := context.Background()
+	limit := Limit{Rate: 2, Period: time.Minute, Burst: 2}
+
+	if _, err := store.Allow(ctx, "k", limit); err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	ttl := server.TTL("k")
+	if ttl <= 0 || ttl > 30*time.Second {
+		t.Errorf("ttl = %v, want the 30s it takes to refill", ttl)
+	}
+
+	server.FastForward(ttl)
+	if server.Exists("k") {
+		t.Error("key still exists after its bucket refilled")
+	}
+}
+
+// The Lua script and the in-memory gcra must agree on every outcome
+func TestRedisStoreMatchesMemoryStore(t *testing.T) {
+	store, _ := newTestRedisStore(t)
+	memory := NewMemoryStore()
+	defer memory.Close()
+	ctx := context.Background()
+	limit := Limit{Rate: 5, Period: time.Hour, Burst: 3}
+
+	for i := 0; i < 6; i++ {
+		got, err := store.Allow(ctx, "k", limit)
+		if err != nil {
+			t.Fatalf("redis Allow #%d: %v", i+1, err)
+		}
+		want, _ := memory.Allow(ctx, "k", limit)
+		if got.Allowed != want.Allowed || got.Remaining != want.Remaining {
+			t.Errorf("request %d: redis = (%v, %d), memory = (%v, %d)",
+				i+1, got.Allowed, got.Remaining, want.Allowed, want.Remaining)
+		}
+	}
+}
+
+func TestRedisStoreReportsUnreachableServer(t *testing.T) {
+	store, server := newTestRedisStore(t)
+	server.Close()
+
+	if _, err := store.Allow(context.Background(), "k", Limit{Rate: 1, Period: time.Second, Burst: 1}); err == nil {
+		t.Fatal("Allow succeeded without a server")
+	}
+}
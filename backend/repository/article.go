@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// ArticleRepository reads health articles
+type ArticleRepository interface {
+	// List returns articles newest first; an empty category returns all of them
+	List(ctx context.Context, category string) ([]models.Article, error)
+	FindByID(ctx context.Context, id uint) (*models.Article, error)
+	Search(ctx context.Context, keyword string) ([]models.Article, error)
+	Paginate(ctx context.Context, category string, offset, limit int) ([]models.Article, int64, error)
+}
+
+type articleRepository struct {
+	db *gorm.DB
+}
+
+func (r *articleRepository) byCategory(ctx context.Context, category string) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.Article{})
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	return query
+}
+
+func (r *articleRepository) List(ctx context.Context, category string) ([]models.Article, error) {
+	var articles []models.Article
+	err := r.byCategory(ctx, category).Order("created_at DESC").Find(&articles).Error
+	return articles, err
+}
+
+func (r *articleRepository) FindByID(ctx context.Context, id uint) (*models.Article, error) {
+	var article models.Article
+	if err := r.db.WithContext(ctx).First(&article, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &article, nil
+}
+
+func (r *articleRepository) Search(ctx context.Context, keyword string) ([]models.Article, error) {
+	pattern := "%" + keyword + "%"
+
+	var articles []models.Article
+	err := r.db.WithContext(ctx).Where("title LIKE ? OR content LIKE ?", pattern, pattern).
+		Order("created_at DESC").Find(&articles).Error
+	return articles, err
+}
+
+func (r *articleRepository) Paginate(ctx context.Context, category string, offset, limit int) ([]models.Article, int64, error) {
+	var total int64
+	if err := r.byCategory(ctx, category).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var articles []models.Article
+	err := r.byCategory(ctx, category).Order("created_at DESC").Offset(offset).Limit(limit).Find(&articles).Error
+	return articles, total, err
+}
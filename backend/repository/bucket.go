@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// BucketQuery selects the rows in [From, To) and groups them into day, week or month
+// buckets of the given location's calendar
+type BucketQuery struct {
+	Bucket   string
+	From     time.Time
+	To       time.Time
+	Location *time.Location
+}
+
+// unit returns the bucket size, falling back to days; it is interpolated into SQL so only
+// known values may pass
+func (q BucketQuery) unit() string {
+	switch q.Bucket {
+	case models.BucketWeek, models.BucketMonth:
+		return q.Bucket
+	}
+	return models.BucketDay
+}
+
+// timeBucket returns the SQL expression for the bucket of a timestamp column, as the
+// bucket's first day in YYYY-MM-DD form, and its arguments
+func timeBucket(db *gorm.DB, column string, q BucketQuery) (string, []any) {
+	if db.Dialector.Name() == "postgres" {
+		return fmt.Sprintf("to_char(date_trunc('%s', %s AT TIME ZONE ?), 'YYYY-MM-DD')", q.unit(), column), []any{q.Location.String()}
+	}
+
+	// SQLite has no time zone database, so each row is shifted by the offset its zone had
+	// at the time: one CASE branch per offset period in the range, split at the zone's
+	// transitions. Stored times are UTC text, so they compare correctly with the bounds.
+	var expr string
+	switch q.unit() {
+	case models.BucketWeek:
+		expr = fmt.Sprintf("date(%s, ?, 'weekday 0', '-6 days')", column)
+	case models.BucketMonth:
+		expr = fmt.Sprintf("strftime('%%Y-%%m-01', %s, ?)", column)
+	default:
+		expr = fmt.Sprintf("date(%s, ?)", column)
+	}
+
+	periods := offsetPeriods(q.From, q.To, q.Location)
+	if len(periods) == 1 {
+		return expr, []any{periods[0].shift()}
+	}
+
+	var sql strings.Builder
+	var args []any
+	sql.WriteString("CASE")
+	for _, p := range periods[:len(periods)-1] {
+		sql.WriteString(fmt.Sprintf(" WHEN %s < ? THEN %s", column, expr))
+		args = append(args, p.until, p.shift())
+	}
+	sql.WriteString(" ELSE " + expr + " END")
+	args = append(args, periods[len(periods)-1].shift())
+	return sql.String(), args
+}
+
+// offsetPeriod is a stretch of time during which a zone keeps the same UTC offset
+type offsetPeriod struct {
+	until  time.Time // exclusive, in UTC; zero for the last period
+	offset int       // seconds east of UTC
+}
+
+// shift returns the SQLite date modifier that moves a UTC time into the period's offset
+func (p offsetPeriod) shift() string {
+	return fmt.Sprintf("%+d seconds", p.offset)
+}
+
+// offsetPeriods splits [from, to) at loc's offset changes, such as daylight saving time
+func offsetPeriods(from, to time.Time, loc *time.Location) []offsetPeriod {
+	var periods []offsetPeriod
+	t := from.In(loc)
+	for {
+		_, offset := t.Zone()
+		_, end := t.ZoneBounds()
+		if end.IsZero() || !end.Before(to) {
+			end = time.Time{}
+		} else {
+			end = end.UTC()
+		}
+
+		// Zones can change abbreviation without changing offset
+		if n := len(periods); n > 0 && periods[n-1].offset == offset {
+			periods[n-1].until = end
+		} else {
+			periods = append(periods, offsetPeriod{until: end, offset: offset})
+		}
+		if end.IsZero() {
+			return periods
+		}
+		t = end.In(loc)
+	}
+}
+
+// dateBucket is timeBucket for YYYY-MM-DD text columns, which are already local dates
+func dateBucket(db *gorm.DB, column string, q BucketQuery) string {
+	if db.Dialector.Name() == "postgres" {
+		return fmt.Sprintf("to_char(date_trunc('%s', CAST(%s AS date)), 'YYYY-MM-DD')", q.unit(), column)
+	}
+
+	switch q.unit() {
+	case models.BucketWeek:
+		return fmt.Sprintf("date(%s, 'weekday 0', '-6 days')", column)
+	case models.BucketMonth:
+		return fmt.Sprintf("strftime('%%Y-%%m-01', %s)", column)
+	}
+	return fmt.Sprintf("date(%s)", column)
+}
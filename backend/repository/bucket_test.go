@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"health-tracker/database"
+	"health-tracker/models"
+)
+
+func TestOffsetPeriods(t *testing.T) {
+	eastern, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, eastern)
+
+	if periods := offsetPeriods(from, from.AddDate(1, 0, 0), jakarta); len(periods) != 1 || periods[0].offset != 7*60*60 {
+		t.Errorf("fixed zone periods = %+v, want one at +7h", periods)
+	}
+
+	periods := offsetPeriods(from, from.AddDate(1, 0, 0), eastern)
+	want := []offsetPeriod{
+		{until: time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC), offset: -5 * 60 * 60},
+		{until: time.Date(2026, 11, 1, 6, 0, 0, 0, time.UTC), offset: -4 * 60 * 60},
+		{offset: -5 * 60 * 60},
+	}
+	if len(periods) != len(want) {
+		t.Fatalf("periods = %+v, want %+v", periods, want)
+	}
+	for i := range want {
+		if !periods[i].until.Equal(want[i].until) || periods[i].offset != want[i].offset {
+			t.Errorf("period %d = %+v, want %+v", i, periods[i], want[i])
+		}
+	}
+}
+
+func TestHealthBucketsAcrossDaylightSavingTime(t *testing.T) {
+	eastern, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	db := openTestDB(t)
+	if _, err := database.MigrateUp(db, "sqlite"); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := db.Create(&models.User{ID: 1, Email: "ana@example.com", Name: "Ana", Password: "!"}).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	// Half past midnight local time before and after clocks went forward on 8 March
+	for i, at := range []time.Time{
+		time.Date(2026, 3, 2, 0, 30, 0, 0, eastern),
+		time.Date(2026, 3, 10, 0, 30, 0, 0, eastern),
+		time.Date(2026, 3, 10, 23, 30, 0, 0, eastern),
+	} {
+		record := models.HealthData{UserID: 1, WeightKg: 70 + float64(i), HeightCm: 170, RecordDate: at.UTC()}
+		if err := db.Create(&record).Error; err != nil {
+			t.Fatalf("create record: %v", err)
+		}
+	}
+
+	repo := &healthRepository{db: db}
+	rows, err := repo.Buckets(context.Background(), 1, BucketQuery{
+		Bucket:   models.BucketDay,
+		From:     time.Date(2026, 3, 1, 0, 0, 0, 0, eastern),
+		To:       time.Date(2026, 3, 15, 0, 0, 0, 0, eastern),
+		Location: eastern,
+	})
+	if err != nil {
+		t.Fatalf("Buckets: %v", err)
+	}
+
+	got := map[string]int{}
+	for _, row := range rows {
+		got[row.Bucket] = int(row.Count)
+	}
+	want := map[string]int{"2026-03-02": 1, "2026-03-10": 2}
+	if len(got) != len(want) {
+		t.Fatalf("buckets = %v, want %v", got, want)
+	}
+	for day, count := range want {
+		if got[day] != count {
+			t.Errorf("bucket %s has %d records, want %d (all: %v)", day, got[day], count, got)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"health-tracker/export"
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// ExportRepository reads and restores personal data archives and tracks background export jobs
+type ExportRepository interface {
+	CountRows(ctx context.Context, userID uint) (int64, error)
+	WriteArchive(ctx context.Context, userID uint, w io.Writer) error
+	// ImportArchive restores an archive in a single transaction
+	ImportArchive(ctx context.Context, userID uint, archive []byte) (*export.ImportResult, error)
+	// HasHealthData reports whether the user already has records an import would duplicate
+	HasHealthData(ctx context.Context, userID uint) (bool, error)
+
+	CreateJob(ctx context.Context, job *models.ExportJob) error
+	FindJob(ctx context.Context, id, userID uint) (*models.ExportJob, error)
+	FindCompletedJob(ctx context.Context, id uint) (*models.ExportJob, error)
+	UpdateJob(ctx context.Context, id uint, fields map[string]interface{}) error
+	ListExpiredJobs(ctx context.Context, now time.Time) ([]models.ExportJob, error)
+}
+
+type exportRepository struct {
+	db *gorm.DB
+}
+
+func (r *exportRepository) CountRows(ctx context.Context, userID uint) (int64, error) {
+	return export.CountRows(r.db.WithContext(ctx), userID)
+}
+
+func (r *exportRepository) WriteArchive(ctx context.Context, userID uint, w io.Writer) error {
+	return export.WriteArchive(r.db.WithContext(ctx), userID, w)
+}
+
+func (r *exportRepository) ImportArchive(ctx context.Context, userID uint, archive []byte) (*export.ImportResult, error) {
+	var result *export.ImportResult
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		result, err = export.ImportArchive(tx, userID, archive)
+		return err
+	})
+	return result, err
+}
+
+func (r *exportRepository) HasHealthData(ctx context.Context, userID uint) (bool, error) {
+	for _, model := range []interface{}{&models.HealthData{}, &models.Measurement{}, &models.Symptom{}, &models.WaterIntake{}, &models.Goal{}} {
+		var count int64
+		if err := r.db.WithContext(ctx).Model(model).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *exportRepository) CreateJob(ctx context.Context, job *models.ExportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *exportRepository) FindJob(ctx context.Context, id, userID uint) (*models.ExportJob, error) {
+	var job models.ExportJob
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&job).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &job, nil
+}
+
+func (r *exportRepository) FindCompletedJob(ctx context.Context, id uint) (*models.ExportJob, error) {
+	var job models.ExportJob
+	if err := r.db.WithContext(ctx).Where("id = ? AND status = ?", id, models.ExportStatusCompleted).First(&job).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &job, nil
+}
+
+func (r *exportRepository) UpdateJob(ctx context.Context, id uint, fields map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&models.ExportJob{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *exportRepository) ListExpiredJobs(ctx context.Context, now time.Time) ([]models.ExportJob, error) {
+	var jobs []models.ExportJob
+	err := r.db.WithContext(ctx).Where("status = ? AND expires_at < ? AND file_path <> ?", models.ExportStatusCompleted, now, "").Find(&jobs).Error
+	return jobs, err
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// FamilyRepository stores family links and invitations
+type FamilyRepository interface {
+	Create(ctx context.Context, link *models.FamilyMember) error
+	Save(ctx context.Context, link *models.FamilyMember) error
+	// Find returns the link from owner to member in any status
+	Find(ctx context.Context, ownerID, memberUserID uint) (*models.FamilyMember, error)
+	FindPendingInvitation(ctx context.Context, id, memberUserID uint) (*models.FamilyMember, error)
+	// FindViewable returns an approved link owned by ownerID that allows viewing health data
+	FindViewable(ctx context.Context, id, ownerID uint) (*models.FamilyMember, error)
+	ListByOwner(ctx context.Context, ownerID uint, status string) ([]models.FamilyMember, error)
+	ListByMember(ctx context.Context, memberUserID uint, status string) ([]models.FamilyMember, error)
+	// Delete removes the link from owner to member and returns how many rows were deleted
+	Delete(ctx context.Context, ownerID, memberUserID uint) (int64, error)
+}
+
+type familyRepository struct {
+	db *gorm.DB
+}
+
+func (r *familyRepository) Create(ctx context.Context, link *models.FamilyMember) error {
+	return r.db.WithContext(ctx).Create(link).Error
+}
+
+func (r *familyRepository) Save(ctx context.Context, link *models.FamilyMember) error {
+	return r.db.WithContext(ctx).Save(link).Error
+}
+
+func (r *familyRepository) Find(ctx context.Context, ownerID, memberUserID uint) (*models.FamilyMember, error) {
+	var link models.FamilyMember
+	if err := r.db.WithContext(ctx).Where("owner_id = ? AND member_user_id = ?", ownerID, memberUserID).First(&link).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &link, nil
+}
+
+func (r *familyRepository) FindPendingInvitation(ctx context.Context, id, memberUserID uint) (*models.FamilyMember, error) {
+	var link models.FamilyMember
+	if err := r.db.WithContext(ctx).Where("id = ? AND member_user_id = ? AND status = ?", id, memberUserID, "pending").First(&link).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &link, nil
+}
+
+func (r *familyRepository) FindViewable(ctx context.Context, id, ownerID uint) (*models.FamilyMember, error) {
+	var link models.FamilyMember
+	if err := r.db.WithContext(ctx).Where("id = ? AND owner_id = ? AND status = ? AND can_view_health = ?",
+		id, ownerID, "approved", true).First(&link).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &link, nil
+}
+
+func (r *familyRepository) ListByOwner(ctx context.Context, ownerID uint, status string) ([]models.FamilyMember, error) {
+	var links []models.FamilyMember
+	err := r.db.WithContext(ctx).Where("owner_id = ? AND status = ?", ownerID, status).Find(&links).Error
+	return links, err
+}
+
+func (r *familyRepository) ListByMember(ctx context.Context, memberUserID uint, status string) ([]models.FamilyMember, error) {
+	var links []models.FamilyMember
+	err := r.db.WithContext(ctx).Where("member_user_id = ? AND status = ?", memberUserID, status).Find(&links).Error
+	return links, err
+}
+
+func (r *familyRepository) Delete(ctx context.Context, ownerID, memberUserID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Where("owner_id = ? AND member_user_id = ?", ownerID, memberUserID).Delete(&models.FamilyMember{})
+	return result.RowsAffected, result.Error
+}
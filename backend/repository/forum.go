@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// ForumRepository stores forum posts, comments and likes
+type ForumRepository interface {
+	// ListPosts returns every post with its author, newest first
+	ListPosts(ctx context.Context) ([]models.Post, error)
+	// FindPost returns a post with its author and, optionally, its comments and their authors
+	FindPost(ctx context.Context, id uint, withComments bool) (*models.Post, error)
+	// CreatePost stores a post and loads its author
+	CreatePost(ctx context.Context, post *models.Post) error
+	// DeletePost removes a post together with its comments and likes
+	DeletePost(ctx context.Context, id uint) error
+	// AddComment stores a comment, bumps the post's comment counter and loads the author
+	AddComment(ctx context.Context, comment *models.Comment) error
+	// LikedPostIDs reports which of the given posts the user has liked
+	LikedPostIDs(ctx context.Context, userID uint, postIDs []uint) (map[uint]bool, error)
+	// ToggleLike likes or unlikes a post and returns the new state and like count
+	ToggleLike(ctx context.Context, postID, userID uint) (bool, int, error)
+}
+
+type forumRepository struct {
+	db *gorm.DB
+}
+
+func (r *forumRepository) ListPosts(ctx context.Context) ([]models.Post, error) {
+	var posts []models.Post
+	err := r.db.WithContext(ctx).Preload("User").Order("created_at DESC").Find(&posts).Error
+	return posts, err
+}
+
+func (r *forumRepository) FindPost(ctx context.Context, id uint, withComments bool) (*models.Post, error) {
+	query := r.db.WithContext(ctx).Preload("User")
+	if withComments {
+		query = query.Preload("Comments.User")
+	}
+
+	var post models.Post
+	if err := query.First(&post, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &post, nil
+}
+
+func (r *forumRepository) CreatePost(ctx context.Context, post *models.Post) error {
+	if err := r.db.WithContext(ctx).Create(post).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Preload("User").First(post, post.ID).Error
+}
+
+func (r *forumRepository) DeletePost(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("post_id = ?", id).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("post_id = ?", id).Delete(&models.Like{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Post{}, id).Error
+	})
+}
+
+func (r *forumRepository) AddComment(ctx context.Context, comment *models.Comment) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Post{}).Where("id = ?", comment.PostID).
+			Update("comments_count", gorm.Expr("comments_count + 1")).Error
+	})
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Preload("User").First(comment, comment.ID).Error
+}
+
+func (r *forumRepository) LikedPostIDs(ctx context.Context, userID uint, postIDs []uint) (map[uint]bool, error) {
+	liked := make(map[uint]bool)
+	if len(postIDs) == 0 {
+		return liked, nil
+	}
+
+	var ids []uint
+	if err := r.db.WithContext(ctx).Model(&models.Like{}).
+		Where("user_id = ? AND post_id IN ?", userID, postIDs).
+		Pluck("post_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked, nil
+}
+
+func (r *forumRepository) ToggleLike(ctx context.Context, postID, userID uint) (bool, int, error) {
+	isLiked := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.Like
+		err := tx.Where("post_id = ? AND user_id = ?", postID, userID).First(&existing).Error
+		switch {
+		case err == nil:
+			// Unlike - remove existing like
+			if err := tx.Delete(&existing).Error; err != nil {
+				return err
+			}
+			return tx.Model(&models.Post{}).Where("id = ?", postID).
+				Update("likes_count", gorm.Expr("likes_count - 1")).Error
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			// Like - add new like
+			isLiked = true
+			if err := tx.Create(&models.Like{PostID: postID, UserID: userID}).Error; err != nil {
+				return err
+			}
+			return tx.Model(&models.Post{}).Where("id = ?", postID).
+				Update("likes_count", gorm.Expr("likes_count + 1")).Error
+		default:
+			return err
+		}
+	})
+	if err != nil {
+		return false, 0, err
+	}
+
+	var post models.Post
+	if err := r.db.WithContext(ctx).Select("likes_count").First(&post, postID).Error; err != nil {
+		return false, 0, translate(err)
+	}
+	return isLiked, post.LikesCount, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// GoalRepository stores personal goals
+type GoalRepository interface {
+	ListByUser(ctx context.Context, userID uint) ([]models.Goal, error)
+	Find(ctx context.Context, id, userID uint) (*models.Goal, error)
+	Create(ctx context.Context, goal *models.Goal) error
+	Save(ctx context.Context, goal *models.Goal) error
+	Delete(ctx context.Context, goal *models.Goal) error
+	// Counts returns the total number of goals and how many are completed
+	Counts(ctx context.Context, userID uint) (int64, int64, error)
+}
+
+type goalRepository struct {
+	db *gorm.DB
+}
+
+func (r *goalRepository) ListByUser(ctx context.Context, userID uint) ([]models.Goal, error) {
+	var goals []models.Goal
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&goals).Error
+	return goals, err
+}
+
+func (r *goalRepository) Find(ctx context.Context, id, userID uint) (*models.Goal, error) {
+	var goal models.Goal
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&goal).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &goal, nil
+}
+
+func (r *goalRepository) Create(ctx context.Context, goal *models.Goal) error {
+	return r.db.WithContext(ctx).Create(goal).Error
+}
+
+func (r *goalRepository) Save(ctx context.Context, goal *models.Goal) error {
+	return r.db.WithContext(ctx).Save(goal).Error
+}
+
+func (r *goalRepository) Delete(ctx context.Context, goal *models.Goal) error {
+	return r.db.WithContext(ctx).Delete(goal).Error
+}
+
+func (r *goalRepository) Counts(ctx context.Context, userID uint) (int64, int64, error) {
+	var total, completed int64
+	if err := r.db.WithContext(ctx).Model(&models.Goal{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		return 0, 0, err
+	}
+	if err := r.db.WithContext(ctx).Model(&models.Goal{}).Where("user_id = ? AND is_completed = ?", userID, true).Count(&completed).Error; err != nil {
+		return 0, 0, err
+	}
+	return total, completed, nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// HealthRepository stores health records
+type HealthRepository interface {
+	Create(ctx context.Context, record *models.HealthData) error
+	// Find returns one of the user's records or ErrNotFound
+	Find(ctx context.Context, id, userID uint) (*models.HealthData, error)
+	Save(ctx context.Context, record *models.HealthData) error
+	Delete(ctx context.Context, record *models.HealthData) error
+	// List returns one page of the user's records matching the filter, and the cursor
+	// of the next page if there is one
+	List(ctx context.Context, userID uint, filter HealthFilter, page PageRequest) ([]models.HealthData, *Cursor, error)
+	// Latest returns the most recent record or ErrNotFound
+	Latest(ctx context.Context, userID uint) (*models.HealthData, error)
+	Recent(ctx context.Context, userID uint, limit int) ([]models.HealthData, error)
+	CountByUser(ctx context.Context, userID uint) (int64, error)
+	// ListSince returns records from the given time onwards, oldest first
+	ListSince(ctx context.Context, userID uint, since time.Time) ([]models.HealthData, error)
+	// Buckets aggregates weight and BMI per bucket, skipping buckets without records
+	Buckets(ctx context.Context, userID uint, q BucketQuery) ([]models.HealthBucketRow, error)
+	// EmotionalStates counts the emotional states recorded per bucket
+	EmotionalStates(ctx context.Context, userID uint, q BucketQuery) ([]models.BucketCount, error)
+}
+
+// HealthFilter narrows a health record listing; zero values match everything
+type HealthFilter struct {
+	From           time.Time // inclusive
+	To             time.Time // exclusive
+	EmotionalState string
+}
+
+// HealthSortFields are the columns health records can be sorted by
+var HealthSortFields = map[string]SortField{
+	"record_date": {Column: "record_date", Time: true},
+	"weight_kg":   {Column: "weight_kg"},
+	"bmi":         {Column: "bmi"},
+}
+
+type healthRepository struct {
+	db *gorm.DB
+}
+
+func (r *healthRepository) Create(ctx context.Context, record *models.HealthData) error {
+	return r.db.WithContext(ctx).Create(record).Error
+}
+
+func (r *healthRepository) Find(ctx context.Context, id, userID uint) (*models.HealthData, error) {
+	var record models.HealthData
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&record).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &record, nil
+}
+
+func (r *healthRepository) Save(ctx context.Context, record *models.HealthData) error {
+	return r.db.WithContext(ctx).Save(record).Error
+}
+
+func (r *healthRepository) Delete(ctx context.Context, record *models.HealthData) error {
+	return r.db.WithContext(ctx).Delete(record).Error
+}
+
+func (r *healthRepository) List(ctx context.Context, userID uint, filter HealthFilter, page PageRequest) ([]models.HealthData, *Cursor, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if !filter.From.IsZero() {
+		query = query.Where("record_date >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("record_date < ?", filter.To.UTC())
+	}
+	if filter.EmotionalState != "" {
+		query = query.Where("emotional_state = ?", filter.EmotionalState)
+	}
+
+	query, err := applyPage(query, HealthSortFields, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var records []models.HealthData
+	if err := query.Find(&records).Error; err != nil {
+		return nil, nil, err
+	}
+	records, next := nextCursor(records, page, func(record models.HealthData) (any, uint) {
+		switch page.Sort {
+		case "weight_kg":
+			return record.WeightKg, record.ID
+		case "bmi":
+			return record.BMI, record.ID
+		}
+		return record.RecordDate, record.ID
+	})
+	return records, next, nil
+}
+
+func (r *healthRepository) Latest(ctx context.Context, userID uint) (*models.HealthData, error) {
+	var record models.HealthData
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("record_date desc, id desc").First(&record).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &record, nil
+}
+
+func (r *healthRepository) Recent(ctx context.Context, userID uint, limit int) ([]models.HealthData, error) {
+	var records []models.HealthData
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("record_date desc, id desc").Limit(limit).Find(&records).Error
+	return records, err
+}
+
+func (r *healthRepository) CountByUser(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.HealthData{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *healthRepository) ListSince(ctx context.Context, userID uint, since time.Time) ([]models.HealthData, error) {
+	var records []models.HealthData
+	err := r.db.WithContext(ctx).Where("user_id = ? AND record_date >= ?", userID, since.UTC()).Order("record_date asc, id asc").Find(&records).Error
+	return records, err
+}
+
+func (r *healthRepository) Buckets(ctx context.Context, userID uint, q BucketQuery) ([]models.HealthBucketRow, error) {
+	bucket, args := timeBucket(r.db, "record_date", q)
+	args = append(args, userID, q.From.UTC(), q.To.UTC())
+
+	// rn = 1 marks the latest record of each bucket, whose values become "last"
+	var rows []models.HealthBucketRow
+	err := r.db.WithContext(ctx).Raw(`SELECT bucket, COUNT(*) AS count,
+			MIN(weight_kg) AS min_weight, MAX(weight_kg) AS max_weight, AVG(weight_kg) AS avg_weight,
+			MAX(CASE WHEN rn = 1 THEN weight_kg END) AS last_weight,
+			MIN(bmi) AS min_bmi, MAX(bmi) AS max_bmi, AVG(bmi) AS avg_bmi,
+			MAX(CASE WHEN rn = 1 THEN bmi END) AS last_bmi
+		FROM (
+			SELECT b.*, ROW_NUMBER() OVER (PARTITION BY bucket ORDER BY record_date DESC, id DESC) AS rn
+			FROM (
+				SELECT `+bucket+` AS bucket, id, record_date, weight_kg, bmi
+				FROM health_data WHERE user_id = ? AND record_date >= ? AND record_date < ?
+			) b
+		) ranked
+		GROUP BY bucket ORDER BY bucket`, args...).Scan(&rows).Error
+	return rows, err
+}
+
+func (r *healthRepository) EmotionalStates(ctx context.Context, userID uint, q BucketQuery) ([]models.BucketCount, error) {
+	bucket, args := timeBucket(r.db, "record_date", q)
+	args = append(args, userID, q.From.UTC(), q.To.UTC())
+
+	var rows []models.BucketCount
+	err := r.db.WithContext(ctx).Raw(`SELECT bucket, emotional_state AS label, COUNT(*) AS count
+		FROM (
+			SELECT `+bucket+` AS bucket, emotional_state
+			FROM health_data WHERE user_id = ? AND record_date >= ? AND record_date < ? AND emotional_state <> ''
+		) b
+		GROUP BY bucket, emotional_state ORDER BY bucket`, args...).Scan(&rows).Error
+	return rows, err
+}
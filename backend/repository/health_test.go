@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"health-tracker/database"
+	"health-tracker/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB returns an empty in-memory SQLite database
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	return db
+}
+
+var (
+	jakarta  = time.FixedZone("WIB", 7*60*60)
+	newYork  = time.FixedZone("EST", -5*60*60)
+	testBase = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// newMixedOffsetDB returns a SQLite database holding six health records an hour apart,
+// written with different offsets the way rows were stored before times were kept in UTC,
+// and then migrated to the latest schema. Record i is at testBase + i hours.
+func newMixedOffsetDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := openTestDB(t)
+	if _, err := database.MigrateTo(db, "sqlite", 5); err != nil {
+		t.Fatalf("migrate to 5: %v", err)
+	}
+	// Columns added after version 5 don't exist yet, so the user is inserted by hand
+	if err := db.Exec("INSERT INTO users (id, email, name, password) VALUES (1, 'ana@example.com', 'Ana', '!')").Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	zones := []*time.Location{jakarta, newYork, time.UTC}
+	for i := 0; i < 6; i++ {
+		at := testBase.Add(time.Duration(i) * time.Hour).In(zones[i%len(zones)])
+		record := models.HealthData{UserID: 1, WeightKg: 70 + float64(i), HeightCm: 170, RecordDate: at}
+		if err := db.Create(&record).Error; err != nil {
+			t.Fatalf("create record: %v", err)
+		}
+	}
+	if _, err := database.MigrateUp(db, "sqlite"); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	return db
+}
+
+// hours returns the hour offsets from testBase of the records
+func hours(records []models.HealthData) []int {
+	var offsets []int
+	for _, r := range records {
+		offsets = append(offsets, int(r.RecordDate.Sub(testBase)/time.Hour))
+	}
+	return offsets
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestHealthListPagesMixedOffsetsInTimeOrder(t *testing.T) {
+	repo := &healthRepository{db: newMixedOffsetDB(t)}
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		desc bool
+		want []int
+	}{
+		{false, []int{0, 1, 2, 3, 4, 5}},
+		{true, []int{5, 4, 3, 2, 1, 0}},
+	} {
+		page := PageRequest{Sort: "record_date", Desc: tt.desc, Limit: 2}
+		var got []int
+		for i := 0; i < 4; i++ {
+			records, next, err := repo.List(ctx, 1, HealthFilter{}, page)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			got = append(got, hours(records)...)
+			if next == nil {
+				break
+			}
+			// Cursors survive the round trip through the client
+			if page.After, err = DecodeCursor(next.Encode()); err != nil {
+				t.Fatalf("DecodeCursor: %v", err)
+			}
+		}
+		if !equalInts(got, tt.want) {
+			t.Errorf("desc=%v: pages = %v, want %v", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestHealthListCursorWithOffset(t *testing.T) {
+	db := newMixedOffsetDB(t)
+	repo := &healthRepository{db: db}
+
+	var third models.HealthData
+	if err := db.Where("weight_kg = ?", 72).First(&third).Error; err != nil {
+		t.Fatalf("find record: %v", err)
+	}
+
+	// A cursor carrying the same instant with an offset continues after the same row
+	cursor := &Cursor{Sort: "record_date", Value: third.RecordDate.In(jakarta).Format(time.RFC3339Nano), ID: third.ID}
+	records, _, err := repo.List(context.Background(), 1, HealthFilter{}, PageRequest{Sort: "record_date", Limit: 10, After: cursor})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if got := hours(records); !equalInts(got, []int{3, 4, 5}) {
+		t.Errorf("after hour 2 = %v, want [3 4 5]", got)
+	}
+}
+
+func TestHealthListRangeWithOffsets(t *testing.T) {
+	repo := &healthRepository{db: newMixedOffsetDB(t)}
+
+	// From is inclusive and To exclusive whatever offset the bounds are given in
+	filter := HealthFilter{
+		From: testBase.Add(1 * time.Hour).In(newYork),
+		To:   testBase.Add(4 * time.Hour).In(jakarta),
+	}
+	records, _, err := repo.List(context.Background(), 1, filter, PageRequest{Sort: "record_date", Limit: 10})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if got := hours(records); !equalInts(got, []int{1, 2, 3}) {
+		t.Errorf("range [1h, 4h) = %v, want [1 2 3]", got)
+	}
+}
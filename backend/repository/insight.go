@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// InsightRepository stores generated trend, projection and anomaly insights
+type InsightRepository interface {
+	// Sync stores the user's freshly computed insights: new ones are created, ones already
+	// stored under the same fingerprint get the new text but keep their read state. Stored
+	// anomalies and insights of the current week (fingerprints ending in ":<week>") that
+	// were not found again are deleted, as the readings behind them were edited, deleted or
+	// fell out of the analysed window. It returns how many were new.
+	Sync(ctx context.Context, userID uint, week string, insights []models.Insight) (int, error)
+	// List returns the newest insights first, optionally unread ones only
+	List(ctx context.Context, userID uint, unreadOnly bool, limit int) ([]models.Insight, error)
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+	// MarkRead marks one of the user's insights read and returns how many rows changed
+	MarkRead(ctx context.Context, id, userID uint, at time.Time) (int64, error)
+}
+
+type insightRepository struct {
+	db *gorm.DB
+}
+
+func (r *insightRepository) Sync(ctx context.Context, userID uint, week string, insights []models.Insight) (int, error) {
+	created := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		found := make([]string, 0, len(insights))
+		for _, insight := range insights {
+			found = append(found, insight.Fingerprint)
+		}
+		stale := tx.Where("user_id = ? AND (kind = ? OR fingerprint LIKE ?)", userID, models.InsightAnomaly, "%:"+week)
+		if len(found) > 0 {
+			stale = stale.Where("fingerprint NOT IN ?", found)
+		}
+		if err := stale.Delete(&models.Insight{}).Error; err != nil {
+			return err
+		}
+
+		for _, insight := range insights {
+			var stored models.Insight
+			err := tx.Where("user_id = ? AND fingerprint = ?", insight.UserID, insight.Fingerprint).First(&stored).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				if err := tx.Create(&insight).Error; err != nil {
+					return err
+				}
+				created++
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if stored.Title == insight.Title && stored.Message == insight.Message && stored.Severity == insight.Severity {
+				continue
+			}
+			if err := tx.Model(&stored).Updates(map[string]interface{}{
+				"title":       insight.Title,
+				"message":     insight.Message,
+				"severity":    insight.Severity,
+				"value":       insight.Value,
+				"observed_at": insight.ObservedAt,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return created, err
+}
+
+func (r *insightRepository) List(ctx context.Context, userID uint, unreadOnly bool, limit int) ([]models.Insight, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	var insights []models.Insight
+	err := query.Order("updated_at desc, id desc").Limit(limit).Find(&insights).Error
+	return insights, err
+}
+
+func (r *insightRepository) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Insight{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *insightRepository) MarkRead(ctx context.Context, id, userID uint, at time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Insight{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userID).
+		Update("read_at", at)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// LoginAttemptRepository stores the sign-in history
+type LoginAttemptRepository interface {
+	Create(ctx context.Context, attempt *models.LoginAttempt) error
+	ListByUser(ctx context.Context, userID uint, limit int) ([]models.LoginAttempt, error)
+}
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func (r *loginAttemptRepository) Create(ctx context.Context, attempt *models.LoginAttempt) error {
+	return r.db.WithContext(ctx).Create(attempt).Error
+}
+
+func (r *loginAttemptRepository) ListByUser(ctx context.Context, userID uint, limit int) ([]models.LoginAttempt, error) {
+	var attempts []models.LoginAttempt
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at desc").Limit(limit).Find(&attempts).Error
+	return attempts, err
+}
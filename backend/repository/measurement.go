@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// MeasurementRepository stores vital sign readings
+type MeasurementRepository interface {
+	Create(ctx context.Context, m *models.Measurement) error
+	// List returns the newest readings first, optionally of one type only
+	List(ctx context.Context, userID uint, measurementType string, limit int) ([]models.Measurement, error)
+	// ListSince returns every reading taken since the given time, oldest first
+	ListSince(ctx context.Context, userID uint, since time.Time) ([]models.Measurement, error)
+	// LatestPerType returns the newest reading of every type the user has recorded since the given time
+	LatestPerType(ctx context.Context, userID uint, since time.Time) ([]models.Measurement, error)
+	// Delete removes one of the user's readings and returns how many rows were deleted
+	Delete(ctx context.Context, id, userID uint) (int64, error)
+}
+
+type measurementRepository struct {
+	db *gorm.DB
+}
+
+func (r *measurementRepository) Create(ctx context.Context, m *models.Measurement) error {
+	return r.db.WithContext(ctx).Create(m).Error
+}
+
+func (r *measurementRepository) List(ctx context.Context, userID uint, measurementType string, limit int) ([]models.Measurement, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if measurementType != "" {
+		query = query.Where("type = ?", measurementType)
+	}
+	var rows []models.Measurement
+	err := query.Order("measured_at desc, id desc").Limit(limit).Find(&rows).Error
+	return rows, err
+}
+
+func (r *measurementRepository) ListSince(ctx context.Context, userID uint, since time.Time) ([]models.Measurement, error) {
+	var rows []models.Measurement
+	err := r.db.WithContext(ctx).Where("user_id = ? AND measured_at >= ?", userID, since.UTC()).Order("measured_at asc, id asc").Find(&rows).Error
+	return rows, err
+}
+
+func (r *measurementRepository) LatestPerType(ctx context.Context, userID uint, since time.Time) ([]models.Measurement, error) {
+	var rows []models.Measurement
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND measured_at >= ?", userID, since.UTC()).
+		Where(`NOT EXISTS (SELECT 1 FROM measurements newer WHERE newer.user_id = measurements.user_id AND newer.type = measurements.type
+			AND (newer.measured_at > measurements.measured_at OR (newer.measured_at = measurements.measured_at AND newer.id > measurements.id)))`).
+		Order("type asc").
+		Find(&rows).Error
+	return rows, err
+}
+
+func (r *measurementRepository) Delete(ctx context.Context, id, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&models.Measurement{})
+	return result.RowsAffected, result.Error
+}
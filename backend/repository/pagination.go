@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCursor is returned for cursors that are malformed or belong to another sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SortField is a column a list can be ordered by; Time columns carry their cursor value
+// as RFC 3339 in UTC, numeric ones as a decimal
+type SortField struct {
+	Column string
+	Time   bool
+}
+
+// Cursor is the position of the last row of a page: its sort value and id
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+// Encode returns the opaque token handed to clients as next_cursor
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor parses a token produced by Cursor.Encode
+func DecodeCursor(token string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// PageRequest asks for one page of a keyset-paginated list. Rows are ordered by the
+// sort field with id as tiebreaker, so a page never repeats or skips rows that were
+// inserted while the client was paging.
+type PageRequest struct {
+	Sort  string // key into the list's sort fields, without the "-" prefix
+	Desc  bool
+	Limit int
+	After *Cursor // nil for the first page
+}
+
+// applyPage orders and limits the query and starts it after the cursor. It fetches one
+// row more than the limit so nextCursor can tell whether another page exists.
+func applyPage(query *gorm.DB, fields map[string]SortField, page PageRequest) (*gorm.DB, error) {
+	field, ok := fields[page.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort field %q", page.Sort)
+	}
+
+	dir, cmp := "asc", ">"
+	if page.Desc {
+		dir, cmp = "desc", "<"
+	}
+
+	if page.After != nil {
+		if page.After.Sort != sortKey(page) {
+			return nil, ErrInvalidCursor
+		}
+		value, err := cursorValue(field, page.After.Value)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(
+			fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", field.Column, cmp, field.Column, cmp),
+			value, value, page.After.ID,
+		)
+	}
+
+	return query.Order(field.Column + " " + dir + ", id " + dir).Limit(page.Limit + 1), nil
+}
+
+// nextCursor trims the extra row fetched by applyPage and returns the cursor for the
+// following page, or nil when this is the last one
+func nextCursor[T any](rows []T, page PageRequest, position func(T) (any, uint)) ([]T, *Cursor) {
+	if len(rows) <= page.Limit {
+		return rows, nil
+	}
+	rows = rows[:page.Limit]
+
+	value, id := position(rows[len(rows)-1])
+	cursor := &Cursor{Sort: sortKey(page), ID: id}
+	switch v := value.(type) {
+	case time.Time:
+		cursor.Value = v.UTC().Format(time.RFC3339Nano)
+	case float64:
+		cursor.Value = strconv.FormatFloat(v, 'g', -1, 64)
+	case int:
+		cursor.Value = strconv.Itoa(v)
+	}
+	return rows, cursor
+}
+
+func sortKey(page PageRequest) string {
+	if page.Desc {
+		return "-" + page.Sort
+	}
+	return page.Sort
+}
+
+func cursorValue(field SortField, raw string) (any, error) {
+	if field.Time {
+		t, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return t.UTC(), nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return v, nil
+}
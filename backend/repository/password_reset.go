@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// PasswordResetRepository stores single-use password reset tokens
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *models.PasswordResetToken) error
+	FindByTokenHash(ctx context.Context, hash string) (*models.PasswordResetToken, error)
+	// InvalidateAll marks every unused token of the user as used
+	InvalidateAll(ctx context.Context, userID uint) error
+	// MarkUsed consumes a token and reports false if it had already been used
+	MarkUsed(ctx context.Context, id uint) (bool, error)
+}
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func (r *passwordResetRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *passwordResetRepository) FindByTokenHash(ctx context.Context, hash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &token, nil
+}
+
+func (r *passwordResetRepository) InvalidateAll(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&models.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
+
+func (r *passwordResetRepository) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	// A concurrent request consuming the same token updates zero rows
+	result := r.db.WithContext(ctx).Model(&models.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
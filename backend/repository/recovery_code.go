@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// RecoveryCodeRepository stores hashed 2FA recovery codes
+type RecoveryCodeRepository interface {
+	// Replace deletes the user's codes and stores the given hashes
+	Replace(ctx context.Context, userID uint, hashes []string) error
+	DeleteAll(ctx context.Context, userID uint) error
+	// Use consumes an unused code and reports whether one matched
+	Use(ctx context.Context, userID uint, hash string) (bool, error)
+}
+
+type recoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func (r *recoveryCodeRepository) Replace(ctx context.Context, userID uint, hashes []string) error {
+	if err := r.DeleteAll(ctx, userID); err != nil {
+		return err
+	}
+
+	records := make([]models.RecoveryCode, len(hashes))
+	for i, hash := range hashes {
+		records[i] = models.RecoveryCode{UserID: userID, CodeHash: hash}
+	}
+	return r.db.WithContext(ctx).Create(&records).Error
+}
+
+func (r *recoveryCodeRepository) DeleteAll(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+}
+
+func (r *recoveryCodeRepository) Use(ctx context.Context, userID uint, hash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
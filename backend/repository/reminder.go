@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// ReminderRepository stores reminders
+type ReminderRepository interface {
+	ListByUser(ctx context.Context, userID uint) ([]models.Reminder, error)
+	Find(ctx context.Context, id, userID uint) (*models.Reminder, error)
+	Create(ctx context.Context, reminder *models.Reminder) error
+	CreateBatch(ctx context.Context, reminders []models.Reminder) error
+	Save(ctx context.Context, reminder *models.Reminder) error
+	// Delete removes a reminder and returns how many rows were deleted
+	Delete(ctx context.Context, id, userID uint) (int64, error)
+}
+
+type reminderRepository struct {
+	db *gorm.DB
+}
+
+func (r *reminderRepository) ListByUser(ctx context.Context, userID uint) ([]models.Reminder, error) {
+	var reminders []models.Reminder
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("time ASC").Find(&reminders).Error
+	return reminders, err
+}
+
+func (r *reminderRepository) Find(ctx context.Context, id, userID uint) (*models.Reminder, error) {
+	var reminder models.Reminder
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&reminder).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &reminder, nil
+}
+
+func (r *reminderRepository) Create(ctx context.Context, reminder *models.Reminder) error {
+	return r.db.WithContext(ctx).Create(reminder).Error
+}
+
+func (r *reminderRepository) CreateBatch(ctx context.Context, reminders []models.Reminder) error {
+	return r.db.WithContext(ctx).Create(&reminders).Error
+}
+
+func (r *reminderRepository) Save(ctx context.Context, reminder *models.Reminder) error {
+	return r.db.WithContext(ctx).Save(reminder).Error
+}
+
+func (r *reminderRepository) Delete(ctx context.Context, id, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&models.Reminder{})
+	return result.RowsAffected, result.Error
+}
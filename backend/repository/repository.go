@@ -0,0 +1,88 @@
+// Package repository is the data access layer. Handlers depend on the interfaces
+// declared here so they can be tested with fakes; the GORM implementations are
+// built by New.
+//
+// Times are stored and bound in UTC: SQLite keeps them as text with their offset and
+// compares them as text, so a bound time in another offset compares wrongly.
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotFound is returned when a lookup matches no row
+var ErrNotFound = errors.New("record not found")
+
+// Repositories bundles every repository the handlers need
+type Repositories struct {
+	Users          UserRepository
+	Sessions       SessionRepository
+	PasswordResets PasswordResetRepository
+	RecoveryCodes  RecoveryCodeRepository
+	LoginAttempts  LoginAttemptRepository
+	Exports        ExportRepository
+	Health         HealthRepository
+	Measurements   MeasurementRepository
+	Insights       InsightRepository
+	Symptoms       SymptomRepository
+	Family         FamilyRepository
+	Forum          ForumRepository
+	Water          WaterRepository
+	Goals          GoalRepository
+	Reminders      ReminderRepository
+	Articles       ArticleRepository
+	System         SystemRepository
+
+	db *gorm.DB
+}
+
+// Transactor runs a function against repositories that share one transaction
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(tx *Repositories) error) error
+}
+
+// New returns GORM-backed repositories using db
+func New(db *gorm.DB) *Repositories {
+	return &Repositories{
+		Users:          &userRepository{db: db},
+		Sessions:       &sessionRepository{db: db},
+		PasswordResets: &passwordResetRepository{db: db},
+		RecoveryCodes:  &recoveryCodeRepository{db: db},
+		LoginAttempts:  &loginAttemptRepository{db: db},
+		Exports:        &exportRepository{db: db},
+		Health:         &healthRepository{db: db},
+		Measurements:   &measurementRepository{db: db},
+		Insights:       &insightRepository{db: db},
+		Symptoms:       &symptomRepository{db: db},
+		Family:         &familyRepository{db: db},
+		Forum:          &forumRepository{db: db},
+		Water:          &waterRepository{db: db},
+		Goals:          &goalRepository{db: db},
+		Reminders:      &reminderRepository{db: db},
+		Articles:       &articleRepository{db: db},
+		System:         &systemRepository{db: db},
+		db:             db,
+	}
+}
+
+// Transaction runs fn with repositories bound to a single database transaction.
+// Repositories assembled without a database, such as fakes in tests, run fn directly.
+func (r *Repositories) Transaction(ctx context.Context, fn func(tx *Repositories) error) error {
+	if r.db == nil {
+		return fn(r)
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(New(tx))
+	})
+}
+
+// translate maps GORM's not-found error to ErrNotFound
+func translate(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
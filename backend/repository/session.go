@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// SessionRepository stores refresh-token sessions
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session) error
+	// Rotate moves an active session from oldHash to session.RefreshTokenHash, recording the
+	// client it was used from. It returns 0 when oldHash is no longer active: already rotated,
+	// revoked or expired.
+	Rotate(ctx context.Context, oldHash string, session *models.Session) (int64, error)
+	FindByID(ctx context.Context, id, userID uint) (*models.Session, error)
+	FindByTokenHash(ctx context.Context, hash string) (*models.Session, error)
+	Revoke(ctx context.Context, id, userID uint) error
+	// RevokeAll revokes every active session of the user and returns how many were revoked
+	RevokeAll(ctx context.Context, userID uint) (int64, error)
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *models.Session) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *sessionRepository) Rotate(ctx context.Context, oldHash string, session *models.Session) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("refresh_token_hash = ? AND revoked_at IS NULL AND expires_at > ?", oldHash, session.LastUsedAt).
+		Updates(map[string]any{
+			"refresh_token_hash": session.RefreshTokenHash,
+			"last_used_at":       session.LastUsedAt,
+			"user_agent":         session.UserAgent,
+			"ip_address":         session.IPAddress,
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *sessionRepository) FindByID(ctx context.Context, id, userID uint) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&session).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) FindByTokenHash(ctx context.Context, hash string) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.WithContext(ctx).Where("refresh_token_hash = ?", hash).First(&session).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, id, userID uint) error {
+	return r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *sessionRepository) RevokeAll(ctx context.Context, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
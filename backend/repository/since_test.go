@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"health-tracker/database"
+	"health-tracker/models"
+)
+
+// useLocal makes loc the local time zone for the rest of the test, as on a server that
+// does not run in UTC
+func useLocal(t *testing.T, loc *time.Location) {
+	t.Helper()
+	saved := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = saved })
+}
+
+func TestSinceQueriesOnNonUTCHost(t *testing.T) {
+	useLocal(t, jakarta)
+	db := openTestDB(t)
+	if _, err := database.MigrateUp(db, "sqlite"); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := db.Create(&models.User{ID: 1, Email: "ana@example.com", Name: "Ana", Password: "!"}).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	// One row of each kind three hours ago and one an hour ago, stored in UTC like the
+	// handlers store them; since is two hours ago in local time, like the callers build it
+	now := time.Now()
+	since := now.Add(-2 * time.Hour)
+	for i, at := range []time.Time{now.Add(-3 * time.Hour).UTC(), now.Add(-time.Hour).UTC()} {
+		rows := []interface{}{
+			&models.HealthData{UserID: 1, WeightKg: 70 + float64(i), HeightCm: 170, RecordDate: at},
+			&models.Measurement{UserID: 1, Type: models.MeasurementHeartRate, Value: 60 + float64(i), Unit: "bpm", MeasuredAt: at},
+			&models.Symptom{UserID: 1, SymptomType: "physical", SymptomName: "Pusing", Severity: 3 + i, LoggedAt: at},
+		}
+		for _, row := range rows {
+			if err := db.Create(row).Error; err != nil {
+				t.Fatalf("create %T: %v", row, err)
+			}
+		}
+	}
+
+	ctx := context.Background()
+	repos := New(db)
+
+	records, err := repos.Health.ListSince(ctx, 1, since)
+	if err != nil || len(records) != 1 || records[0].WeightKg != 71 {
+		t.Errorf("health ListSince = %+v, %v; want the record from an hour ago", records, err)
+	}
+	readings, err := repos.Measurements.ListSince(ctx, 1, since)
+	if err != nil || len(readings) != 1 || readings[0].Value != 61 {
+		t.Errorf("measurements ListSince = %+v, %v; want the reading from an hour ago", readings, err)
+	}
+	latest, err := repos.Measurements.LatestPerType(ctx, 1, now.Add(-30*time.Minute))
+	if err != nil || len(latest) != 0 {
+		t.Errorf("LatestPerType in the last 30 minutes = %+v, %v; want none", latest, err)
+	}
+	latest, err = repos.Measurements.LatestPerType(ctx, 1, since)
+	if err != nil || len(latest) != 1 || latest[0].Value != 61 {
+		t.Errorf("LatestPerType = %+v, %v; want the reading from an hour ago", latest, err)
+	}
+	symptoms, err := repos.Symptoms.ListSince(ctx, 1, since)
+	if err != nil || len(symptoms) != 1 || symptoms[0].Severity != 4 {
+		t.Errorf("symptoms ListSince = %+v, %v; want the symptom from an hour ago", symptoms, err)
+	}
+	count, err := repos.Symptoms.CountSince(ctx, 1, since)
+	if err != nil || count != 1 {
+		t.Errorf("CountSince = %d, %v; want 1", count, err)
+	}
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// SymptomRepository stores logged symptoms and the symptom catalogue
+type SymptomRepository interface {
+	ListTemplates(ctx context.Context) ([]models.SymptomTemplate, error)
+	Create(ctx context.Context, symptom *models.Symptom) error
+	CreateBatch(ctx context.Context, symptoms []models.Symptom) error
+	// Recent returns the latest symptoms, optionally limited to one symptom type
+	Recent(ctx context.Context, userID uint, symptomType string, limit int) ([]models.Symptom, error)
+	// List returns one page of the user's symptoms matching the filter, and the cursor
+	// of the next page if there is one
+	List(ctx context.Context, userID uint, filter SymptomFilter, page PageRequest) ([]models.Symptom, *Cursor, error)
+	ListSince(ctx context.Context, userID uint, since time.Time) ([]models.Symptom, error)
+	CountSince(ctx context.Context, userID uint, since time.Time) (int64, error)
+	MostFrequent(ctx context.Context, userID uint, limit int) ([]models.SymptomCount, error)
+	AverageSeverity(ctx context.Context, userID uint) (float64, error)
+	// Buckets counts symptoms per bucket, skipping empty buckets
+	Buckets(ctx context.Context, userID uint, q BucketQuery) ([]models.SymptomBucketRow, error)
+}
+
+// SymptomFilter narrows a symptom listing; zero values match everything
+type SymptomFilter struct {
+	From        time.Time // inclusive
+	To          time.Time // exclusive
+	SymptomType string
+	SymptomName string
+	MinSeverity int
+	MaxSeverity int
+}
+
+// SymptomSortFields are the columns symptoms can be sorted by
+var SymptomSortFields = map[string]SortField{
+	"logged_at": {Column: "logged_at", Time: true},
+	"severity":  {Column: "severity"},
+}
+
+type symptomRepository struct {
+	db *gorm.DB
+}
+
+func (r *symptomRepository) ListTemplates(ctx context.Context) ([]models.SymptomTemplate, error) {
+	var templates []models.SymptomTemplate
+	err := r.db.WithContext(ctx).Find(&templates).Error
+	return templates, err
+}
+
+func (r *symptomRepository) Create(ctx context.Context, symptom *models.Symptom) error {
+	return r.db.WithContext(ctx).Create(symptom).Error
+}
+
+func (r *symptomRepository) CreateBatch(ctx context.Context, symptoms []models.Symptom) error {
+	return r.db.WithContext(ctx).Create(&symptoms).Error
+}
+
+func (r *symptomRepository) Recent(ctx context.Context, userID uint, symptomType string, limit int) ([]models.Symptom, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if symptomType != "" {
+		query = query.Where("symptom_type = ?", symptomType)
+	}
+
+	var symptoms []models.Symptom
+	err := query.Order("logged_at desc").Limit(limit).Find(&symptoms).Error
+	return symptoms, err
+}
+
+func (r *symptomRepository) List(ctx context.Context, userID uint, filter SymptomFilter, page PageRequest) ([]models.Symptom, *Cursor, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if !filter.From.IsZero() {
+		query = query.Where("logged_at >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("logged_at < ?", filter.To.UTC())
+	}
+	if filter.SymptomType != "" {
+		query = query.Where("symptom_type = ?", filter.SymptomType)
+	}
+	if filter.SymptomName != "" {
+		query = query.Where("symptom_name = ?", filter.SymptomName)
+	}
+	if filter.MinSeverity > 0 {
+		query = query.Where("severity >= ?", filter.MinSeverity)
+	}
+	if filter.MaxSeverity > 0 {
+		query = query.Where("severity <= ?", filter.MaxSeverity)
+	}
+
+	query, err := applyPage(query, SymptomSortFields, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var symptoms []models.Symptom
+	if err := query.Find(&symptoms).Error; err != nil {
+		return nil, nil, err
+	}
+	symptoms, next := nextCursor(symptoms, page, func(s models.Symptom) (any, uint) {
+		if page.Sort == "severity" {
+			return s.Severity, s.ID
+		}
+		return s.LoggedAt, s.ID
+	})
+	return symptoms, next, nil
+}
+
+func (r *symptomRepository) ListSince(ctx context.Context, userID uint, since time.Time) ([]models.Symptom, error) {
+	var symptoms []models.Symptom
+	err := r.db.WithContext(ctx).Where("user_id = ? AND logged_at > ?", userID, since.UTC()).Order("logged_at desc").Find(&symptoms).Error
+	return symptoms, err
+}
+
+func (r *symptomRepository) CountSince(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Symptom{}).Where("user_id = ? AND logged_at > ?", userID, since.UTC()).Count(&count).Error
+	return count, err
+}
+
+func (r *symptomRepository) MostFrequent(ctx context.Context, userID uint, limit int) ([]models.SymptomCount, error) {
+	var counts []models.SymptomCount
+	err := r.db.WithContext(ctx).Model(&models.Symptom{}).
+		Select("symptom_name, COUNT(*) as count").
+		Where("user_id = ?", userID).
+		Group("symptom_name").
+		Order("count desc").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *symptomRepository) AverageSeverity(ctx context.Context, userID uint) (float64, error) {
+	var avg float64
+	err := r.db.WithContext(ctx).Model(&models.Symptom{}).
+		Select("COALESCE(AVG(severity), 0)").
+		Where("user_id = ?", userID).
+		Scan(&avg).Error
+	return avg, err
+}
+
+func (r *symptomRepository) Buckets(ctx context.Context, userID uint, q BucketQuery) ([]models.SymptomBucketRow, error) {
+	bucket, args := timeBucket(r.db, "logged_at", q)
+	args = append(args, userID, q.From.UTC(), q.To.UTC())
+
+	var rows []models.SymptomBucketRow
+	err := r.db.WithContext(ctx).Raw(`SELECT bucket, COUNT(*) AS count, AVG(severity) AS avg_severity, MAX(severity) AS max_severity
+		FROM (
+			SELECT `+bucket+` AS bucket, severity
+			FROM symptoms WHERE user_id = ? AND logged_at >= ? AND logged_at < ?
+		) b
+		GROUP BY bucket ORDER BY bucket`, args...).Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"health-tracker/database"
+
+	"gorm.io/gorm"
+)
+
+// SystemRepository reports database health for the probe endpoints
+type SystemRepository interface {
+	Ping(ctx context.Context) error
+	SchemaState(ctx context.Context) (database.SchemaState, error)
+}
+
+type systemRepository struct {
+	db *gorm.DB
+}
+
+func (r *systemRepository) Ping(ctx context.Context) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+func (r *systemRepository) SchemaState(ctx context.Context) (database.SchemaState, error) {
+	return database.GetSchemaState(r.db.WithContext(ctx), r.db.Dialector.Name())
+}
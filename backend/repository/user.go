@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// UserFilter narrows the admin user list
+type UserFilter struct {
+	Query  string // matched against email and name
+	Role   string
+	Status string // active, suspended
+	Offset int
+	Limit  int
+}
+
+// UserRepository stores user accounts
+type UserRepository interface {
+	FindByID(ctx context.Context, id uint) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	Save(ctx context.Context, user *models.User) error
+	Update(ctx context.Context, id uint, fields map[string]interface{}) error
+	// RecordFailedLogin atomically counts a failed sign-in at the given time, restarting the
+	// count when the previous failure is older than window, and returns the new count
+	RecordFailedLogin(ctx context.Context, id uint, at time.Time, window time.Duration) (int, error)
+	// AdvanceTwoFactorStep stores a newer TOTP step and reports false if it was already used
+	AdvanceTwoFactorStep(ctx context.Context, id uint, step int64) (bool, error)
+	// ConsumeLoginChallenge clears the pending 2FA login challenge and reports false if it
+	// was not the one with the given hash, so each challenge is exchanged at most once
+	ConsumeLoginChallenge(ctx context.Context, id uint, hash string) (bool, error)
+	List(ctx context.Context, filter UserFilter) ([]models.User, int64, error)
+	// Purge deletes the user with everything they own and returns the export files to remove from disk
+	Purge(ctx context.Context, id uint) ([]string, error)
+}
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &user, nil
+}
+
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
+}
+
+func (r *userRepository) Save(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Save(user).Error
+}
+
+func (r *userRepository) Update(ctx context.Context, id uint, fields map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *userRepository) RecordFailedLogin(ctx context.Context, id uint, at time.Time, window time.Duration) (int, error) {
+	db := r.db.WithContext(ctx)
+	at = at.UTC()
+	err := db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"failed_logins": gorm.Expr("CASE WHEN last_failed_login_at IS NULL OR last_failed_login_at >= ? THEN failed_logins + 1 ELSE 1 END",
+			at.Add(-window)),
+		"last_failed_login_at": at,
+	}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var failures int
+	if err := db.Model(&models.User{}).Where("id = ?", id).Pluck("failed_logins", &failures).Error; err != nil {
+		return 0, err
+	}
+	return failures, nil
+}
+
+func (r *userRepository) AdvanceTwoFactorStep(ctx context.Context, id uint, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND two_factor_last_step < ?", id, step).
+		Update("two_factor_last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *userRepository) ConsumeLoginChallenge(ctx context.Context, id uint, hash string) (bool, error) {
+	if hash == "" {
+		return false, nil
+	}
+	result := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND login_challenge = ?", id, hash).
+		Update("login_challenge", "")
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *userRepository) List(ctx context.Context, filter UserFilter) ([]models.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.User{}).Where("email <> ?", models.DeletedUserEmail)
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		pattern := "%" + strings.ToLower(q) + "%"
+		query = query.Where("LOWER(email) LIKE ? OR LOWER(name) LIKE ?", pattern, pattern)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	switch filter.Status {
+	case "suspended":
+		query = query.Where("suspended_at IS NOT NULL")
+	case "active":
+		query = query.Where("suspended_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	err := query.Order("created_at desc").Offset(filter.Offset).Limit(filter.Limit).Find(&users).Error
+	return users, total, err
+}
+
+func (r *userRepository) Purge(ctx context.Context, id uint) ([]string, error) {
+	var files []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		placeholder, err := deletedUserPlaceholder(tx)
+		if err != nil {
+			return err
+		}
+
+		// Keep like counters consistent before the user's likes disappear
+		if err := tx.Model(&models.Post{}).
+			Where("id IN (?)", tx.Model(&models.Like{}).Select("post_id").Where("user_id = ?", id)).
+			Update("likes_count", gorm.Expr("likes_count - 1")).Error; err != nil {
+			return err
+		}
+
+		// Forum threads stay readable but no longer point at the user
+		if err := tx.Model(&models.Post{}).Where("user_id = ?", id).
+			Update("user_id", placeholder.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Comment{}).Where("user_id = ?", id).
+			Update("user_id", placeholder.ID).Error; err != nil {
+			return err
+		}
+
+		// Archived exports on disk contain the same personal data
+		if err := tx.Model(&models.ExportJob{}).
+			Where("user_id = ? AND file_path <> ?", id, "").
+			Pluck("file_path", &files).Error; err != nil {
+			return err
+		}
+
+		owned := []interface{}{
+			&models.Like{},
+			&models.HealthData{},
+			&models.Measurement{},
+			&models.Insight{},
+			&models.Symptom{},
+			&models.WaterIntake{},
+			&models.Goal{},
+			&models.Reminder{},
+			&models.Session{},
+			&models.PasswordResetToken{},
+			&models.RecoveryCode{},
+			&models.LoginAttempt{},
+			&models.ExportJob{},
+		}
+		for _, model := range owned {
+			if err := tx.Where("user_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		// Family links in both directions
+		if err := tx.Where("owner_id = ? OR member_user_id = ?", id, id).
+			Delete(&models.FamilyMember{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&models.User{}, id).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// deletedUserPlaceholder returns the shared account that anonymized content belongs to
+func deletedUserPlaceholder(tx *gorm.DB) (models.User, error) {
+	var placeholder models.User
+	err := tx.Where(models.User{Email: models.DeletedUserEmail}).
+		Attrs(models.User{
+			Name: models.DeletedUserName,
+			// Not a valid bcrypt hash, so nobody can ever sign in as this account
+			Password: "!",
+		}).
+		FirstOrCreate(&placeholder).Error
+	return placeholder, err
+}
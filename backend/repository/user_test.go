@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"health-tracker/database"
+	"health-tracker/models"
+)
+
+func TestUserTwoFactorGuards(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := database.MigrateUp(db, "sqlite"); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user := models.User{Email: "ana@example.com", Name: "Ana", Password: "!", TwoFactorLastStep: 10, LoginChallenge: "hash"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	repo := &userRepository{db: db}
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		step int64
+		want bool
+	}{{10, false}, {9, false}, {11, true}, {11, false}} {
+		if advanced, err := repo.AdvanceTwoFactorStep(ctx, user.ID, tt.step); err != nil || advanced != tt.want {
+			t.Errorf("advance to %d = %v, %v; want %v", tt.step, advanced, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		hash string
+		want bool
+	}{{"other", false}, {"", false}, {"hash", true}, {"hash", false}} {
+		if consumed, err := repo.ConsumeLoginChallenge(ctx, user.ID, tt.hash); err != nil || consumed != tt.want {
+			t.Errorf("consume %q = %v, %v; want %v", tt.hash, consumed, err, tt.want)
+		}
+	}
+}
+
+func TestRecordFailedLoginRestartsAfterWindow(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := database.MigrateUp(db, "sqlite"); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	user := models.User{Email: "ana@example.com", Name: "Ana", Password: "!"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	repo := &userRepository{db: db}
+	ctx := context.Background()
+	window := 15 * time.Minute
+
+	for i, tt := range []struct {
+		at   time.Duration // after testBase
+		want int
+	}{
+		{0, 1},
+		{5 * time.Minute, 2},
+		{20 * time.Minute, 3}, // 15 minutes after the previous failure
+		{36 * time.Minute, 1}, // more than the window since the previous failure
+		{37 * time.Minute, 2},
+	} {
+		// Failures reported in different offsets still compare by instant
+		zone := []*time.Location{jakarta, newYork}[i%2]
+		failures, err := repo.RecordFailedLogin(ctx, user.ID, testBase.Add(tt.at).In(zone), window)
+		if err != nil {
+			t.Fatalf("RecordFailedLogin: %v", err)
+		}
+		if failures != tt.want {
+			t.Errorf("failure at +%v = %d, want %d", tt.at, failures, tt.want)
+		}
+	}
+}
+
+func TestPurgeRemovesOwnedDataAndAnonymizesForum(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := database.MigrateUp(db, "sqlite"); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	ana := models.User{Email: "ana@example.com", Name: "Ana", Password: "!"}
+	budi := models.User{Email: "budi@example.com", Name: "Budi", Password: "!"}
+	for _, user := range []*models.User{&ana, &budi} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+	anaPost := models.Post{UserID: ana.ID, Title: "Hi", Content: "Hello"}
+	budiPost := models.Post{UserID: budi.ID, Title: "Hey", Content: "There", LikesCount: 2}
+	for _, post := range []*models.Post{&anaPost, &budiPost} {
+		if err := db.Create(post).Error; err != nil {
+			t.Fatalf("create post: %v", err)
+		}
+	}
+	for _, row := range []interface{}{
+		&models.Comment{PostID: budiPost.ID, UserID: ana.ID, Content: "Nice"},
+		&models.Like{PostID: budiPost.ID, UserID: ana.ID},
+		&models.Like{PostID: budiPost.ID, UserID: budi.ID},
+		&models.HealthData{UserID: ana.ID, WeightKg: 60, HeightCm: 165, RecordDate: testBase},
+		&models.HealthData{UserID: budi.ID, WeightKg: 80, HeightCm: 180, RecordDate: testBase},
+		&models.FamilyMember{OwnerID: budi.ID, MemberUserID: ana.ID, MemberEmail: ana.Email},
+		&models.ExportJob{UserID: ana.ID, Status: models.ExportStatusCompleted, FilePath: "/exports/export-1.zip"},
+		&models.ExportJob{UserID: ana.ID, Status: models.ExportStatusFailed},
+	} {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatalf("create %T: %v", row, err)
+		}
+	}
+
+	files, err := (&userRepository{db: db}).Purge(context.Background(), ana.ID)
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if len(files) != 1 || files[0] != "/exports/export-1.zip" {
+		t.Errorf("export files = %v, want the completed archive", files)
+	}
+
+	count := func(model interface{}, query string, args ...interface{}) int64 {
+		t.Helper()
+		var n int64
+		if err := db.Model(model).Where(query, args...).Count(&n).Error; err != nil {
+			t.Fatalf("count %T: %v", model, err)
+		}
+		return n
+	}
+	for _, model := range []interface{}{&models.Like{}, &models.HealthData{}, &models.ExportJob{}} {
+		if n := count(model, "user_id = ?", ana.ID); n != 0 {
+			t.Errorf("%T rows left for the deleted user = %d", model, n)
+		}
+	}
+	if n := count(&models.User{}, "id = ?", ana.ID); n != 0 {
+		t.Error("user still exists")
+	}
+	if n := count(&models.FamilyMember{}, "member_user_id = ?", ana.ID); n != 0 {
+		t.Error("family link to the deleted user still exists")
+	}
+	if n := count(&models.HealthData{}, "user_id = ?", budi.ID); n != 1 {
+		t.Errorf("other user's health records = %d, want 1", n)
+	}
+
+	var placeholder models.User
+	if err := db.Where("email = ?", models.DeletedUserEmail).First(&placeholder).Error; err != nil {
+		t.Fatalf("placeholder: %v", err)
+	}
+	if n := count(&models.Post{}, "user_id = ?", placeholder.ID); n != 1 {
+		t.Errorf("posts owned by the placeholder = %d, want 1", n)
+	}
+	if n := count(&models.Comment{}, "user_id = ?", placeholder.ID); n != 1 {
+		t.Errorf("comments owned by the placeholder = %d, want 1", n)
+	}
+	var post models.Post
+	if err := db.First(&post, budiPost.ID).Error; err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if post.LikesCount != 1 {
+		t.Errorf("likes_count = %d, want 1", post.LikesCount)
+	}
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	"health-tracker/models"
+
+	"gorm.io/gorm"
+)
+
+// WaterRepository stores daily water intake
+type WaterRepository interface {
+	// FindByDate returns the record for a YYYY-MM-DD date or ErrNotFound
+	FindByDate(ctx context.Context, userID uint, date string) (*models.WaterIntake, error)
+	Create(ctx context.Context, intake *models.WaterIntake) error
+	Save(ctx context.Context, intake *models.WaterIntake) error
+	Recent(ctx context.Context, userID uint, days int) ([]models.WaterIntake, error)
+	// Buckets sums intake per bucket, skipping empty buckets. Water is recorded per local
+	// date, so the range is compared as dates in the query's location.
+	Buckets(ctx context.Context, userID uint, q BucketQuery) ([]models.WaterBucketRow, error)
+}
+
+type waterRepository struct {
+	db *gorm.DB
+}
+
+func (r *waterRepository) FindByDate(ctx context.Context, userID uint, date string) (*models.WaterIntake, error) {
+	var intake models.WaterIntake
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND date = ?", userID, date).First(&intake).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &intake, nil
+}
+
+func (r *waterRepository) Create(ctx context.Context, intake *models.WaterIntake) error {
+	return r.db.WithContext(ctx).Create(intake).Error
+}
+
+func (r *waterRepository) Save(ctx context.Context, intake *models.WaterIntake) error {
+	return r.db.WithContext(ctx).Save(intake).Error
+}
+
+func (r *waterRepository) Recent(ctx context.Context, userID uint, days int) ([]models.WaterIntake, error) {
+	var history []models.WaterIntake
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("date DESC").Limit(days).Find(&history).Error
+	return history, err
+}
+
+func (r *waterRepository) Buckets(ctx context.Context, userID uint, q BucketQuery) ([]models.WaterBucketRow, error) {
+	from := q.From.In(q.Location).Format("2006-01-02")
+	to := q.To.In(q.Location).Format("2006-01-02")
+
+	var rows []models.WaterBucketRow
+	err := r.db.WithContext(ctx).Raw(`SELECT bucket, SUM(glasses) AS glasses, COUNT(*) AS days,
+			SUM(CASE WHEN glasses >= goal THEN 1 ELSE 0 END) AS days_goal_met
+		FROM (
+			SELECT `+dateBucket(r.db, "date", q)+` AS bucket, glasses, goal
+			FROM water_intakes WHERE user_id = ? AND date >= ? AND date < ?
+		) b
+		GROUP BY bucket ORDER BY bucket`, userID, from, to).Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,340 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"health-tracker/database"
+	"health-tracker/export"
+	"health-tracker/models"
+	"health-tracker/openapi"
+	"health-tracker/ratelimit"
+	"health-tracker/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiDocs documents every /v1 route, keyed like overrides ("METHOD /path" relative to the
+// version root). BuildOpenAPI reports routes missing from this table.
+var apiDocs = map[string]openapi.Route{
+	// Authentication
+	"POST /auth/register":        {Tag: "Auth", Summary: "Register a new account", Request: models.RegisterRequest{}, Response: models.LoginResponse{}, Status: http.StatusCreated},
+	"POST /auth/login":           {Tag: "Auth", Summary: "Log in", Description: "Returns a challenge token instead of a session when two-factor authentication is enabled.", Request: models.LoginRequest{}, Response: models.LoginResponse{}},
+	"POST /auth/refresh":         {Tag: "Auth", Summary: "Exchange a refresh token for a new token pair", Request: models.RefreshTokenRequest{}, Response: models.LoginResponse{}},
+	"POST /auth/2fa/verify":      {Tag: "Auth", Summary: "Complete a two-factor login", Request: models.TwoFactorVerifyRequest{}, Response: models.LoginResponse{}},
+	"POST /auth/forgot-password": {Tag: "Auth", Summary: "Email a password reset link", Request: models.ForgotPasswordRequest{}},
+	"POST /auth/reset-password":  {Tag: "Auth", Summary: "Reset the password with an emailed token", Request: models.ResetPasswordRequest{}},
+	"GET /auth/me":               {Tag: "Auth", Summary: "Current user's profile", Auth: true, Response: models.User{}},
+	"PUT /auth/profile":          {Tag: "Auth", Summary: "Update the profile", Auth: true, Request: models.UpdateProfileRequest{}, Response: models.User{}},
+	"POST /auth/logout":          {Tag: "Auth", Summary: "Revoke the current session", Auth: true},
+	"POST /auth/logout-all": {Tag: "Auth", Summary: "Revoke every session", Auth: true, Response: struct {
+		RevokedSessions int64 `json:"revoked_sessions"`
+	}{}},
+	"GET /auth/login-history": {Tag: "Auth", Summary: "Recent login attempts", Auth: true, Response: []models.LoginAttempt{},
+		Query: []openapi.Parameter{openapi.QueryParam("limit", "integer", "1-100, default 20")}},
+	"DELETE /auth/account": {Tag: "Account", Summary: "Delete the account and all its data", Auth: true, Request: models.DeleteAccountRequest{}},
+
+	// Two-factor authentication
+	"POST /auth/2fa/setup":          {Tag: "Two-factor", Summary: "Start two-factor enrollment", Auth: true, Response: models.TwoFactorSetupResponse{}},
+	"POST /auth/2fa/confirm":        {Tag: "Two-factor", Summary: "Enable two-factor with a first code", Auth: true, Request: models.TwoFactorCodeRequest{}, Response: models.RecoveryCodesResponse{}},
+	"POST /auth/2fa/recovery-codes": {Tag: "Two-factor", Summary: "Regenerate recovery codes", Auth: true, Request: models.TwoFactorCodeRequest{}, Response: models.RecoveryCodesResponse{}},
+	"POST /auth/2fa/disable":        {Tag: "Two-factor", Summary: "Disable two-factor", Auth: true, Request: models.TwoFactorDisableRequest{}},
+
+	// Account data
+	"GET /account/export": {Tag: "Account", Summary: "Export all data as a ZIP", Auth: true, ContentType: "application/zip",
+		Description: "Large accounts, or ?async=true, get 202 with an export job instead.",
+		Query:       []openapi.Parameter{openapi.QueryParam("async", "boolean", "Always run the export in the background")}},
+	"GET /account/export/jobs/:id": {Tag: "Account", Summary: "Background export status", Auth: true, Response: models.ExportJobResponse{}},
+	"GET /account/export/download/:id": {Tag: "Account", Summary: "Download a finished export through a signed link", ContentType: "application/zip",
+		Query: []openapi.Parameter{
+			openapi.QueryParam("expires", "integer", "Link expiry as a Unix timestamp"),
+			openapi.QueryParam("signature", "string", "Link signature"),
+		}},
+	"POST /account/import": {Tag: "Account", Summary: "Restore an export ZIP (multipart field \"archive\")", Auth: true, Response: export.ImportResult{},
+		Description: "Rows are validated like the create endpoints. If any is invalid nothing is imported and the VALIDATION_FAILED details name each field as <entity>[<row>].<field>."},
+
+	// Admin
+	"GET /admin/users": {Tag: "Admin", Summary: "List users", Auth: true, Response: []models.User{}, Paginated: true,
+		Query: []openapi.Parameter{
+			openapi.QueryParam("q", "string", "Search name or email"),
+			openapi.QueryParam("role", "string", "user, moderator or admin"),
+			openapi.QueryParam("status", "string", "active or suspended"),
+			openapi.QueryParam("page", "integer", "Default 1"),
+			openapi.QueryParam("limit", "integer", "1-100, default 20"),
+		}},
+	"GET /admin/users/:id":           {Tag: "Admin", Summary: "Get a user", Auth: true, Response: models.User{}},
+	"PUT /admin/users/:id/suspend":   {Tag: "Admin", Summary: "Suspend a user and revoke their sessions", Auth: true, Request: models.SuspendUserRequest{}, Response: models.User{}},
+	"PUT /admin/users/:id/unsuspend": {Tag: "Admin", Summary: "Lift a suspension", Auth: true, Response: models.User{}},
+	"PUT /admin/users/:id/role":      {Tag: "Admin", Summary: "Change a user's role (admins only)", Auth: true, Request: models.UpdateRoleRequest{}, Response: models.User{}},
+
+	// Articles
+	"GET /articles": {Tag: "Articles", Summary: "List articles", Response: []models.Article{},
+		Query: []openapi.Parameter{openapi.QueryParam("category", "string", "Category ID; \"all\" for every category")}},
+	"GET /articles/categories": {Tag: "Articles", Summary: "Article categories", Response: []map[string]string{}},
+	"GET /articles/search": {Tag: "Articles", Summary: "Search articles", Response: []models.Article{},
+		Query: []openapi.Parameter{{Name: "q", In: "query", Required: true, Schema: &openapi.Schema{Type: "string"}}}},
+	"GET /articles/:id": {Tag: "Articles", Summary: "Get an article", Response: models.Article{}},
+
+	// Health data
+	"POST /health": {Tag: "Health", Summary: "Record health data", Auth: true, Request: models.HealthDataRequest{}, Status: http.StatusCreated, Response: struct {
+		HealthData  models.HealthData `json:"health_data"`
+		BMICategory string            `json:"bmi_category"`
+	}{}},
+	"PUT /health/:id": {Tag: "Health", Summary: "Replace a health record", Description: "BMI is recomputed and the profile's weight and height follow the latest record.", Auth: true, Request: models.HealthDataRequest{}, Response: struct {
+		HealthData  models.HealthData `json:"health_data"`
+		BMICategory string            `json:"bmi_category"`
+	}{}},
+	"DELETE /health/:id": {Tag: "Health", Summary: "Delete a health record", Description: "The profile's weight and height follow the latest remaining record; deleting the last one leaves them unchanged.", Auth: true},
+	"GET /health": {Tag: "Health", Summary: "Health records, newest first", Auth: true, Response: []models.HealthData{}, Paginated: true,
+		Query: append(historyQuery(repository.HealthSortFields, "-record_date"),
+			openapi.QueryParam("emotional_state", "string", "Only records with this emotional state"))},
+	"GET /health/latest": {Tag: "Health", Summary: "Latest health record", Description: "data is omitted when nothing has been recorded yet.", Auth: true, Response: struct {
+		HealthData  models.HealthData `json:"health_data"`
+		BMICategory string            `json:"bmi_category"`
+	}{}},
+	"GET /health/dashboard": {Tag: "Health", Summary: "Dashboard summary", Auth: true, Response: models.DashboardData{}},
+	"GET /health/graph/:period": {Tag: "Health", Summary: "Weight, BMI, emotional states, symptoms and water intake per time bucket", Auth: true,
+		Description: "Aggregates are computed in SQL. week, month and year end today; custom needs from and to.",
+		Params:      []openapi.Parameter{openapi.EnumParam("period", "path", "", "week", "month", "year", "custom")},
+		Query: []openapi.Parameter{
+			openapi.EnumParam("bucket", "query", "Default day for week and month, week for year, by range length for custom",
+				models.BucketDay, models.BucketWeek, models.BucketMonth),
+			openapi.QueryParam("from", "string", "Start date (YYYY-MM-DD, inclusive) or RFC 3339 timestamp"),
+			openapi.QueryParam("to", "string", "End date (YYYY-MM-DD, inclusive) or RFC 3339 timestamp (exclusive)"),
+			openapi.QueryParam("tz", "string", "IANA time zone the buckets follow, including daylight saving changes within the range, default UTC"),
+			openapi.EnumParam("fill", "query", "Buckets without health records: null values (default), dropped, or the previous values",
+				models.FillNull, models.FillNone, models.FillPrevious),
+		},
+		Response: models.HealthGraph{}},
+	"GET /health/insights": {Tag: "Health", Summary: "Weight and vital sign trends, projections and anomalies", Auth: true, Response: models.InsightsReport{},
+		Description: "Trends are computed from the last 180 days on each call; nothing is written. Insights are stored when health records, measurements or weight goals change and stay on the dashboard until read.",
+		Query: []openapi.Parameter{
+			openapi.QueryParam("unread", "boolean", "Only unread insights"),
+			openapi.QueryParam("limit", "integer", "1-100, default 20"),
+		}},
+	"PUT /health/insights/:id/read": {Tag: "Health", Summary: "Mark an insight read", Auth: true},
+
+	// Measurements
+	"GET /measurements/types": {Tag: "Measurements", Summary: "Supported vital signs with units, ranges and categories", Auth: true, Response: []models.MeasurementSpec{}},
+	"GET /measurements": {Tag: "Measurements", Summary: "Recent readings, newest first", Auth: true, Response: []models.Measurement{},
+		Query: []openapi.Parameter{
+			openapi.EnumParam("type", "query", "Only readings of this type", models.MeasurementBloodPressure, models.MeasurementHeartRate,
+				models.MeasurementBloodGlucose, models.MeasurementSpO2, models.MeasurementBodyTemperature, models.MeasurementCholesterol),
+			openapi.QueryParam("limit", "integer", "1-200, default 50"),
+		}},
+	"POST /measurements": {Tag: "Measurements", Summary: "Record a reading", Auth: true, Request: models.MeasurementRequest{}, Response: models.Measurement{}, Status: http.StatusCreated,
+		Description: "The value is converted to the type's unit (mmol/L and °F are accepted where listed) and classified, e.g. AHA blood pressure stages. " +
+			"blood_pressure needs diastolic; blood_glucose needs context."},
+	"GET /measurements/latest": {Tag: "Measurements", Summary: "Newest reading of each type", Auth: true, Response: []models.Measurement{}},
+	"DELETE /measurements/:id": {Tag: "Measurements", Summary: "Delete a reading", Auth: true},
+
+	// Symptoms
+	"GET /symptoms/list": {Tag: "Symptoms", Summary: "Symptom templates by type", Auth: true, Response: struct {
+		Physical []models.SymptomTemplate `json:"physical"`
+		Mental   []models.SymptomTemplate `json:"mental"`
+	}{}},
+	"POST /symptoms":       {Tag: "Symptoms", Summary: "Log a symptom", Auth: true, Request: models.SymptomRequest{}, Response: models.Symptom{}, Status: http.StatusCreated},
+	"POST /symptoms/batch": {Tag: "Symptoms", Summary: "Log several symptoms", Description: "At most SYMPTOM_BATCH_MAX (default 50) symptoms per request; larger batches get BATCH_TOO_LARGE.", Auth: true, Request: []models.SymptomRequest{}, Response: []models.Symptom{}, Status: http.StatusCreated},
+	"GET /symptoms/history": {Tag: "Symptoms", Summary: "Symptom history, also grouped by day", Auth: true, Response: struct {
+		Symptoms []models.Symptom            `json:"symptoms"`
+		Grouped  map[string][]models.Symptom `json:"grouped"`
+	}{}, Paginated: true,
+		Query: append(historyQuery(repository.SymptomSortFields, "-logged_at"),
+			openapi.QueryParam("symptom_type", "string", "Only symptoms of this type, e.g. physical or mental"),
+			openapi.QueryParam("symptom_name", "string", "Only this symptom"),
+			openapi.QueryParam("severity_gte", "integer", "Minimum severity, 1-10"),
+			openapi.QueryParam("severity_lte", "integer", "Maximum severity, 1-10"))},
+	"GET /symptoms/stats": {Tag: "Symptoms", Summary: "Symptom statistics", Auth: true, Response: struct {
+		FrequentSymptoms []models.SymptomCount `json:"frequent_symptoms"`
+		SymptomsThisWeek int64                 `json:"symptoms_this_week"`
+		AverageSeverity  float64               `json:"average_severity"`
+	}{}},
+
+	// Family
+	"POST /family/invite": {Tag: "Family", Summary: "Invite a family member", Auth: true, Request: models.FamilyInviteRequest{}, Response: models.FamilyMember{}, Status: http.StatusCreated},
+	"GET /family/members": {Tag: "Family", Summary: "Approved family members", Auth: true, Response: []models.FamilyMemberResponse{}},
+	"GET /family/requests": {Tag: "Family", Summary: "Pending invitations received and sent", Auth: true, Response: struct {
+		Received []map[string]any      `json:"received"`
+		Sent     []models.FamilyMember `json:"sent"`
+	}{}},
+	"PUT /family/approve/:id": {Tag: "Family", Summary: "Approve an invitation", Auth: true, Response: models.FamilyMember{}},
+	"PUT /family/reject/:id":  {Tag: "Family", Summary: "Reject an invitation", Auth: true},
+	"GET /family/:id/health":  {Tag: "Family", Summary: "A family member's health overview", Auth: true, Response: models.FamilyHealthView{}},
+	"DELETE /family/:id":      {Tag: "Family", Summary: "Remove a family member", Auth: true},
+
+	// Recommendations
+	"GET /recommendations/food":       {Tag: "Recommendations", Summary: "Food recommendations", Auth: true, Response: []models.FoodRecommendation{}},
+	"GET /recommendations/exercise":   {Tag: "Recommendations", Summary: "Exercise recommendations", Auth: true, Response: []models.ExerciseRecommendation{}},
+	"GET /recommendations/emotional":  {Tag: "Recommendations", Summary: "Emotional wellbeing recommendations", Auth: true, Response: []models.EmotionalRecommendation{}},
+	"GET /recommendations/daily-menu": {Tag: "Recommendations", Summary: "Generated daily menu", Auth: true, Response: models.DailyMenu{}},
+
+	// Forum
+	"GET /forum/posts":  {Tag: "Forum", Summary: "List posts", Auth: true, Response: []models.PostResponse{}},
+	"POST /forum/posts": {Tag: "Forum", Summary: "Create a post", Auth: true, Request: models.CreatePostRequest{}, Response: models.PostResponse{}, Status: http.StatusCreated},
+	"GET /forum/posts/:id": {Tag: "Forum", Summary: "A post with its comments", Auth: true, Response: struct {
+		Post     models.PostResponse      `json:"post"`
+		Comments []models.CommentResponse `json:"comments"`
+	}{}},
+	"DELETE /forum/posts/:id":        {Tag: "Forum", Summary: "Delete a post (owner or moderator)", Auth: true},
+	"POST /forum/posts/:id/comments": {Tag: "Forum", Summary: "Comment on a post", Auth: true, Request: models.CreateCommentRequest{}, Response: models.CommentResponse{}, Status: http.StatusCreated},
+	"POST /forum/posts/:id/like": {Tag: "Forum", Summary: "Like or unlike a post", Auth: true, Response: struct {
+		IsLiked    bool `json:"is_liked"`
+		LikesCount int  `json:"likes_count"`
+	}{}},
+
+	// Water
+	"GET /water":         {Tag: "Water", Summary: "Today's water intake", Auth: true, Response: models.WaterIntakeResponse{}},
+	"POST /water/add":    {Tag: "Water", Summary: "Add a glass", Auth: true, Response: models.WaterIntakeResponse{}},
+	"POST /water/remove": {Tag: "Water", Summary: "Remove a glass", Auth: true, Response: models.WaterIntakeResponse{}},
+	"PUT /water/goal":    {Tag: "Water", Summary: "Change the daily goal", Auth: true, Request: models.UpdateWaterGoalRequest{}, Response: models.WaterIntakeResponse{}},
+	"GET /water/history": {Tag: "Water", Summary: "Last 7 days", Auth: true, Response: []models.WaterIntakeResponse{}},
+
+	// Goals
+	"GET /goals":              {Tag: "Goals", Summary: "List goals", Auth: true, Response: []models.GoalResponse{}},
+	"POST /goals":             {Tag: "Goals", Summary: "Create a goal", Auth: true, Request: models.CreateGoalRequest{}, Response: models.GoalResponse{}, Status: http.StatusCreated},
+	"PUT /goals/:id/progress": {Tag: "Goals", Summary: "Update progress", Auth: true, Request: models.UpdateGoalProgressRequest{}, Response: models.GoalResponse{}},
+	"PUT /goals/:id/toggle":   {Tag: "Goals", Summary: "Toggle completion", Auth: true, Response: models.GoalResponse{}},
+	"DELETE /goals/:id":       {Tag: "Goals", Summary: "Delete a goal", Auth: true},
+	"GET /goals/stats": {Tag: "Goals", Summary: "Goal counts", Auth: true, Response: struct {
+		Total      int64 `json:"total"`
+		Completed  int64 `json:"completed"`
+		InProgress int64 `json:"in_progress"`
+	}{}},
+
+	// Reminders
+	"GET /reminders":            {Tag: "Reminders", Summary: "List reminders, creating the defaults on first use", Auth: true, Response: []models.ReminderResponse{}},
+	"POST /reminders":           {Tag: "Reminders", Summary: "Create a reminder", Auth: true, Request: models.CreateReminderRequest{}, Response: models.ReminderResponse{}, Status: http.StatusCreated},
+	"PUT /reminders/:id":        {Tag: "Reminders", Summary: "Update a reminder", Auth: true, Request: models.UpdateReminderRequest{}, Response: models.ReminderResponse{}},
+	"DELETE /reminders/:id":     {Tag: "Reminders", Summary: "Delete a reminder", Auth: true},
+	"PUT /reminders/:id/toggle": {Tag: "Reminders", Summary: "Toggle a reminder", Auth: true, Response: models.ReminderResponse{}},
+}
+
+// rootDocs documents the unversioned operational routes by their full path
+var rootDocs = map[string]openapi.Route{
+	"GET /livez": {Tag: "Probes", Summary: "Liveness", Bare: true, Response: struct {
+		Status string `json:"status"`
+	}{}},
+	"GET /readyz": {Tag: "Probes", Summary: "Readiness: database reachable and no pending migrations", Bare: true, Response: struct {
+		Status     string               `json:"status"`
+		Database   map[string]string    `json:"database"`
+		Migrations database.SchemaState `json:"migrations"`
+	}{}},
+	"GET /server-status": {Tag: "Probes", Summary: "Legacy health check", Response: struct {
+		Status string `json:"status"`
+	}{}},
+	"GET /metrics":         {Tag: "Probes", Summary: "Prometheus metrics", ContentType: "text/plain"},
+	"GET /openapi.json":    {Tag: "Docs", Summary: "This OpenAPI document", Bare: true, Response: map[string]any{}},
+	"GET /docs/*filepath":  {Tag: "Docs", Summary: "API docs viewer", ContentType: "text/html"},
+	"HEAD /docs/*filepath": {Tag: "Docs", Summary: "API docs viewer", ContentType: "text/html"},
+}
+
+// optionalRoutes are documented but only registered when enabled in configuration
+var optionalRoutes = map[string]bool{
+	"GET /metrics": true,
+}
+
+// historyQuery documents the date range, sort and cursor parameters shared by the history lists
+func historyQuery(sortFields map[string]repository.SortField, defaultSort string) []openapi.Parameter {
+	var sorts []string
+	for name := range sortFields {
+		sorts = append(sorts, name, "-"+name)
+	}
+	sort.Strings(sorts)
+	return []openapi.Parameter{
+		openapi.QueryParam("from", "string", "Start date (YYYY-MM-DD, inclusive) or RFC 3339 timestamp"),
+		openapi.QueryParam("to", "string", "End date (YYYY-MM-DD, inclusive) or RFC 3339 timestamp (exclusive)"),
+		openapi.QueryParam("tz", "string", "IANA time zone for dates and day grouping, default UTC"),
+		openapi.EnumParam("sort", "query", "Sort field, - for descending; default "+defaultSort, sorts...),
+		openapi.QueryParam("limit", "integer", "1-200, default 50"),
+		openapi.QueryParam("cursor", "string", "meta.pagination.next_cursor of the previous page"),
+	}
+}
+
+// BuildOpenAPI documents the routes registered on r. Deprecated root aliases of /v1 routes
+// are left out. The returned slice lists registered routes without documentation and
+// documentation entries whose route no longer exists.
+func BuildOpenAPI(r *gin.Engine) (*openapi.Document, []string) {
+	b := openapi.NewBuilder(openapi.Info{
+		Title:       "Health Tracker API",
+		Version:     "v1",
+		Description: "Every JSON response uses the {success, message, data, meta} envelope; errors carry a machine-readable code.",
+	})
+
+	registered := make(map[string]bool)
+	for _, route := range r.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	var undocumented []string
+	for _, route := range r.Routes() {
+		key := route.Method + " " + route.Path
+
+		var doc openapi.Route
+		var ok bool
+		switch {
+		case strings.HasPrefix(route.Path, "/v1/"):
+			doc, ok = apiDocs[route.Method+" "+strings.TrimPrefix(route.Path, "/v1")]
+		case registered[route.Method+" /v1"+route.Path]:
+			continue
+		default:
+			doc, ok = rootDocs[key]
+		}
+
+		if !ok {
+			undocumented = append(undocumented, key)
+			continue
+		}
+		b.Add(route.Method, route.Path, doc)
+	}
+
+	for key := range apiDocs {
+		method, path, _ := strings.Cut(key, " ")
+		if !registered[method+" /v1"+path] {
+			undocumented = append(undocumented, key+" (documented but not registered)")
+		}
+	}
+	for key := range rootDocs {
+		if !registered[key] && !optionalRoutes[key] {
+			undocumented = append(undocumented, key+" (documented but not registered)")
+		}
+	}
+
+	sort.Strings(undocumented)
+	return b.Document(), undocumented
+}
+
+// RunOpenAPICommand handles `./main openapi [--check]`: it prints the document for the
+// routes SetupRoutes registers, or with --check only fails when any route is undocumented
+func RunOpenAPICommand(args []string) error {
+	check := len(args) > 0 && args[0] == "--check"
+	if len(args) > 0 && !check {
+		return fmt.Errorf("unknown argument %q, usage: openapi [--check]", args[0])
+	}
+
+	r := gin.New()
+	SetupRoutes(r, repository.New(nil), ratelimit.NewMemoryStore())
+	doc, undocumented := BuildOpenAPI(r)
+	if len(undocumented) > 0 {
+		return fmt.Errorf("routes missing from the OpenAPI document:\n  %s", strings.Join(undocumented, "\n  "))
+	}
+	if check {
+		fmt.Printf("OpenAPI document covers all %d operations\n", countOperations(doc))
+		return nil
+	}
+
+	out := json.NewEncoder(os.Stdout)
+	out.SetIndent("", "  ")
+	return out.Encode(doc)
+}
+
+func countOperations(doc *openapi.Document) int {
+	n := 0
+	for _, item := range doc.Paths {
+		n += len(*item)
+	}
+	return n
+}
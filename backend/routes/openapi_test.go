@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"testing"
+
+	"health-tracker/config"
+	"health-tracker/ratelimit"
+	"health-tracker/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestOpenAPICoversEveryRoute(t *testing.T) {
+	config.LoadConfig()
+	gin.SetMode(gin.TestMode)
+
+	for _, legacy := range []bool{true, false} {
+		config.AppConfig.LegacyRoutesEnabled = legacy
+
+		r := gin.New()
+		SetupRoutes(r, repository.New(nil), ratelimit.NewMemoryStore())
+		doc, undocumented := BuildOpenAPI(r)
+
+		for _, op := range undocumented {
+			t.Errorf("legacy routes %v: %s", legacy, op)
+		}
+		if countOperations(doc) == 0 {
+			t.Errorf("legacy routes %v: document has no operations", legacy)
+		}
+	}
+}
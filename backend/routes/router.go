@@ -0,0 +1,69 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// overrides swaps the final handler of individual routes in one API version, keyed by
+// "METHOD /path" relative to the version root (e.g. "GET /health/:id"). A nil handler
+// leaves the route out of that version.
+type overrides map[string]gin.HandlerFunc
+
+// router registers routes like a gin.RouterGroup, but lets an API version replace
+// individual handlers so /v2 can reuse the /v1 route table and change only what differs
+type router struct {
+	group     *gin.RouterGroup
+	path      string // relative to the version root
+	overrides overrides
+}
+
+func newRouter(group *gin.RouterGroup, o overrides) *router {
+	return &router{group: group, overrides: o}
+}
+
+// Group creates a sub-router for routes sharing a path prefix and middleware
+func (r *router) Group(path string, middleware ...gin.HandlerFunc) *router {
+	return &router{
+		group:     r.group.Group(path, middleware...),
+		path:      r.path + path,
+		overrides: r.overrides,
+	}
+}
+
+// Use adds middleware to the routes registered afterwards on this router
+func (r *router) Use(middleware ...gin.HandlerFunc) {
+	r.group.Use(middleware...)
+}
+
+func (r *router) GET(path string, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodGet, path, handlers)
+}
+
+func (r *router) POST(path string, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodPost, path, handlers)
+}
+
+func (r *router) PUT(path string, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodPut, path, handlers)
+}
+
+func (r *router) PATCH(path string, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodPatch, path, handlers)
+}
+
+func (r *router) DELETE(path string, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodDelete, path, handlers)
+}
+
+func (r *router) handle(method, path string, handlers []gin.HandlerFunc) {
+	if override, ok := r.overrides[method+" "+r.path+path]; ok {
+		if override == nil {
+			return
+		}
+		// Route-specific middleware (e.g. RequireRole) stays, only the handler itself changes
+		handlers = append(handlers[:len(handlers)-1:len(handlers)-1], override)
+	}
+	r.group.Handle(method, path, handlers...)
+}
@@ -1,143 +1,284 @@
 package routes
 
 import (
+	"log/slog"
+	"net/http"
 	"time"
 
+	"health-tracker/config"
 	"health-tracker/handlers"
+	"health-tracker/metrics"
 	"health-tracker/middleware"
+	"health-tracker/models"
+	"health-tracker/openapi"
+	"health-tracker/ratelimit"
+	"health-tracker/repository"
+	"health-tracker/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(r *gin.Engine) {
-	// CORS middleware
-	r.Use(middleware.CORSMiddleware())
+func SetupRoutes(r *gin.Engine, repos *repository.Repositories, limiter ratelimit.Store) {
+	h := handlers.New(repos)
+	requireAuth := middleware.AuthMiddleware(repos.Sessions)
+	userRateLimit := middleware.RateLimitMiddleware(limiter, "user", config.AppConfig.RateLimitUser)
 
-	// Global rate limiting
-	r.Use(middleware.RateLimitMiddleware(100, time.Minute))
+	// Panic recovery, request IDs, tracing and structured access logs
+	r.Use(middleware.RecoveryMiddleware(), middleware.RequestIDMiddleware(), middleware.TracingMiddleware(), middleware.LoggerMiddleware())
 
-	// ==========================================
-	// 1. AUTH ROUTES
-	// ==========================================
-	auth := r.Group("/auth")
-	auth.Use(middleware.StrictRateLimitMiddleware())
+	// Request metrics, before rate limiting so rejected requests are counted too
+	r.Use(middleware.MetricsMiddleware())
+
+	// CORS allowlist and browser security headers
+	r.Use(middleware.CORSMiddleware(middleware.CORSOptions{
+		AllowedOrigins:   config.AppConfig.CORSAllowedOrigins,
+		AllowedMethods:   config.AppConfig.CORSAllowedMethods,
+		AllowCredentials: config.AppConfig.CORSAllowCredentials,
+		MaxAge:           time.Duration(config.AppConfig.CORSMaxAgeSeconds) * time.Second,
+	}))
+	r.Use(middleware.SecurityHeadersMiddleware(middleware.SecurityHeaderOptions{
+		HSTSMaxAge:            config.AppConfig.HSTSMaxAgeSeconds,
+		HSTSIncludeSubdomains: config.AppConfig.HSTSIncludeSubdomains,
+		ContentSecurityPolicy: config.AppConfig.ContentSecurityPolicy,
+	}))
+
+	// Global rate limiting per client IP; authenticated routes add a per-user limit below
+	r.Use(middleware.RateLimitMiddleware(limiter, "global", config.AppConfig.RateLimitGlobal))
+
+	mw := apiMiddleware{
+		requireAuth:   requireAuth,
+		userRateLimit: userRateLimit,
+		authRateLimit: middleware.RateLimitMiddleware(limiter, "auth", config.AppConfig.RateLimitAuth),
+		defaultBody:   middleware.BodyLimitMiddleware(kilobytes(config.AppConfig.BodyLimitDefaultKB)),
+		authBody:      middleware.BodyLimitMiddleware(kilobytes(config.AppConfig.BodyLimitAuthKB)),
+		forumBody:     middleware.BodyLimitMiddleware(kilobytes(config.AppConfig.BodyLimitForumKB)),
+		importBody:    middleware.BodyLimitMiddleware(kilobytes(config.AppConfig.BodyLimitImportKB)),
+	}
+
+	// Current API. A future /v2 registers the same table with overrides for the handlers that change:
+	//   registerAPI(newRouter(r.Group("/v2"), overrides{"GET /health": h.HealthV2.List}), h, mw)
+	registerAPI(newRouter(r.Group("/v1"), nil), h, mw)
+
+	// Pre-versioning root paths (/auth/login, /health, ...) stay as deprecated aliases of /v1
+	if config.AppConfig.LegacyRoutesEnabled {
+		legacy := r.Group("", middleware.DeprecationMiddleware(middleware.DeprecationOptions{
+			DeprecatedAt:    config.AppConfig.LegacyRoutesDeprecatedAt,
+			Sunset:          config.AppConfig.LegacyRoutesSunset,
+			SuccessorPrefix: "/v1",
+		}))
+		registerAPI(newRouter(legacy, nil), h, mw)
+	}
+
+	// Health checks: liveness never touches the database, readiness does
+	r.GET("/server-status", h.Probe.ServerStatus)
+	r.GET("/livez", h.Probe.Livez)
+	r.GET("/readyz", h.Probe.Readyz)
+
+	// Prometheus scrape endpoint, opt-in because it reveals traffic and usage figures
+	if config.AppConfig.MetricsEnabled {
+		if config.AppConfig.MetricsToken == "" {
+			slog.Warn("/metrics is enabled without METRICS_TOKEN; keep it reachable from the internal network only")
+		}
+		r.GET("/metrics", middleware.MetricsAuthMiddleware(config.AppConfig.MetricsToken), gin.WrapH(metrics.Handler()))
+	}
+
+	// OpenAPI document and docs viewer, built once every route above is registered
+	var doc *openapi.Document
+	r.GET("/openapi.json", openapi.SpecHandler(func() *openapi.Document { return doc }))
+	openapi.RegisterUI(r, "/docs")
+
+	doc, undocumented := BuildOpenAPI(r)
+	if len(undocumented) > 0 {
+		slog.Warn("routes missing from the OpenAPI document", "routes", undocumented)
+	}
+
+	// Unknown paths and methods get the same JSON envelope as every other error
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(func(c *gin.Context) {
+		utils.ErrorResponse(c, http.StatusNotFound, "Route not found")
+	})
+	r.NoMethod(func(c *gin.Context) {
+		utils.ErrorResponse(c, http.StatusMethodNotAllowed, "Method not allowed")
+	})
+}
+
+// apiMiddleware is shared by every API version so limits apply across versions
+type apiMiddleware struct {
+	requireAuth   gin.HandlerFunc
+	userRateLimit gin.HandlerFunc
+	authRateLimit gin.HandlerFunc
+	// Request body caps per route group
+	defaultBody gin.HandlerFunc
+	authBody    gin.HandlerFunc
+	forumBody   gin.HandlerFunc
+	importBody  gin.HandlerFunc
+}
+
+// registerAPI registers the versioned API routes on api
+func registerAPI(api *router, h *handlers.Handlers, mw apiMiddleware) {
+	// Public auth routes, with a stricter per-IP limit against credential stuffing
+	auth := api.Group("/auth", mw.authRateLimit, mw.authBody)
+	{
+		auth.POST("/register", h.Auth.Register)
+		auth.POST("/login", h.Auth.Login)
+		auth.POST("/refresh", h.Auth.RefreshToken)
+		auth.POST("/2fa/verify", h.Auth.VerifyTwoFactor)
+		auth.POST("/forgot-password", h.Auth.ForgotPassword)
+		auth.POST("/reset-password", h.Auth.ResetPassword)
+	}
+
+	// Articles routes
+	articles := api.Group("/articles")
 	{
-		auth.POST("/register", handlers.Register)
-		auth.POST("/login", handlers.Login)
-		auth.POST("/reset-password", handlers.ResetPassword)
+		articles.GET("", h.Article.GetArticles)
+		articles.GET("/categories", h.Article.GetArticleCategories)
+		articles.GET("/search", h.Article.SearchArticles)
+		articles.GET("/:id", h.Article.GetArticle)
 	}
 
-	// ==========================================
-	// 2. MAIN ROUTES (PENTING: Ubah /api menjadi /)
-	// ==========================================
-	// Dengan mengganti "/api" menjadi "/", fitur health bisa diakses langsung
-	api := r.Group("/")
+	// Protected routes
+	protected := api.Group("", mw.requireAuth, mw.userRateLimit, mw.defaultBody)
 	{
-		// Articles routes
-		articles := api.Group("/articles")
+		// User routes
+		protected.GET("/auth/me", h.Auth.GetCurrentUser)
+		protected.PUT("/auth/profile", h.Auth.UpdateProfile)
+		protected.POST("/auth/logout", h.Auth.Logout)
+		protected.POST("/auth/logout-all", h.Auth.LogoutAll)
+		protected.GET("/auth/login-history", h.Auth.GetLoginHistory)
+		protected.DELETE("/auth/account", h.Account.DeleteAccount)
+
+		// Two-factor authentication routes
+		twoFactor := protected.Group("/auth/2fa")
+		{
+			twoFactor.POST("/setup", h.Auth.SetupTwoFactor)
+			twoFactor.POST("/confirm", h.Auth.ConfirmTwoFactor)
+			twoFactor.POST("/recovery-codes", h.Auth.RegenerateRecoveryCodes)
+			twoFactor.POST("/disable", h.Auth.DisableTwoFactor)
+		}
+
+		// Personal data export & import
+		account := protected.Group("/account")
+		{
+			account.GET("/export", h.Account.ExportData)
+			account.GET("/export/jobs/:id", h.Account.GetExportJob)
+		}
+
+		// Health data routes
+		health := protected.Group("/health")
+		{
+			health.POST("", h.Health.CreateHealthData)
+			health.GET("", h.Health.GetHealthData)
+			health.GET("/latest", h.Health.GetLatestHealthData)
+			health.GET("/dashboard", h.Health.GetDashboard)
+			health.GET("/graph/:period", h.Health.GetHealthGraph)
+			health.GET("/insights", h.Health.GetInsights)
+			health.PUT("/insights/:id/read", h.Health.MarkInsightRead)
+			health.PUT("/:id", h.Health.UpdateHealthData)
+			health.DELETE("/:id", h.Health.DeleteHealthData)
+		}
+
+		// Vital sign measurement routes
+		measurements := protected.Group("/measurements")
+		{
+			measurements.GET("/types", h.Measurement.GetMeasurementTypes)
+			measurements.GET("", h.Measurement.GetMeasurements)
+			measurements.POST("", h.Measurement.CreateMeasurement)
+			measurements.GET("/latest", h.Measurement.GetLatestMeasurements)
+			measurements.DELETE("/:id", h.Measurement.DeleteMeasurement)
+		}
+
+		// Symptom routes
+		symptoms := protected.Group("/symptoms")
 		{
-			articles.GET("", handlers.GetArticles)
-			articles.GET("/categories", handlers.GetArticleCategories)
-			articles.GET("/search", handlers.SearchArticles)
-			articles.GET("/:id", handlers.GetArticle)
+			symptoms.GET("/list", h.Symptom.GetSymptomList)
+			symptoms.POST("", h.Symptom.LogSymptom)
+			symptoms.POST("/batch", h.Symptom.LogMultipleSymptoms)
+			symptoms.GET("/history", h.Symptom.GetSymptomHistory)
+			symptoms.GET("/stats", h.Symptom.GetSymptomStats)
 		}
 
-		// Protected routes
-		protected := api.Group("")
-		protected.Use(middleware.AuthMiddleware())
+		// Family routes
+		family := protected.Group("/family")
 		{
-			// User routes
-			protected.GET("/auth/me", handlers.GetCurrentUser)
-			protected.PUT("/auth/profile", handlers.UpdateProfile)
-
-			// Health data routes (Ini yang error 404 tadi)
-			health := protected.Group("/health")
-			{
-				health.POST("", handlers.CreateHealthData)
-				health.GET("", handlers.GetHealthData)
-				health.GET("/latest", handlers.GetLatestHealthData)
-				health.GET("/dashboard", handlers.GetDashboard)
-				health.GET("/graph/:period", handlers.GetHealthGraph)
-			}
-
-			// Symptom routes
-			symptoms := protected.Group("/symptoms")
-			{
-				symptoms.GET("/list", handlers.GetSymptomList)
-				symptoms.POST("", handlers.LogSymptom)
-				symptoms.POST("/batch", handlers.LogMultipleSymptoms)
-				symptoms.GET("/history", handlers.GetSymptomHistory)
-				symptoms.GET("/stats", handlers.GetSymptomStats)
-			}
-
-			// Family routes
-			family := protected.Group("/family")
-			{
-				family.POST("/invite", handlers.InviteFamilyMember)
-				family.GET("/members", handlers.GetFamilyMembers)
-				family.GET("/requests", handlers.GetFamilyRequests)
-				family.PUT("/approve/:id", handlers.ApproveFamilyRequest)
-				family.PUT("/reject/:id", handlers.RejectFamilyRequest)
-				family.GET("/:id/health", handlers.GetFamilyMemberHealth)
-				family.DELETE("/:id", handlers.RemoveFamilyMember)
-			}
-
-			// Recommendation routes
-			recommendations := protected.Group("/recommendations")
-			{
-				recommendations.GET("/food", handlers.GetFoodRecommendations)
-				recommendations.GET("/exercise", handlers.GetExerciseRecommendations)
-				recommendations.GET("/emotional", handlers.GetEmotionalRecommendations)
-				recommendations.GET("/daily-menu", handlers.GetDailyMenu)
-			}
-
-			// Forum routes
-			forum := protected.Group("/forum")
-			{
-				forum.GET("/posts", handlers.GetPosts)
-				forum.POST("/posts", handlers.CreatePost)
-				forum.GET("/posts/:id", handlers.GetPost)
-				forum.DELETE("/posts/:id", handlers.DeletePost)
-				forum.POST("/posts/:id/comments", handlers.AddComment)
-				forum.POST("/posts/:id/like", handlers.ToggleLike)
-			}
-
-			// Water tracker routes
-			water := protected.Group("/water")
-			{
-				water.GET("", handlers.GetWaterIntake)
-				water.POST("/add", handlers.AddWaterGlass)
-				water.POST("/remove", handlers.RemoveWaterGlass)
-				water.PUT("/goal", handlers.UpdateWaterGoal)
-				water.GET("/history", handlers.GetWaterHistory)
-			}
-
-			// Goals routes
-			goals := protected.Group("/goals")
-			{
-				goals.GET("", handlers.GetGoals)
-				goals.POST("", handlers.CreateGoal)
-				goals.PUT("/:id/progress", handlers.UpdateGoalProgress)
-				goals.PUT("/:id/toggle", handlers.ToggleGoalComplete)
-				goals.DELETE("/:id", handlers.DeleteGoal)
-				goals.GET("/stats", handlers.GetGoalStats)
-			}
-
-			// Reminders routes
-			reminders := protected.Group("/reminders")
-			{
-				reminders.GET("", handlers.GetReminders)
-				reminders.POST("", handlers.CreateReminder)
-				reminders.PUT("/:id", handlers.UpdateReminder)
-				reminders.DELETE("/:id", handlers.DeleteReminder)
-				reminders.PUT("/:id/toggle", handlers.ToggleReminder)
-			}
+			family.POST("/invite", h.Family.InviteFamilyMember)
+			family.GET("/members", h.Family.GetFamilyMembers)
+			family.GET("/requests", h.Family.GetFamilyRequests)
+			family.PUT("/approve/:id", h.Family.ApproveFamilyRequest)
+			family.PUT("/reject/:id", h.Family.RejectFamilyRequest)
+			family.GET("/:id/health", h.Family.GetFamilyMemberHealth)
+			family.DELETE("/:id", h.Family.RemoveFamilyMember)
+		}
+
+		// Recommendation routes
+		recommendations := protected.Group("/recommendations")
+		{
+			recommendations.GET("/food", h.Recommendation.GetFoodRecommendations)
+			recommendations.GET("/exercise", h.Recommendation.GetExerciseRecommendations)
+			recommendations.GET("/emotional", h.Recommendation.GetEmotionalRecommendations)
+			recommendations.GET("/daily-menu", h.Recommendation.GetDailyMenu)
+		}
+
+		// Water tracker routes
+		water := protected.Group("/water")
+		{
+			water.GET("", h.Water.GetWaterIntake)
+			water.POST("/add", h.Water.AddWaterGlass)
+			water.POST("/remove", h.Water.RemoveWaterGlass)
+			water.PUT("/goal", h.Water.UpdateWaterGoal)
+			water.GET("/history", h.Water.GetWaterHistory)
+		}
+
+		// Goals routes
+		goals := protected.Group("/goals")
+		{
+			goals.GET("", h.Goal.GetGoals)
+			goals.POST("", h.Goal.CreateGoal)
+			goals.PUT("/:id/progress", h.Goal.UpdateGoalProgress)
+			goals.PUT("/:id/toggle", h.Goal.ToggleGoalComplete)
+			goals.DELETE("/:id", h.Goal.DeleteGoal)
+			goals.GET("/stats", h.Goal.GetGoalStats)
+		}
+
+		// Reminders routes
+		reminders := protected.Group("/reminders")
+		{
+			reminders.GET("", h.Reminder.GetReminders)
+			reminders.POST("", h.Reminder.CreateReminder)
+			reminders.PUT("/:id", h.Reminder.UpdateReminder)
+			reminders.DELETE("/:id", h.Reminder.DeleteReminder)
+			reminders.PUT("/:id/toggle", h.Reminder.ToggleReminder)
 		}
 	}
 
-	// Health check
-	r.GET("/server-status", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok", "message": "Health Tracker API is running"})
-	})
+	// Admin routes
+	admin := api.Group("/admin", mw.requireAuth, mw.userRateLimit, mw.defaultBody, middleware.RequireRole(models.RoleModerator, models.RoleAdmin))
+	{
+		admin.GET("/users", h.Admin.AdminListUsers)
+		admin.GET("/users/:id", h.Admin.AdminGetUser)
+		admin.PUT("/users/:id/suspend", h.Admin.AdminSuspendUser)
+		admin.PUT("/users/:id/unsuspend", h.Admin.AdminUnsuspendUser)
+		admin.PUT("/users/:id/role", middleware.RequireRole(models.RoleAdmin), h.Admin.AdminUpdateUserRole)
+	}
+
+	// Forum routes, outside the protected group so their own body limit replaces the default
+	// instead of nesting under it
+	forum := api.Group("/forum", mw.requireAuth, mw.userRateLimit, mw.forumBody)
+	{
+		forum.GET("/posts", h.Forum.GetPosts)
+		forum.POST("/posts", h.Forum.CreatePost)
+		forum.GET("/posts/:id", h.Forum.GetPost)
+		forum.DELETE("/posts/:id", h.Forum.DeletePost)
+		forum.POST("/posts/:id/comments", h.Forum.AddComment)
+		forum.POST("/posts/:id/like", h.Forum.ToggleLike)
+	}
+
+	// Archive uploads need far more room than the default body limit, which would cap them if nested
+	api.POST("/account/import", mw.requireAuth, mw.userRateLimit, mw.importBody, h.Account.ImportData)
+
+	// Signed, expiring download links for background exports
+	api.GET("/account/export/download/:id", h.Account.DownloadExport)
+}
 
+func kilobytes(kb int) int64 {
+	return int64(kb) << 10
 }
@@ -0,0 +1,145 @@
+package routes
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"health-tracker/config"
+	"health-tracker/handlers"
+	"health-tracker/middleware"
+	"health-tracker/ratelimit"
+	"health-tracker/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRouteBodyLimitsReplaceDefault(t *testing.T) {
+	config.LoadConfig()
+	gin.SetMode(gin.TestMode)
+
+	// The handlers only read the body, so the limits are all that can reject it
+	readBody := func(c *gin.Context) {
+		if _, err := io.ReadAll(c.Request.Body); err != nil {
+			c.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		c.Status(http.StatusOK)
+	}
+	pass := func(c *gin.Context) { c.Next() }
+	mw := apiMiddleware{
+		requireAuth:   pass,
+		userRateLimit: pass,
+		authRateLimit: pass,
+		defaultBody:   middleware.BodyLimitMiddleware(1 << 10),
+		authBody:      middleware.BodyLimitMiddleware(1 << 10),
+		forumBody:     middleware.BodyLimitMiddleware(4 << 10),
+		importBody:    middleware.BodyLimitMiddleware(8 << 10),
+	}
+	r := gin.New()
+	registerAPI(newRouter(r.Group("/v1"), overrides{
+		"POST /forum/posts":    readBody,
+		"POST /health":         readBody,
+		"POST /account/import": readBody,
+	}), handlers.New(repository.New(nil)), mw)
+
+	tests := []struct {
+		path string
+		size int
+		want int
+	}{
+		{"/v1/health", 512, http.StatusOK},
+		{"/v1/health", 2 << 10, http.StatusRequestEntityTooLarge},
+		// Between the default and the forum limit
+		{"/v1/forum/posts", 2 << 10, http.StatusOK},
+		{"/v1/forum/posts", 5 << 10, http.StatusRequestEntityTooLarge},
+		{"/v1/account/import", 6 << 10, http.StatusOK},
+		{"/v1/account/import", 9 << 10, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, bytes.NewReader(make([]byte, tt.size))))
+		if w.Code != tt.want {
+			t.Errorf("POST %s with %d bytes = %d, want %d", tt.path, tt.size, w.Code, tt.want)
+		}
+	}
+}
+
+func TestMetricsIsOptIn(t *testing.T) {
+	config.LoadConfig()
+	gin.SetMode(gin.TestMode)
+	t.Cleanup(config.LoadConfig)
+
+	tests := []struct {
+		name    string
+		enabled bool
+		token   string
+		auth    string
+		want    int
+	}{
+		{"disabled by default", false, "", "", http.StatusNotFound},
+		{"enabled without a token", true, "", "", http.StatusOK},
+		{"token missing", true, "s3cret", "", http.StatusUnauthorized},
+		{"token wrong", true, "s3cret", "Bearer nope", http.StatusUnauthorized},
+		{"token given", true, "s3cret", "Bearer s3cret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.AppConfig.MetricsEnabled = tt.enabled
+			config.AppConfig.MetricsToken = tt.token
+			r := gin.New()
+			SetupRoutes(r, repository.New(nil), ratelimit.NewMemoryStore())
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("GET /metrics = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestLegacyRoutesAreDeprecatedAliases(t *testing.T) {
+	config.LoadConfig()
+	gin.SetMode(gin.TestMode)
+	t.Cleanup(config.LoadConfig)
+
+	// Without a token the auth middleware rejects the request before any handler runs
+	tests := []struct {
+		name       string
+		legacy     bool
+		path       string
+		want       int
+		deprecated bool
+	}{
+		{"versioned route", true, "/v1/health", http.StatusUnauthorized, false},
+		{"legacy alias", true, "/health", http.StatusUnauthorized, true},
+		{"legacy alias disabled", false, "/health", http.StatusNotFound, false},
+		{"versioned route with legacy disabled", false, "/v1/health", http.StatusUnauthorized, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.AppConfig.LegacyRoutesEnabled = tt.legacy
+			r := gin.New()
+			SetupRoutes(r, repository.New(nil), ratelimit.NewMemoryStore())
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.want {
+				t.Errorf("GET %s = %d, want %d", tt.path, w.Code, tt.want)
+			}
+			if deprecated := w.Header().Get("Deprecation") != ""; deprecated != tt.deprecated {
+				t.Errorf("GET %s Deprecation header present = %v, want %v", tt.path, deprecated, tt.deprecated)
+			}
+			if tt.deprecated && w.Header().Get("Link") != `</v1/health>; rel="successor-version"` {
+				t.Errorf("GET %s Link = %q", tt.path, w.Header().Get("Link"))
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestTracesURL(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{"", "http://localhost:4318/v1/traces"},
+		{"http://collector:4318", "http://collector:4318/v1/traces"},
+		{"http://collector:4318/", "http://collector:4318/v1/traces"},
+		{"https://collector.example.com/custom/traces", "https://collector.example.com/custom/traces"},
+	}
+	for _, tt := range tests {
+		if got := tracesURL(tt.endpoint); got != tt.want {
+			t.Errorf("tracesURL(%q) = %q, want %q", tt.endpoint, got, tt.want)
+		}
+	}
+}
+
+func TestGormPluginCreatesChildSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if err := db.Exec("CREATE TABLE notes (id integer PRIMARY KEY, body text)").Error; err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	if err := db.Use(GormPlugin{}); err != nil {
+		t.Fatalf("use plugin: %v", err)
+	}
+
+	ctx, request := Tracer().Start(context.Background(), "GET /notes")
+	session := db.WithContext(ctx).Table("notes")
+	if err := session.Create(map[string]interface{}{"body": "secret"}).Error; err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	var count int64
+	if err := session.Count(&count).Error; err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	request.End()
+
+	var statements []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if strings.HasPrefix(span.Name(), "gorm.") {
+			statements = append(statements, span)
+		}
+	}
+	if len(statements) != 2 || statements[0].Name() != "gorm.create notes" || statements[1].Name() != "gorm.query notes" {
+		t.Fatalf("statement spans = %v, want gorm.create notes and gorm.query notes", statements)
+	}
+	for _, span := range statements {
+		// Both statements hang off the request, not off each other
+		if span.Parent().SpanID() != request.SpanContext().SpanID() {
+			t.Errorf("%s is not a child of the request span", span.Name())
+		}
+		for _, attr := range span.Attributes() {
+			if strings.Contains(attr.Value.Emit(), "secret") {
+				t.Errorf("%s attribute %s leaks a bound value", span.Name(), attr.Key)
+			}
+		}
+	}
+}
@@ -10,17 +10,38 @@ import (
 )
 
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
+	UserID    uint   `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role,omitempty"`
+	SessionID uint   `json:"sid"`
+	Purpose   string `json:"purpose,omitempty"` // empty for access tokens
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uint, email string) (string, error) {
-	expirationTime := time.Now().Add(time.Duration(config.AppConfig.JWTExpiryHours) * time.Hour)
+// PurposeTwoFactor marks a short-lived token that only proves the password step of a 2FA login
+const PurposeTwoFactor = "2fa"
+
+// TwoFactorChallengeTTL is how long a user has to complete the second login step
+const TwoFactorChallengeTTL = 5 * time.Minute
+
+// AccessTokenTTL returns how long an access token stays valid
+func AccessTokenTTL() time.Duration {
+	return time.Duration(config.AppConfig.AccessTokenExpiryMinutes) * time.Minute
+}
+
+// RefreshTokenTTL returns how long a refresh token stays valid
+func RefreshTokenTTL() time.Duration {
+	return time.Duration(config.AppConfig.RefreshTokenExpiryDays) * 24 * time.Hour
+}
+
+func GenerateToken(userID uint, email, role string, sessionID uint) (string, error) {
+	expirationTime := time.Now().Add(AccessTokenTTL())
 
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -38,7 +59,54 @@ func GenerateToken(userID uint, email string) (string, error) {
 	return tokenString, nil
 }
 
+// GenerateChallengeToken issues a token that can only be exchanged at the 2FA verify endpoint.
+// challengeID is carried as the token ID so the server can accept the token only once.
+func GenerateChallengeToken(userID uint, email, challengeID string) (string, error) {
+	claims := &Claims{
+		UserID:  userID,
+		Email:   email,
+		Purpose: PurposeTwoFactor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        challengeID,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TwoFactorChallengeTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "health-tracker",
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(config.AppConfig.JWTSecret))
+}
+
+// ValidateToken validates an access token
 func ValidateToken(tokenString string) (*Claims, error) {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != "" {
+		return nil, errors.New("not an access token")
+	}
+
+	return claims, nil
+}
+
+// ValidateChallengeToken validates a 2FA challenge token
+func ValidateChallengeToken(tokenString string) (*Claims, error) {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != PurposeTwoFactor || claims.ID == "" {
+		return nil, errors.New("not a 2FA challenge token")
+	}
+
+	return claims, nil
+}
+
+func parseToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
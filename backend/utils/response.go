@@ -1,14 +1,81 @@
 package utils
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 )
 
+// Error codes returned in the "code" field so clients don't have to match on messages
+const (
+	CodeBadRequest         = "BAD_REQUEST"
+	CodeValidationFailed   = "VALIDATION_FAILED"
+	CodeUnknownField       = "UNKNOWN_FIELD"
+	CodeBatchTooLarge      = "BATCH_TOO_LARGE"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeForbidden          = "FORBIDDEN"
+	CodeNotFound           = "NOT_FOUND"
+	CodeMethodNotAllowed   = "METHOD_NOT_ALLOWED"
+	CodeConflict           = "CONFLICT"
+	CodeGone               = "GONE"
+	CodePayloadTooLarge    = "PAYLOAD_TOO_LARGE"
+	CodeRateLimited        = "RATE_LIMITED"
+	CodeAccountLocked      = "ACCOUNT_LOCKED"
+	CodeAccountSuspended   = "ACCOUNT_SUSPENDED"
+	CodeInternal           = "INTERNAL_ERROR"
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+)
+
+// APIResponse is the envelope every JSON endpoint responds with
 type APIResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	Success bool         `json:"success"`
+	Message string       `json:"message,omitempty"`
+	Data    interface{}  `json:"data,omitempty"`
+	Meta    *Meta        `json:"meta,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Code    string       `json:"code,omitempty"`
+	Details []FieldError `json:"details,omitempty"`
+	// RequestID lets users quote a failed request when reporting a problem
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Meta carries information about the data rather than the data itself
+type Meta struct {
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+// Pagination describes one page of a larger result set. Page-based lists report their
+// page and totals; cursor-based lists only know whether more rows follow, and hand out
+// next_cursor to fetch them.
+type Pagination struct {
+	Limit      int    `json:"limit"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+	TotalPages *int64 `json:"total_pages,omitempty"`
+}
+
+// NewPagination builds pagination metadata for a page of `limit` items out of `total`
+func NewPagination(page, limit int, total int64) *Pagination {
+	totalPages := (total + int64(limit) - 1) / int64(limit)
+	return &Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      &total,
+		TotalPages: &totalPages,
+		HasMore:    int64(page) < totalPages,
+	}
+}
+
+// NewCursorPagination builds pagination metadata for a keyset page; nextCursor is empty
+// on the last page
+func NewCursorPagination(limit int, nextCursor string) *Pagination {
+	return &Pagination{
+		Limit:      limit,
+		HasMore:    nextCursor != "",
+		NextCursor: nextCursor,
+	}
 }
 
 func SuccessResponse(c *gin.Context, statusCode int, message string, data interface{}) {
@@ -19,9 +86,57 @@ func SuccessResponse(c *gin.Context, statusCode int, message string, data interf
 	})
 }
 
+// PaginatedResponse returns one page of a list together with its pagination metadata
+func PaginatedResponse(c *gin.Context, message string, data interface{}, pagination *Pagination) {
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+		Meta:    &Meta{Pagination: pagination},
+	})
+}
+
+// ErrorResponse responds with the default error code for the status
 func ErrorResponse(c *gin.Context, statusCode int, message string) {
+	ErrorResponseWithCode(c, statusCode, codeForStatus(statusCode), message)
+}
+
+// ErrorResponseWithCode responds with a specific error code, for errors clients handle differently
+func ErrorResponseWithCode(c *gin.Context, statusCode int, code, message string) {
 	c.JSON(statusCode, APIResponse{
-		Success: false,
-		Error:   message,
+		Success:   false,
+		Error:     message,
+		Code:      code,
+		RequestID: c.GetString("requestID"),
 	})
 }
+
+// codeForStatus maps HTTP statuses to their generic error code
+func codeForStatus(statusCode int) string {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusGone:
+		return CodeGone
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	}
+	if statusCode >= 500 {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// respond runs fn against a test context carrying a request ID and decodes the envelope
+func respond(t *testing.T, fn func(c *gin.Context)) (int, APIResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	c.Set("requestID", "req-1")
+	fn(c)
+
+	var body APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not JSON: %v\n%s", err, w.Body)
+	}
+	return w.Code, body
+}
+
+func TestErrorResponseCodes(t *testing.T) {
+	tests := []struct {
+		status int
+		code   string
+	}{
+		{http.StatusBadRequest, CodeBadRequest},
+		{http.StatusUnauthorized, CodeUnauthorized},
+		{http.StatusNotFound, CodeNotFound},
+		{http.StatusConflict, CodeConflict},
+		{http.StatusTooManyRequests, CodeRateLimited},
+		{http.StatusUnprocessableEntity, CodeBadRequest},
+		{http.StatusInternalServerError, CodeInternal},
+		{http.StatusBadGateway, CodeInternal},
+		{http.StatusServiceUnavailable, CodeServiceUnavailable},
+	}
+	for _, tt := range tests {
+		status, body := respond(t, func(c *gin.Context) { ErrorResponse(c, tt.status, "Nope") })
+		want := APIResponse{Error: "Nope", Code: tt.code, RequestID: "req-1"}
+		if status != tt.status || !reflect.DeepEqual(body, want) {
+			t.Errorf("ErrorResponse(%d) = %d %+v, want %+v", tt.status, status, body, want)
+		}
+	}
+
+	status, body := respond(t, func(c *gin.Context) {
+		ErrorResponseWithCode(c, http.StatusForbidden, CodeAccountSuspended, "Suspended")
+	})
+	if status != http.StatusForbidden || body.Code != CodeAccountSuspended {
+		t.Errorf("ErrorResponseWithCode = %d %+v", status, body)
+	}
+}
+
+func TestSuccessEnvelopes(t *testing.T) {
+	status, body := respond(t, func(c *gin.Context) { SuccessResponse(c, http.StatusCreated, "Created", map[string]int{"id": 1}) })
+	if status != http.StatusCreated || !body.Success || body.Message != "Created" || body.Meta != nil || body.RequestID != "" {
+		t.Errorf("SuccessResponse = %d %+v", status, body)
+	}
+
+	_, body = respond(t, func(c *gin.Context) { PaginatedResponse(c, "Listed", []int{}, NewCursorPagination(20, "abc")) })
+	if body.Meta == nil || !reflect.DeepEqual(*body.Meta.Pagination, Pagination{Limit: 20, HasMore: true, NextCursor: "abc"}) {
+		t.Errorf("PaginatedResponse meta = %+v", body.Meta)
+	}
+}
+
+func TestNewPagination(t *testing.T) {
+	tests := []struct {
+		page, limit       int
+		total, totalPages int64
+		hasMore           bool
+	}{
+		{1, 10, 0, 0, false},
+		{1, 10, 10, 1, false},
+		{1, 10, 11, 2, true},
+		{2, 10, 11, 2, false},
+		{5, 10, 11, 2, false},
+	}
+	for _, tt := range tests {
+		p := NewPagination(tt.page, tt.limit, tt.total)
+		if *p.Total != tt.total || *p.TotalPages != tt.totalPages || p.HasMore != tt.hasMore {
+			t.Errorf("NewPagination(%d, %d, %d) = total_pages %d, has_more %v", tt.page, tt.limit, tt.total, *p.TotalPages, p.HasMore)
+		}
+	}
+}
+
+func TestValidationErrorResponse(t *testing.T) {
+	ConfigureValidator()
+	type item struct {
+		Name string `json:"name" binding:"required,max=3"`
+	}
+	type request struct {
+		Email string `json:"email" binding:"required,email"`
+		Items []item `json:"items" binding:"dive"`
+	}
+	bindErr := func(body string) error {
+		var req request
+		return binding.JSON.BindBody([]byte(body), &req)
+	}
+	strictErr := func(body string) error {
+		dec := json.NewDecoder(strings.NewReader(body))
+		dec.DisallowUnknownFields()
+		var req request
+		return dec.Decode(&req)
+	}
+	tooLargeErr := func() error {
+		w := httptest.NewRecorder()
+		_, err := io.ReadAll(http.MaxBytesReader(w, io.NopCloser(bytes.NewReader(make([]byte, 10))), 4))
+		return err
+	}
+
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		details []FieldError
+	}{
+		{
+			name:   "field rules",
+			err:    bindErr(`{"email":"nope","items":[{"name":"ok"},{"name":"long"}]}`),
+			status: http.StatusBadRequest,
+			code:   CodeValidationFailed,
+			details: []FieldError{
+				{Field: "email", Rule: "email", Message: "must be a valid email address"},
+				{Field: "items[1].name", Rule: "max", Param: "3", Message: "must be at most 3 characters"},
+			},
+		},
+		{
+			name:    "wrong type",
+			err:     bindErr(`{"email":1}`),
+			status:  http.StatusBadRequest,
+			code:    CodeValidationFailed,
+			details: []FieldError{{Field: "email", Rule: "type", Param: "string", Message: "must be of type string"}},
+		},
+		{
+			name:    "unknown field",
+			err:     strictErr(`{"email":"a@b.co","admin":true}`),
+			status:  http.StatusBadRequest,
+			code:    CodeUnknownField,
+			details: []FieldError{{Field: "admin", Rule: "unknown", Message: "is not accepted by this endpoint"}},
+		},
+		{name: "empty body", err: strictErr(""), status: http.StatusBadRequest, code: CodeBadRequest},
+		{name: "too large", err: tooLargeErr(), status: http.StatusRequestEntityTooLarge, code: CodePayloadTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := respond(t, func(c *gin.Context) { ValidationErrorResponse(c, tt.err) })
+			if status != tt.status || body.Code != tt.code || body.RequestID != "req-1" {
+				t.Errorf("got %d %+v, want %d %s", status, body, tt.status, tt.code)
+			}
+			if !reflect.DeepEqual(body.Details, tt.details) {
+				t.Errorf("details = %+v, want %+v", body.Details, tt.details)
+			}
+		})
+	}
+}
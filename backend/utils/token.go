@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"health-tracker/config"
+)
+
+// GenerateRandomToken returns a URL-safe random string built from n random bytes
+func GenerateRandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex SHA-256 digest used to store opaque tokens
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// SignValue returns a hex HMAC-SHA256 of value keyed with the JWT secret,
+// used for links that must work without an Authorization header
+func SignValue(value string) string {
+	mac := hmac.New(sha256.New, []byte(config.AppConfig.JWTSecret))
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignedValue checks a signature produced by SignValue in constant time
+func VerifySignedValue(value, signature string) bool {
+	return hmac.Equal([]byte(SignValue(value)), []byte(signature))
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 key from RFC 6238 appendix B, "12345678901234567890", in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeMatchesRFC6238(t *testing.T) {
+	// The RFC lists 8-digit codes; 6-digit codes are their last six digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		code, err := TOTPCode(rfcSecret, TOTPStep(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("TOTPCode at %d: %v", tt.unix, err)
+		}
+		if code != tt.code {
+			t.Errorf("code at %d = %s, want %s", tt.unix, code, tt.code)
+		}
+	}
+
+	// Secrets are accepted in lower case and with surrounding spaces
+	if code, _ := TOTPCode(" gezdgnbvgy3tqojqgezdgnbvgy3tqojq ", 1); code != "287082" {
+		t.Errorf("normalized secret code = %s, want 287082", code)
+	}
+	if _, err := TOTPCode("not base32!", 1); err == nil {
+		t.Error("invalid secret accepted")
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := TOTPStep(now)
+	codeAt := func(step int64) string {
+		code, err := TOTPCode(rfcSecret, step)
+		if err != nil {
+			t.Fatalf("TOTPCode: %v", err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		lastStep int64
+		step     int64
+		ok       bool
+	}{
+		{"current step", codeAt(current), 0, current, true},
+		{"one step behind", codeAt(current - 1), 0, current - 1, true},
+		{"one step ahead", codeAt(current + 1), 0, current + 1, true},
+		{"two steps behind", codeAt(current - 2), 0, 0, false},
+		{"two steps ahead", codeAt(current + 2), 0, 0, false},
+		{"surrounding spaces", " " + codeAt(current) + " ", 0, current, true},
+		{"already used", codeAt(current), current, 0, false},
+		{"older than the last used step", codeAt(current - 1), current, 0, false},
+		{"newer than the last used step", codeAt(current + 1), current, current + 1, true},
+		{"wrong length", codeAt(current)[:5], 0, 0, false},
+		{"wrong code", "000000", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := validateTOTPAt(rfcSecret, tt.code, tt.lastStep, now)
+			if ok != tt.ok || step != tt.step {
+				t.Errorf("validate = %d, %v; want %d, %v", step, ok, tt.step, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes why one request field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// ConfigureValidator makes validation errors report JSON field names instead of Go struct fields
+func ConfigureValidator() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+}
+
+// unknownFieldPrefix starts the error encoding/json returns when DisallowUnknownFields rejects a field
+const unknownFieldPrefix = "json: unknown field "
+
+// ValidationErrorResponse turns a binding error into a VALIDATION_FAILED response with one
+// entry per rejected field, PAYLOAD_TOO_LARGE or UNKNOWN_FIELD for bodies rejected while
+// decoding, or a BAD_REQUEST when the body isn't valid JSON at all
+func ValidationErrorResponse(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		PayloadTooLargeResponse(c, tooLarge.Limit)
+		return
+	}
+
+	if field, ok := strings.CutPrefix(err.Error(), unknownFieldPrefix); ok {
+		field = strings.Trim(field, `"`)
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success:   false,
+			Error:     "Unknown field " + field,
+			Code:      CodeUnknownField,
+			Details:   []FieldError{{Field: field, Rule: "unknown", Message: "is not accepted by this endpoint"}},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	details := ValidationDetails(err)
+	if details == nil {
+		message := "Invalid request body"
+		if errors.Is(err, io.EOF) {
+			message = "Request body is required"
+		}
+		ErrorResponseWithCode(c, http.StatusBadRequest, CodeBadRequest, message)
+		return
+	}
+
+	FieldErrorResponse(c, details...)
+}
+
+// FieldErrorResponse responds VALIDATION_FAILED for checks binding tags can't express,
+// such as rules that depend on another field
+func FieldErrorResponse(c *gin.Context, details ...FieldError) {
+	c.JSON(http.StatusBadRequest, APIResponse{
+		Success:   false,
+		Error:     "Validation failed",
+		Code:      CodeValidationFailed,
+		Details:   details,
+		RequestID: c.GetString("requestID"),
+	})
+}
+
+// PayloadTooLargeResponse rejects a request body larger than limit bytes
+func PayloadTooLargeResponse(c *gin.Context, limit int64) {
+	ErrorResponseWithCode(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge,
+		"Request body exceeds the limit of "+strconv.FormatInt(limit, 10)+" bytes")
+}
+
+// ValidationDetails extracts field errors from validator and JSON type errors, or returns nil
+func ValidationDetails(err error) []FieldError {
+	// Arrays are validated element by element; gin only keeps the failing elements' errors
+	var sliceErrs binding.SliceValidationError
+	if errors.As(err, &sliceErrs) {
+		var details []FieldError
+		for _, elemErr := range sliceErrs {
+			details = append(details, ValidationDetails(elemErr)...)
+		}
+		return details
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		details := make([]FieldError, len(validationErrs))
+		for i, fe := range validationErrs {
+			details[i] = FieldError{
+				Field:   fieldPath(fe),
+				Rule:    fe.Tag(),
+				Param:   fe.Param(),
+				Message: ruleMessage(fe.Tag(), fe.Param(), fe.Kind()),
+			}
+		}
+		return details
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Param:   typeErr.Type.String(),
+			Message: "must be of type " + typeErr.Type.String(),
+		}}
+	}
+
+	return nil
+}
+
+// fieldPath drops the top-level struct name from the namespace ("Request.items[0].name" -> "items[0].name")
+func fieldPath(fe validator.FieldError) string {
+	if _, path, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return path
+	}
+	return fe.Field()
+}
+
+// lengthUnit qualifies min/max/len on strings and collections, which count characters or items
+func lengthUnit(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
+	}
+	return ""
+}
+
+func ruleMessage(rule, param string, kind reflect.Kind) string {
+	switch rule {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min", "gte":
+		return "must be at least " + param + lengthUnit(kind)
+	case "max", "lte":
+		return "must be at most " + param + lengthUnit(kind)
+	case "gt":
+		return "must be greater than " + param
+	case "lt":
+		return "must be less than " + param
+	case "len":
+		return "must have length " + param + lengthUnit(kind)
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(param, " ", ", ")
+	case "numeric", "number":
+		return "must be a number"
+	case "datetime":
+		return "must be a date in the format " + param
+	}
+	return "failed the " + rule + " rule"
+}
@@ -15,7 +15,7 @@ const WaterTracker = () => {
     const fetchWater = async () => {
         try {
             const res = await waterAPI.get();
-            setWater(res.data);
+            setWater(res.data.data);
         } catch (error) {
             console.error('Failed to fetch water:', error);
         } finally {
@@ -28,7 +28,7 @@ const WaterTracker = () => {
         setUpdating(true);
         try {
             const res = await waterAPI.addGlass();
-            setWater(res.data);
+            setWater(res.data.data);
         } catch (error) {
             console.error('Failed to add glass:', error);
         } finally {
@@ -41,7 +41,7 @@ const WaterTracker = () => {
         setUpdating(true);
         try {
             const res = await waterAPI.removeGlass();
-            setWater(res.data);
+            setWater(res.data.data);
         } catch (error) {
             console.error('Failed to remove glass:', error);
         } finally {
@@ -1,5 +1,5 @@
 // API Configuration
 // Change this to your deployed backend URL when deploying
-const API_BASE_URL = import.meta.env.VITE_API_URL || 'https://par-chocolate-poor-yeast.trycloudflare.com/api';
+const API_BASE_URL = import.meta.env.VITE_API_URL || 'https://par-chocolate-poor-yeast.trycloudflare.com/v1';
 
 export default API_BASE_URL;
@@ -17,7 +17,7 @@ const ArticleDetail = () => {
     const fetchArticle = async () => {
         try {
             const res = await articlesAPI.getById(id);
-            setArticle(res.data);
+            setArticle(res.data.data);
         } catch (error) {
             console.error('Failed to fetch article:', error);
         } finally {
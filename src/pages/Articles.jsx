@@ -20,7 +20,7 @@ const Articles = () => {
     const fetchCategories = async () => {
         try {
             const res = await articlesAPI.getCategories();
-            setCategories(res.data);
+            setCategories(res.data.data);
         } catch (error) {
             console.error('Failed to fetch categories:', error);
         }
@@ -30,7 +30,7 @@ const Articles = () => {
         setLoading(true);
         try {
             const res = await articlesAPI.getAll(category === 'all' ? '' : category);
-            setArticles(res.data || []);
+            setArticles(res.data.data || []);
         } catch (error) {
             console.error('Failed to fetch articles:', error);
         } finally {
@@ -53,7 +53,7 @@ const Articles = () => {
         setLoading(true);
         try {
             const res = await articlesAPI.search(searchQuery);
-            setArticles(res.data || []);
+            setArticles(res.data.data || []);
         } catch (error) {
             console.error('Search failed:', error);
         } finally {
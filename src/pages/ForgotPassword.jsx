@@ -1,12 +1,17 @@
 import { useState } from 'react';
-import { Link } from 'react-router-dom';
+import { Link, useSearchParams } from 'react-router-dom';
 import { Heart, Mail, Lock, Loader2, ArrowLeft, CheckCircle, Eye, EyeOff } from 'lucide-react';
 import axios from 'axios';
 import './Auth.css';
 
 const API_URL = import.meta.env.VITE_API_URL || 'http://localhost:8080/api';
 
+// Two steps: without a token the user asks for a reset link by email; the link in that
+// email opens this page with ?token=, where the new password is set
 const ForgotPassword = () => {
+    const [searchParams] = useSearchParams();
+    const token = searchParams.get('token');
+
     const [email, setEmail] = useState('');
     const [newPassword, setNewPassword] = useState('');
     const [confirmPassword, setConfirmPassword] = useState('');
@@ -16,7 +21,22 @@ const ForgotPassword = () => {
     const [success, setSuccess] = useState(false);
     const [error, setError] = useState('');
 
-    const handleSubmit = async (e) => {
+    const handleRequest = async (e) => {
+        e.preventDefault();
+        setError('');
+        setLoading(true);
+
+        try {
+            await axios.post(`${API_URL}/auth/forgot-password`, { email });
+            setSuccess(true);
+        } catch (err) {
+            setError(err.response?.data?.error || 'Gagal mengirim tautan reset password. Coba lagi nanti.');
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    const handleReset = async (e) => {
         e.preventDefault();
         setError('');
 
@@ -36,12 +56,12 @@ const ForgotPassword = () => {
 
         try {
             await axios.post(`${API_URL}/auth/reset-password`, {
-                email: email,
+                token: token,
                 new_password: newPassword
             });
             setSuccess(true);
         } catch (err) {
-            setError(err.response?.data?.error || 'Gagal reset password. Pastikan email terdaftar.');
+            setError(err.response?.data?.error || 'Gagal reset password. Tautan mungkin sudah kedaluwarsa.');
         } finally {
             setLoading(false);
         }
@@ -73,10 +93,26 @@ const ForgotPassword = () => {
                         <span>Live for Health</span>
                     </div>
                     <h1>Reset Password</h1>
-                    <p>Masukkan email dan password baru Anda</p>
+                    <p>{token ? 'Masukkan password baru Anda' : 'Masukkan email akun Anda'}</p>
                 </div>
 
-                {success ? (
+                {success && !token ? (
+                    <div className="auth-form">
+                        <div className="success-message">
+                            <CheckCircle size={32} style={{ marginBottom: 12 }} />
+                            <p>Periksa email Anda</p>
+                            <p style={{ fontSize: 13, marginTop: 8, opacity: 0.8 }}>
+                                Jika email terdaftar, tautan reset password telah dikirim ke {email}.
+                            </p>
+                        </div>
+                        <div className="back-to-login" style={{ marginTop: 24 }}>
+                            <Link to="/login">
+                                <ArrowLeft size={18} />
+                                Kembali ke Login
+                            </Link>
+                        </div>
+                    </div>
+                ) : success ? (
                     <div className="auth-form">
                         <div className="success-message">
                             <CheckCircle size={32} style={{ marginBottom: 12 }} />
@@ -93,68 +129,74 @@ const ForgotPassword = () => {
                         </div>
                     </div>
                 ) : (
-                    <form onSubmit={handleSubmit} className="auth-form">
+                    <form onSubmit={token ? handleReset : handleRequest} className="auth-form">
                         <div className="forgot-password-info">
                             <p>
-                                🔐 Masukkan email yang terdaftar dan password baru Anda.
+                                {token
+                                    ? '🔐 Buat password baru untuk akun Anda.'
+                                    : '🔐 Masukkan email yang terdaftar. Kami akan mengirim tautan untuk membuat password baru.'}
                             </p>
                         </div>
 
                         {error && <div className="auth-error">{error}</div>}
 
-                        <div className="input-group">
-                            <Mail className="input-icon" />
-                            <input
-                                type="email"
-                                placeholder="Email terdaftar"
-                                value={email}
-                                onChange={(e) => setEmail(e.target.value)}
-                                required
-                            />
-                        </div>
-
-                        <div className="input-group">
-                            <Lock className="input-icon" />
-                            <input
-                                type={showPassword ? "text" : "password"}
-                                placeholder="Password baru"
-                                value={newPassword}
-                                onChange={(e) => setNewPassword(e.target.value)}
-                                required
-                                minLength={6}
-                            />
-                            <button
-                                type="button"
-                                className="password-toggle"
-                                onClick={() => setShowPassword(!showPassword)}
-                                tabIndex={-1}
-                            >
-                                {showPassword ? <EyeOff size={20} /> : <Eye size={20} />}
-                            </button>
-                        </div>
-
-                        <div className="input-group">
-                            <Lock className="input-icon" />
-                            <input
-                                type={showConfirmPassword ? "text" : "password"}
-                                placeholder="Konfirmasi password baru"
-                                value={confirmPassword}
-                                onChange={(e) => setConfirmPassword(e.target.value)}
-                                required
-                                minLength={6}
-                            />
-                            <button
-                                type="button"
-                                className="password-toggle"
-                                onClick={() => setShowConfirmPassword(!showConfirmPassword)}
-                                tabIndex={-1}
-                            >
-                                {showConfirmPassword ? <EyeOff size={20} /> : <Eye size={20} />}
-                            </button>
-                        </div>
+                        {token ? (
+                            <>
+                                <div className="input-group">
+                                    <Lock className="input-icon" />
+                                    <input
+                                        type={showPassword ? "text" : "password"}
+                                        placeholder="Password baru"
+                                        value={newPassword}
+                                        onChange={(e) => setNewPassword(e.target.value)}
+                                        required
+                                        minLength={6}
+                                    />
+                                    <button
+                                        type="button"
+                                        className="password-toggle"
+                                        onClick={() => setShowPassword(!showPassword)}
+                                        tabIndex={-1}
+                                    >
+                                        {showPassword ? <EyeOff size={20} /> : <Eye size={20} />}
+                                    </button>
+                                </div>
+
+                                <div className="input-group">
+                                    <Lock className="input-icon" />
+                                    <input
+                                        type={showConfirmPassword ? "text" : "password"}
+                                        placeholder="Konfirmasi password baru"
+                                        value={confirmPassword}
+                                        onChange={(e) => setConfirmPassword(e.target.value)}
+                                        required
+                                        minLength={6}
+                                    />
+                                    <button
+                                        type="button"
+                                        className="password-toggle"
+                                        onClick={() => setShowConfirmPassword(!showConfirmPassword)}
+                                        tabIndex={-1}
+                                    >
+                                        {showConfirmPassword ? <EyeOff size={20} /> : <Eye size={20} />}
+                                    </button>
+                                </div>
+                            </>
+                        ) : (
+                            <div className="input-group">
+                                <Mail className="input-icon" />
+                                <input
+                                    type="email"
+                                    placeholder="Email terdaftar"
+                                    value={email}
+                                    onChange={(e) => setEmail(e.target.value)}
+                                    required
+                                />
+                            </div>
+                        )}
 
                         <button type="submit" className="auth-button" disabled={loading}>
-                            {loading ? <Loader2 className="spinner" /> : 'Reset Password'}
+                            {loading ? <Loader2 className="spinner" /> : token ? 'Reset Password' : 'Kirim Tautan Reset'}
                         </button>
 
                         <div className="back-to-login">
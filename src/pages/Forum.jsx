@@ -19,7 +19,7 @@ const Forum = () => {
     const fetchPosts = async () => {
         try {
             const res = await forumAPI.getPosts();
-            setPosts(res.data || []);
+            setPosts(res.data.data || []);
         } catch (error) {
             console.error('Failed to fetch posts:', error);
         } finally {
@@ -49,7 +49,7 @@ const Forum = () => {
             const res = await forumAPI.toggleLike(postId);
             setPosts(posts.map(p =>
                 p.id === postId
-                    ? { ...p, is_liked: res.data.is_liked, likes_count: res.data.likes_count }
+                    ? { ...p, is_liked: res.data.data.is_liked, likes_count: res.data.data.likes_count }
                     : p
             ));
         } catch (error) {
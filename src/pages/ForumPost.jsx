@@ -20,8 +20,8 @@ const ForumPost = () => {
     const fetchPost = async () => {
         try {
             const res = await forumAPI.getPost(id);
-            setPost(res.data.post);
-            setComments(res.data.comments || []);
+            setPost(res.data.data.post);
+            setComments(res.data.data.comments || []);
         } catch (error) {
             console.error('Failed to fetch post:', error);
         } finally {
@@ -32,7 +32,7 @@ const ForumPost = () => {
     const handleLike = async () => {
         try {
             const res = await forumAPI.toggleLike(id);
-            setPost({ ...post, is_liked: res.data.is_liked, likes_count: res.data.likes_count });
+            setPost({ ...post, is_liked: res.data.data.is_liked, likes_count: res.data.data.likes_count });
         } catch (error) {
             console.error('Failed to toggle like:', error);
         }
@@ -45,7 +45,7 @@ const ForumPost = () => {
         setSubmitting(true);
         try {
             const res = await forumAPI.addComment(id, { content: newComment });
-            setComments([...comments, res.data]);
+            setComments([...comments, res.data.data]);
             setNewComment('');
             setPost({ ...post, comments_count: post.comments_count + 1 });
         } catch (error) {
@@ -56,7 +56,7 @@ const Goals = () => {
     const fetchGoals = async () => {
         try {
             const res = await goalsAPI.getAll();
-            setGoals(res.data || []);
+            setGoals(res.data.data || []);
         } catch (error) {
             console.error('Failed to fetch goals:', error);
         } finally {
@@ -67,7 +67,7 @@ const Goals = () => {
     const fetchStats = async () => {
         try {
             const res = await goalsAPI.getStats();
-            setStats(res.data);
+            setStats(res.data.data);
         } catch (error) {
             console.error('Failed to fetch stats:', error);
         }